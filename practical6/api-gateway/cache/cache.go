@@ -0,0 +1,127 @@
+// Package cache keeps the api-gateway's responses to reads that rarely
+// change, such as the menu, for a short time.
+//
+// Entries expire after the cache's TTL and are all dropped by Purge, which
+// the gateway calls after a write to the backend the responses came from.
+// Each gateway replica has its own cache, so after a write through another
+// replica, or a change the gateway does not see, responses may be stale for
+// up to the TTL.
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long responses are kept when CACHE_TTL is unset
+const DefaultTTL = 30 * time.Second
+
+// maxEntries bounds the memory a cache uses, as list queries can vary
+// freely
+const maxEntries = 1000
+
+// TTL returns how long responses are kept from the CACHE_TTL environment
+// variable, e.g. "1m"; "0" turns caching off
+func TTL() (time.Duration, error) {
+	value := os.Getenv("CACHE_TTL")
+	if value == "" {
+		return DefaultTTL, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid CACHE_TTL %q", value)
+	}
+	return d, nil
+}
+
+// Entry is a cached response
+type Entry struct {
+	Header http.Header
+	Body   []byte
+	ETag   string
+	Stored time.Time
+}
+
+// Cache holds entries by key, usually the request URI
+type Cache struct {
+	name string
+	ttl  time.Duration
+
+	mu         sync.Mutex
+	entries    map[string]Entry
+	generation uint64
+}
+
+// New returns an empty cache keeping entries for ttl. name identifies the
+// cache in metrics, e.g. "menu".
+func New(name string, ttl time.Duration) *Cache {
+	return &Cache{name: name, ttl: ttl, entries: make(map[string]Entry)}
+}
+
+// Name returns the name the cache was created with
+func (c *Cache) Name() string {
+	return c.name
+}
+
+// TTL returns how long entries are kept; zero means the cache is off
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Get returns the entry stored under key if it has not expired by now
+func (c *Cache) Get(key string, now time.Time) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.fresh(entry, now) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Generation identifies the cache's contents. Read it before fetching a
+// response and pass it to Set, so a response fetched before a Purge is not
+// stored after it.
+func (c *Cache) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Set stores entry under key, unless the cache has been purged since
+// generation was read or is off
+func (c *Cache) Set(key string, entry Entry, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 || generation != c.generation {
+		return
+	}
+	if len(c.entries) >= maxEntries {
+		for k, e := range c.entries {
+			if !c.fresh(e, entry.Stored) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxEntries {
+			return
+		}
+	}
+	c.entries[key] = entry
+}
+
+// Purge drops every entry
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.generation++
+}
+
+func (c *Cache) fresh(entry Entry, now time.Time) bool {
+	return now.Sub(entry.Stored) < c.ttl
+}
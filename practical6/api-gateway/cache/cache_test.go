@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := Entry{Body: []byte(`[]`), ETag: `"abc"`, Stored: start}
+
+	t.Run("entries expire after the TTL", func(t *testing.T) {
+		c := New("menu", time.Minute)
+		c.Set("/api/menu", entry, c.Generation())
+
+		got, ok := c.Get("/api/menu", start.Add(59*time.Second))
+		assert.True(t, ok)
+		assert.Equal(t, entry, got)
+
+		_, ok = c.Get("/api/menu", start.Add(time.Minute))
+		assert.False(t, ok)
+	})
+
+	t.Run("purge drops every entry", func(t *testing.T) {
+		c := New("menu", time.Minute)
+		c.Set("/api/menu", entry, c.Generation())
+		c.Set("/api/menu/1", entry, c.Generation())
+		c.Purge()
+
+		_, ok := c.Get("/api/menu", start)
+		assert.False(t, ok)
+		_, ok = c.Get("/api/menu/1", start)
+		assert.False(t, ok)
+	})
+
+	t.Run("responses fetched before a purge are not stored", func(t *testing.T) {
+		c := New("menu", time.Minute)
+		generation := c.Generation()
+		c.Purge()
+		c.Set("/api/menu", entry, generation)
+
+		_, ok := c.Get("/api/menu", start)
+		assert.False(t, ok)
+	})
+
+	t.Run("a zero TTL turns the cache off", func(t *testing.T) {
+		c := New("menu", 0)
+		c.Set("/api/menu", entry, c.Generation())
+
+		_, ok := c.Get("/api/menu", start)
+		assert.False(t, ok)
+	})
+
+	t.Run("expired entries make room for new ones", func(t *testing.T) {
+		c := New("menu", time.Minute)
+		for i := range maxEntries {
+			c.Set(fmt.Sprintf("/api/menu/%d", i), entry, c.Generation())
+		}
+
+		later := entry
+		later.Stored = start.Add(30 * time.Second)
+		c.Set("/api/menu?page_size=5", later, c.Generation())
+		_, ok := c.Get("/api/menu?page_size=5", later.Stored)
+		assert.False(t, ok, "the cache is full of fresh entries")
+
+		later.Stored = start.Add(time.Minute)
+		c.Set("/api/menu?page_size=5", later, c.Generation())
+		_, ok = c.Get("/api/menu?page_size=5", later.Stored)
+		assert.True(t, ok)
+	})
+}
+
+func TestTTL(t *testing.T) {
+	t.Setenv("CACHE_TTL", "")
+	ttl, err := TTL()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTTL, ttl)
+
+	t.Setenv("CACHE_TTL", "0")
+	ttl, err = TTL()
+	assert.NoError(t, err)
+	assert.Zero(t, ttl)
+
+	t.Setenv("CACHE_TTL", "soon")
+	_, err = TTL()
+	assert.Error(t, err)
+}
@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"api-gateway/grpc"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/auth"
+)
+
+// fakeUsers serves users 1 and 2 and records each batch asked for. Like
+// user-service, it requires an authenticated caller and reports the users a
+// student may not read as missing.
+type fakeUsers struct {
+	userv1.UserServiceClient
+
+	mu      sync.Mutex
+	batches [][]uint32
+}
+
+func (f *fakeUsers) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersRequest, _ ...googlegrpc.CallOption) (*userv1.BatchGetUsersResponse, error) {
+	caller, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	f.mu.Lock()
+	f.batches = append(f.batches, slices.Sorted(slices.Values(req.Ids)))
+	f.mu.Unlock()
+
+	resp := &userv1.BatchGetUsersResponse{}
+	for _, id := range req.Ids {
+		if id > 2 || (caller.Role != auth.RoleCafeOwner && id != caller.UserID) {
+			resp.MissingIds = append(resp.MissingIds, id)
+			continue
+		}
+		resp.Users = append(resp.Users, &userv1.User{Id: id, Name: map[uint32]string{1: "Ann", 2: "Bo"}[id]})
+	}
+	return resp, nil
+}
+
+// fakeMenu serves menu items 1 to 3 and records each batch asked for
+type fakeMenu struct {
+	menuv1.MenuServiceClient
+
+	mu      sync.Mutex
+	batches [][]uint32
+}
+
+func (f *fakeMenu) BatchGetMenuItems(ctx context.Context, req *menuv1.BatchGetMenuItemsRequest, _ ...googlegrpc.CallOption) (*menuv1.BatchGetMenuItemsResponse, error) {
+	f.mu.Lock()
+	f.batches = append(f.batches, slices.Sorted(slices.Values(req.Ids)))
+	f.mu.Unlock()
+
+	resp := &menuv1.BatchGetMenuItemsResponse{}
+	for _, id := range req.Ids {
+		if id > 3 {
+			resp.MissingIds = append(resp.MissingIds, id)
+			continue
+		}
+		resp.MenuItems = append(resp.MenuItems, &menuv1.MenuItem{Id: id, Name: map[uint32]string{1: "Tea", 2: "Toast", 3: "Soup"}[id]})
+	}
+	return resp, nil
+}
+
+// fakeOrders lists three orders, by users 1, 2 and 1
+type fakeOrders struct {
+	orderv1.OrderServiceClient
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (f *fakeOrders) GetOrders(ctx context.Context, req *orderv1.GetOrdersRequest, _ ...googlegrpc.CallOption) (*orderv1.GetOrdersResponse, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+
+	item := func(menuItemID uint32) *orderv1.OrderItem {
+		return &orderv1.OrderItem{MenuItemId: menuItemID, Quantity: 1}
+	}
+	return &orderv1.GetOrdersResponse{Orders: []*orderv1.Order{
+		{Id: 1, UserId: 1, OrderItems: []*orderv1.OrderItem{item(1), item(2)}},
+		{Id: 2, UserId: 2, OrderItems: []*orderv1.OrderItem{item(2), item(3)}},
+		{Id: 3, UserId: 1, OrderItems: []*orderv1.OrderItem{item(9)}},
+	}}, nil
+}
+
+type fakes struct {
+	users  *fakeUsers
+	menu   *fakeMenu
+	orders *fakeOrders
+}
+
+func newFakes() fakes {
+	return fakes{users: &fakeUsers{}, menu: &fakeMenu{}, orders: &fakeOrders{}}
+}
+
+// response is the body of a GraphQL response
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// query posts query to the endpoint as the given caller, anonymously if nil
+func (f fakes) query(t *testing.T, caller *auth.Identity, query string) response {
+	t.Helper()
+	h := NewHandler(&grpc.ServiceClients{UserClient: f.users, MenuClient: f.menu, OrderClient: f.orders})
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if caller != nil {
+		req = req.WithContext(auth.NewContext(req.Context(), *caller))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	return resp
+}
+
+var owner = &auth.Identity{UserID: 1, Role: auth.RoleCafeOwner}
+
+func TestOrdersResolveUsersAndMenuItemsInBatches(t *testing.T) {
+	f := newFakes()
+	resp := f.query(t, owner, `{
+		orders {
+			orders {
+				id
+				user { name }
+				items { menuItem { name } }
+			}
+		}
+	}`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"orders": {"orders": [
+		{"id": "1", "user": {"name": "Ann"}, "items": [{"menuItem": {"name": "Tea"}}, {"menuItem": {"name": "Toast"}}]},
+		{"id": "2", "user": {"name": "Bo"}, "items": [{"menuItem": {"name": "Toast"}}, {"menuItem": {"name": "Soup"}}]},
+		{"id": "3", "user": {"name": "Ann"}, "items": [{"menuItem": null}]}
+	]}}`, string(resp.Data))
+
+	assert.Equal(t, [][]uint32{{1, 2}}, f.users.batches, "users are fetched in one batch")
+	assert.Equal(t, [][]uint32{{1, 2, 3, 9}}, f.menu.batches, "menu items are fetched in one batch")
+}
+
+func TestUsersRequireAuthentication(t *testing.T) {
+	f := newFakes()
+
+	resp := f.query(t, nil, `{ user(id: 1) { name } }`)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "UNAUTHENTICATED", resp.Errors[0].Extensions["code"])
+	assert.Empty(t, f.users.batches)
+
+	resp = f.query(t, nil, `{ me { name } }`)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"me": null}`, string(resp.Data))
+
+	resp = f.query(t, owner, `{ me { name } user(id: 7) { name } }`)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"me": {"name": "Ann"}, "user": null}`, string(resp.Data))
+}
+
+func TestStudentsReadThemselvesInMixedBatches(t *testing.T) {
+	f := newFakes()
+	student := &auth.Identity{UserID: 2, Role: auth.RoleStudent}
+
+	// Another user batched with the caller leaves only that user out
+	resp := f.query(t, student, `{ me { name } user(id: 1) { name } }`)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"me": {"name": "Bo"}, "user": null}`, string(resp.Data))
+	assert.Equal(t, [][]uint32{{1, 2}}, f.users.batches)
+}
+
+func TestQueryLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{
+			name:  "queries nesting too deeply are rejected",
+			query: `{ me { orders { orders { user { orders { orders { items { menuItem { price { amountMinor } } } } } } } } } }`,
+			code:  "DEPTH_LIMIT_EXCEEDED",
+		},
+		{
+			name:  "depth counts fields inside fragments",
+			query: `{ orders { ...deep } } fragment deep on OrderPage { orders { user { orders { orders { user { orders { orders { id } } } } } } } }`,
+			code:  "DEPTH_LIMIT_EXCEEDED",
+		},
+		{
+			name:  "pages of nested pages are too complex",
+			query: `{ orders { orders { user { orders { orders { id } } } } } }`,
+			code:  "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:  "large pages are too complex",
+			query: `{ orders(first: 200) { orders { id userId status createdAt updatedAt } } }`,
+			code:  "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:  "introspection is not limited by depth",
+			query: `{ __schema { types { fields { type { ofType { ofType { ofType { ofType { name } } } } } } } } }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakes()
+			resp := f.query(t, owner, tt.query)
+			if tt.code == "" {
+				assert.Empty(t, resp.Errors)
+				return
+			}
+			require.Len(t, resp.Errors, 1)
+			assert.Equal(t, tt.code, resp.Errors[0].Extensions["code"])
+			assert.Zero(t, f.orders.calls, "rejected before calling a backend")
+		})
+	}
+}
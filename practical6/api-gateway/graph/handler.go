@@ -0,0 +1,72 @@
+// Package graph serves the api-gateway's GraphQL endpoint, which resolves
+// users, menu items and orders, and the links between them, through the
+// gRPC clients in one request.
+//
+// The server is generated by gqlgen from schema.graphqls; the types are the
+// services' protobuf messages.
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"api-gateway/grpc"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/logging"
+)
+
+// NewHandler returns the GraphQL endpoint, taking queries by GET or POST.
+// Queries nesting deeper than MaxDepth or costing more than MaxComplexity
+// are rejected before any backend is called.
+func NewHandler(clients *grpc.ServiceClients) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers:  &Resolver{clients: clients},
+		Complexity: complexity(),
+	}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(depthLimit(MaxDepth))
+	srv.Use(extension.FixedComplexityLimit(MaxComplexity))
+	srv.SetErrorPresenter(presentError)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeHTTP(w, r.WithContext(withLoaders(r.Context(), clients)))
+	})
+}
+
+// presentError reports errors from the backends with their gRPC status
+// message, and the status code name (e.g. "NOT_FOUND"), google.rpc.ErrorInfo
+// reason and request ID as extensions, like the REST error bodies
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return gqlErr
+	}
+	st := grpcErr.GRPCStatus()
+	gqlErr.Message = st.Message()
+	gqlErr.Extensions = map[string]any{"code": code.Code(st.Code()).String()}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			gqlErr.Extensions["reason"] = info.Reason
+		}
+	}
+	if id := logging.RequestID(ctx); id != "" {
+		gqlErr.Extensions["request_id"] = id
+	}
+	return gqlErr
+}
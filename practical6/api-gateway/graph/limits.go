@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"student-cafe-shared/pagination"
+)
+
+const (
+	// MaxDepth is how deeply the fields of a query may nest; e.g.
+	// me { orders { orders { items { menuItem { price { amountMinor } } } } } }
+	// has a depth of 7
+	MaxDepth = 8
+	// MaxComplexity bounds a query's estimated cost: one per field, with the
+	// fields of a page's elements counted once per element it may hold
+	MaxComplexity = 1000
+)
+
+// complexity returns the cost of the paged fields, whose elements are
+// counted first times, or as many as a page holds by default. Other lists,
+// such as an order's items, are short and count once.
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Menu = func(childComplexity int, first *int32, _ *string, _ *bool) int {
+		return pageSize(first) * childComplexity
+	}
+	c.Query.Orders = func(childComplexity int, first *int32, _ *string, _ *string) int {
+		return pageSize(first) * childComplexity
+	}
+	c.User.Orders = func(childComplexity int, first *int32, _ *string, _ *string) int {
+		return pageSize(first) * childComplexity
+	}
+	return c
+}
+
+// pageSize returns how many elements a page requested with first holds at
+// most, as the services decide
+func pageSize(first *int32) int {
+	switch {
+	case first == nil || *first <= 0:
+		return pagination.DefaultPageSize
+	case *first > pagination.MaxPageSize:
+		return pagination.MaxPageSize
+	default:
+		return int(*first)
+	}
+}
+
+// depthLimit rejects operations whose fields nest deeper than its value.
+// Introspection fields are not counted, as the schema queries clients send
+// are deep but cheap.
+type depthLimit int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit(0)
+
+func (depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (depthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (limit depthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if depth := selectionDepth(op.SelectionSet); depth > int(limit) {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, limit)
+		errcode.Set(err, "DEPTH_LIMIT_EXCEEDED")
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns how deeply the fields of set nest, looking through
+// fragments. Validation has already rejected fragment cycles.
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range set {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys before fetching them. The
+// resolvers of a list's elements run concurrently, so their keys arrive
+// within it.
+const batchWait = 2 * time.Millisecond
+
+// fetchFunc fetches the values for keys. Keys with no value, e.g. deleted
+// menu items, are left out of the map.
+type fetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// loader batches and caches fetches by key for the life of one request,
+// like the dataloader pattern: the keys requested within batchWait of each
+// other, up to maxBatch of them, are fetched with a single call.
+type loader[K comparable, V any] struct {
+	ctx      context.Context
+	fetch    fetchFunc[K, V]
+	maxBatch int
+
+	mu    sync.Mutex
+	batch *batch[K, V]
+	cache map[K]*batch[K, V]
+}
+
+// batch is one fetch, done once its keys have been collected
+type batch[K comparable, V any] struct {
+	keys   []K
+	once   sync.Once
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+// newLoader returns a loader fetching with fetch in ctx, the request's
+// context, so calls carry the caller's identity and request ID
+func newLoader[K comparable, V any](ctx context.Context, maxBatch int, fetch fetchFunc[K, V]) *loader[K, V] {
+	return &loader[K, V]{ctx: ctx, fetch: fetch, maxBatch: maxBatch, cache: make(map[K]*batch[K, V])}
+}
+
+// Load returns the value for key, waiting for the batch it joins to be
+// fetched. ok is false if there is no value for key.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (value V, ok bool, err error) {
+	l.mu.Lock()
+	b, cached := l.cache[key]
+	if !cached {
+		b = l.batch
+		if b == nil {
+			b = &batch[K, V]{done: make(chan struct{})}
+			l.batch = b
+			time.AfterFunc(batchWait, func() { l.run(b) })
+		}
+		b.keys = append(b.keys, key)
+		l.cache[key] = b
+		if len(b.keys) == l.maxBatch {
+			l.batch = nil
+			go l.run(b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+	value, ok = b.values[key]
+	return value, ok, b.err
+}
+
+// run fetches b, once, after closing it to new keys; it is called when the
+// batch is full or batchWait has passed, whichever is first
+func (l *loader[K, V]) run(b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+
+		b.values, b.err = l.fetch(l.ctx, b.keys)
+		close(b.done)
+	})
+}
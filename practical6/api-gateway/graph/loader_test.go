@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"student-cafe-shared/auth"
+)
+
+func TestLoaderFetchesOncePerBatch(t *testing.T) {
+	users := &fakeUsers{}
+	ctx := auth.NewContext(context.Background(), *owner)
+	l := newLoader(ctx, 2, fetchUsers(users))
+
+	// load gets ids concurrently, as the resolvers of a list's elements do
+	load := func(ids ...uint32) map[uint32]string {
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			names = make(map[uint32]string)
+		)
+		for _, id := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				user, ok, err := l.Load(ctx, id)
+				assert.NoError(t, err)
+				mu.Lock()
+				defer mu.Unlock()
+				if ok {
+					names[id] = user.Name
+				}
+			}()
+		}
+		wg.Wait()
+		return names
+	}
+
+	// Three distinct users fill one batch of two and start another
+	assert.Equal(t, map[uint32]string{1: "Ann", 2: "Bo"}, load(1, 2, 3, 1))
+	assert.Len(t, users.batches, 2, "one BatchGetUsers call per batch")
+	assert.ElementsMatch(t, []uint32{1, 2, 3}, append(users.batches[0], users.batches[1]...))
+
+	// Users already loaded, or known to be missing, are not fetched again
+	assert.Equal(t, map[uint32]string{2: "Bo"}, load(2, 3))
+	assert.Len(t, users.batches, 2)
+}
@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"context"
+
+	"api-gateway/grpc"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+)
+
+const (
+	// maxMenuBatch is the most IDs BatchGetMenuItems accepts
+	maxMenuBatch = 200
+	// maxUserBatch is the most IDs BatchGetUsers accepts
+	maxUserBatch = 200
+)
+
+// loaders batch the lookups made while resolving one request
+type loaders struct {
+	users     *loader[uint32, *userv1.User]
+	menuItems *loader[uint32, *menuv1.MenuItem]
+}
+
+type loadersKey struct{}
+
+// withLoaders returns a copy of ctx carrying new loaders for the request it
+// belongs to
+func withLoaders(ctx context.Context, clients *grpc.ServiceClients) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		users:     newLoader(ctx, maxUserBatch, fetchUsers(clients.UserClient)),
+		menuItems: newLoader(ctx, maxMenuBatch, fetchMenuItems(clients.MenuClient)),
+	})
+}
+
+// loadersFrom returns the loaders stored in ctx by withLoaders
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// fetchMenuItems gets menu items with one BatchGetMenuItems call
+func fetchMenuItems(client menuv1.MenuServiceClient) fetchFunc[uint32, *menuv1.MenuItem] {
+	return func(ctx context.Context, ids []uint32) (map[uint32]*menuv1.MenuItem, error) {
+		resp, err := client.BatchGetMenuItems(ctx, &menuv1.BatchGetMenuItemsRequest{Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		items := make(map[uint32]*menuv1.MenuItem, len(resp.MenuItems))
+		for _, item := range resp.MenuItems {
+			items[item.Id] = item
+		}
+		return items, nil
+	}
+}
+
+// fetchUsers gets users with one BatchGetUsers call
+func fetchUsers(client userv1.UserServiceClient) fetchFunc[uint32, *userv1.User] {
+	return func(ctx context.Context, ids []uint32) (map[uint32]*userv1.User, error) {
+		resp, err := client.BatchGetUsers(ctx, &userv1.BatchGetUsersRequest{Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		users := make(map[uint32]*userv1.User, len(resp.Users))
+		for _, user := range resp.Users {
+			users[user.Id] = user
+		}
+		return users, nil
+	}
+}
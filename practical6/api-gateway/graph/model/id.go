@@ -0,0 +1,26 @@
+// Package model holds the GraphQL types not backed by a protobuf message,
+// and the ID scalar.
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// MarshalID writes the services' uint32 IDs as GraphQL IDs, which are
+// strings
+func MarshalID(id uint32) graphql.Marshaler {
+	return graphql.MarshalString(strconv.FormatUint(uint64(id), 10))
+}
+
+// UnmarshalID reads an ID given as a string or an integer
+func UnmarshalID(v any) (uint32, error) {
+	s := fmt.Sprint(v)
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+	return uint32(id), nil
+}
@@ -0,0 +1,23 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+)
+
+type MenuPage struct {
+	MenuItems []*menuv1.MenuItem `json:"menuItems"`
+	// Empty on the last page
+	NextPageToken string `json:"nextPageToken"`
+}
+
+type OrderPage struct {
+	Orders []*orderv1.Order `json:"orders"`
+	// Empty on the last page
+	NextPageToken string `json:"nextPageToken"`
+}
+
+type Query struct {
+}
@@ -0,0 +1,43 @@
+package graph
+
+//go:generate sh -c "cd .. && go tool gqlgen generate"
+
+import (
+	"context"
+
+	"api-gateway/graph/model"
+	"api-gateway/grpc"
+
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+)
+
+// Resolver resolves the schema's fields with the gRPC clients. Users and
+// menu items are fetched through the request's loaders, so a list of orders
+// costs one call per batch rather than one per order.
+type Resolver struct {
+	clients *grpc.ServiceClients
+}
+
+// orders returns a page of orders, those placed by userID if it is not nil
+func (r *Resolver) orders(ctx context.Context, userID *uint32, first *int32, after, status *string) (*model.OrderPage, error) {
+	resp, err := r.clients.OrderClient.GetOrders(ctx, &orderv1.GetOrdersRequest{
+		PageSize:  value(first),
+		PageToken: value(after),
+		UserId:    userID,
+		Status:    value(status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.OrderPage{Orders: resp.Orders, NextPageToken: resp.NextPageToken}, nil
+}
+
+// value returns the argument p points to, or its zero value if it was not
+// given
+func value[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"api-gateway/graph/model"
+	"context"
+	"student-cafe-shared/auth"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CategoryID is the resolver for the categoryId field.
+func (r *menuItemResolver) CategoryID(ctx context.Context, obj *menuv1.MenuItem) (*uint32, error) {
+	if obj.CategoryId == 0 {
+		return nil, nil
+	}
+	return &obj.CategoryId, nil
+}
+
+// User is the resolver for the user field.
+func (r *orderResolver) User(ctx context.Context, obj *orderv1.Order) (*userv1.User, error) {
+	user, _, err := loadersFrom(ctx).users.Load(ctx, obj.UserId)
+	return user, err
+}
+
+// MenuItem is the resolver for the menuItem field.
+func (r *orderItemResolver) MenuItem(ctx context.Context, obj *orderv1.OrderItem) (*menuv1.MenuItem, error) {
+	item, _, err := loadersFrom(ctx).menuItems.Load(ctx, obj.MenuItemId)
+	return item, err
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*userv1.User, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	user, _, err := loadersFrom(ctx).users.Load(ctx, identity.UserID)
+	return user, err
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id uint32) (*userv1.User, error) {
+	user, _, err := loadersFrom(ctx).users.Load(ctx, id)
+	return user, err
+}
+
+// MenuItem is the resolver for the menuItem field.
+func (r *queryResolver) MenuItem(ctx context.Context, id uint32) (*menuv1.MenuItem, error) {
+	item, _, err := loadersFrom(ctx).menuItems.Load(ctx, id)
+	return item, err
+}
+
+// Menu is the resolver for the menu field.
+func (r *queryResolver) Menu(ctx context.Context, first *int32, after *string, availableOnly *bool) (*model.MenuPage, error) {
+	resp, err := r.clients.MenuClient.GetMenu(ctx, &menuv1.GetMenuRequest{
+		PageSize:      value(first),
+		PageToken:     value(after),
+		AvailableOnly: value(availableOnly),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.MenuPage{MenuItems: resp.MenuItems, NextPageToken: resp.NextPageToken}, nil
+}
+
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id uint32) (*orderv1.Order, error) {
+	resp, err := r.clients.OrderClient.GetOrder(ctx, &orderv1.GetOrderRequest{Id: id})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Order, nil
+}
+
+// Orders is the resolver for the orders field.
+func (r *queryResolver) Orders(ctx context.Context, first *int32, after *string, status *string) (*model.OrderPage, error) {
+	return r.orders(ctx, nil, first, after, status)
+}
+
+// Orders is the resolver for the orders field.
+func (r *userResolver) Orders(ctx context.Context, obj *userv1.User, first *int32, after *string, status *string) (*model.OrderPage, error) {
+	return r.orders(ctx, &obj.Id, first, after, status)
+}
+
+// MenuItem returns MenuItemResolver implementation.
+func (r *Resolver) MenuItem() MenuItemResolver { return &menuItemResolver{r} }
+
+// Order returns OrderResolver implementation.
+func (r *Resolver) Order() OrderResolver { return &orderResolver{r} }
+
+// OrderItem returns OrderItemResolver implementation.
+func (r *Resolver) OrderItem() OrderItemResolver { return &orderItemResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type menuItemResolver struct{ *Resolver }
+type orderResolver struct{ *Resolver }
+type orderItemResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
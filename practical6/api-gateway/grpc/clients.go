@@ -1,15 +1,37 @@
 package grpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/logging"
+	"student-cafe-shared/mtls"
+	"student-cafe-shared/resilience"
+	"student-cafe-shared/tracing"
+)
+
+// Idempotent reads, retried with backoff while a service is unavailable
+var (
+	userReads  = []string{"GetUser", "BatchGetUsers", "GetUsers"}
+	menuReads  = []string{"GetMenuItem", "BatchGetMenuItems", "GetMenu", "GetCategories"}
+	orderReads = []string{"GetOrder", "GetOrders"}
+)
+
+// Circuit breakers for each backend service, published as metrics
+var (
+	userBreaker  = resilience.NewBreaker("user-service", resilience.BreakerConfig{})
+	menuBreaker  = resilience.NewBreaker("menu-service", resilience.BreakerConfig{})
+	orderBreaker = resilience.NewBreaker("order-service", resilience.BreakerConfig{})
 )
 
 // ServiceClients holds all gRPC clients for backend services
@@ -17,35 +39,69 @@ type ServiceClients struct {
 	UserClient  userv1.UserServiceClient
 	MenuClient  menuv1.MenuServiceClient
 	OrderClient orderv1.OrderServiceClient
+
+	// Backends are the connections behind the clients, used for health
+	// checks and closed by Close
+	Backends []Backend
 }
 
-// NewServiceClients creates and initializes gRPC clients for all backend services
-func NewServiceClients() (*ServiceClients, error) {
+// Backend is the connection to one backend service
+type Backend struct {
+	Name    string // e.g. "user-service"
+	Service string // fully qualified gRPC service name, e.g. "user.v1.UserService"
+	Conn    *grpc.ClientConn
+}
+
+// Close closes the connections to every backend service
+func (c *ServiceClients) Close() error {
+	var errs []error
+	for _, b := range c.Backends {
+		errs = append(errs, b.Conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// NewServiceClients creates and initializes gRPC clients for all backend
+// services. Every call forwards the caller identity stored in its context by
+// the authentication middleware. menuChanged, if not nil, is called after
+// every call that may have changed the menu, including order writes, which
+// reserve and release its stock.
+func NewServiceClients(menuChanged func()) (*ServiceClients, error) {
 	// Get service addresses from environment or use defaults
 	userAddr := getEnv("USER_SERVICE_GRPC_ADDR", "user-service:9091")
 	menuAddr := getEnv("MENU_SERVICE_GRPC_ADDR", "menu-service:9092")
 	orderAddr := getEnv("ORDER_SERVICE_GRPC_ADDR", "order-service:9093")
 
-	log.Printf("Connecting to User Service at %s", userAddr)
+	callTimeout, err := resilience.CallTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Connecting to User Service", "addr", userAddr)
 	// Create gRPC connection to user service
-	userConn, err := grpc.NewClient(userAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	userConn, err := dial(userAddr, resilience.DialOptions(userv1.UserService_ServiceDesc, callTimeout, userBreaker, userReads...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service: %w", err)
 	}
 
-	log.Printf("Connecting to Menu Service at %s", menuAddr)
+	slog.Info("Connecting to Menu Service", "addr", menuAddr)
 	// Create gRPC connection to menu service
-	menuConn, err := grpc.NewClient(menuAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	menuOpts := resilience.DialOptions(menuv1.MenuService_ServiceDesc, callTimeout, menuBreaker, menuReads...)
+	if menuChanged != nil {
+		menuOpts = append(menuOpts, afterWrites(menuv1.MenuService_ServiceDesc, menuChanged, menuReads...))
+	}
+	menuConn, err := dial(menuAddr, menuOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to menu service: %w", err)
 	}
 
-	log.Printf("Connecting to Order Service at %s", orderAddr)
+	slog.Info("Connecting to Order Service", "addr", orderAddr)
 	// Create gRPC connection to order service
-	orderConn, err := grpc.NewClient(orderAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	orderOpts := resilience.DialOptions(orderv1.OrderService_ServiceDesc, callTimeout, orderBreaker, orderReads...)
+	if menuChanged != nil {
+		orderOpts = append(orderOpts, afterWrites(orderv1.OrderService_ServiceDesc, menuChanged, orderReads...))
+	}
+	orderConn, err := dial(orderAddr, orderOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to order service: %w", err)
 	}
@@ -54,9 +110,46 @@ func NewServiceClients() (*ServiceClients, error) {
 		UserClient:  userv1.NewUserServiceClient(userConn),
 		MenuClient:  menuv1.NewMenuServiceClient(menuConn),
 		OrderClient: orderv1.NewOrderServiceClient(orderConn),
+		Backends: []Backend{
+			{Name: "user-service", Service: userv1.UserService_ServiceDesc.ServiceName, Conn: userConn},
+			{Name: "menu-service", Service: menuv1.MenuService_ServiceDesc.ServiceName, Conn: menuConn},
+			{Name: "order-service", Service: orderv1.OrderService_ServiceDesc.ServiceName, Conn: orderConn},
+		},
 	}, nil
 }
 
+// dial connects to addr over TLS when TLS_CA_FILE is set, forwarding the
+// caller identity, request ID and trace context on every call
+func dial(addr string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	creds, err := mtls.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewClient(addr, append([]grpc.DialOption{
+		creds,
+		tracing.DialOption(),
+		grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor(), logging.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(auth.StreamClientInterceptor(), logging.StreamClientInterceptor()),
+	}, opts...)...)
+}
+
+// afterWrites returns a dial option calling changed after every call to a
+// method of service other than reads that succeeded, or timed out and so may
+// have
+func afterWrites(service grpc.ServiceDesc, changed func(), reads ...string) grpc.DialOption {
+	readMethods := make(map[string]bool, len(reads))
+	for _, name := range reads {
+		readMethods["/"+service.ServiceName+"/"+name] = true
+	}
+	return grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !readMethods[method] && (err == nil || status.Code(err) == codes.DeadlineExceeded) {
+			changed()
+		}
+		return err
+	})
+}
+
 func getEnv(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
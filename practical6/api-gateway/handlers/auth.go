@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"google.golang.org/grpc/codes"
+	"student-cafe-shared/auth"
+)
+
+// Authenticate returns middleware that verifies the bearer token of requests
+// that carry one and stores the caller's identity in the request context,
+// from where the gRPC clients forward it to the backend services. Requests
+// without an Authorization header pass through anonymously; invalid tokens
+// are rejected with 401.
+func Authenticate(verifier *auth.TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") {
+				unauthorized(w, r, "authorization header must be 'Bearer <token>'")
+				return
+			}
+
+			identity, err := verifier.Verify(strings.TrimSpace(token), auth.AccessToken)
+			if err != nil {
+				unauthorized(w, r, "invalid or expired access token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), identity)))
+		})
+	}
+}
+
+// RequireAuth rejects requests that Authenticate did not attach an identity
+// to
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.FromContext(r.Context()); !ok {
+			unauthorized(w, r, "authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="student-cafe"`)
+	writeError(w, r, codes.Unauthenticated, msg)
+}
+
+// Login handles POST /api/auth/login
+// Translates HTTP request to gRPC Login call
+func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
+	// Parse HTTP JSON request body
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.UserClient.Login(r.Context(), &userv1.LoginRequest{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RefreshToken handles POST /api/auth/refresh
+// Translates HTTP request to gRPC RefreshToken call
+func (h *Handlers) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	// Parse HTTP JSON request body
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.UserClient.RefreshToken(r.Context(), &userv1.RefreshTokenRequest{
+		RefreshToken: req.RefreshToken,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.Tokens)
+}
+
+// callerID returns the authenticated caller's user ID, or 0 when the request
+// is anonymous
+func callerID(ctx context.Context) uint32 {
+	identity, _ := auth.FromContext(ctx)
+	return identity.UserID
+}
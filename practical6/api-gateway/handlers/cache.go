@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-gateway/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_cache_requests_total",
+	Help: "Requests to cached routes, by cache and result: hit, miss or bypass.",
+}, []string{"cache", "result"})
+
+// Cache returns middleware that serves successful GET responses from c,
+// keyed by request URI. Responses carry an ETag, a Cache-Control max-age of
+// the time left until the entry expires and X-Cache: HIT, MISS or BYPASS,
+// and requests whose If-None-Match matches the ETag get 304 Not Modified. A
+// request with Cache-Control: no-cache (or max-age=0) bypasses the cache and
+// refreshes it; with no-store the fresh response is not stored either.
+func Cache(c *cache.Cache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if c.TTL() <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.URL.RequestURI()
+			directives := r.Header.Get("Cache-Control")
+			noStore := hasDirective(directives, "no-store")
+			bypass := noStore || hasDirective(directives, "no-cache") || hasDirective(directives, "max-age=0")
+
+			now := time.Now()
+			if !bypass {
+				if entry, ok := c.Get(key, now); ok {
+					cacheRequests.WithLabelValues(c.Name(), "hit").Inc()
+					writeCached(w, r, c, entry, "HIT", now)
+					return
+				}
+			}
+
+			result := "MISS"
+			if bypass {
+				result = "BYPASS"
+			}
+			cacheRequests.WithLabelValues(c.Name(), strings.ToLower(result)).Inc()
+
+			generation := c.Generation()
+			buf := &responseBuffer{header: make(http.Header)}
+			next.ServeHTTP(buf, r)
+			if buf.status != http.StatusOK {
+				buf.copyTo(w)
+				return
+			}
+
+			sum := sha256.Sum256(buf.body.Bytes())
+			entry := cache.Entry{
+				Header: buf.header,
+				Body:   buf.body.Bytes(),
+				ETag:   `"` + hex.EncodeToString(sum[:16]) + `"`,
+				Stored: now,
+			}
+			if !noStore {
+				c.Set(key, entry, generation)
+			}
+			writeCached(w, r, c, entry, result, now)
+		})
+	}
+}
+
+// writeCached writes entry, or 304 if the client already has it
+func writeCached(w http.ResponseWriter, r *http.Request, c *cache.Cache, entry cache.Entry, result string, now time.Time) {
+	h := w.Header()
+	for k, v := range entry.Header {
+		h[k] = v
+	}
+	age := now.Sub(entry.Stored)
+	h.Set("ETag", entry.ETag)
+	h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int((c.TTL()-age).Seconds())))
+	h.Set("X-Cache", result)
+	if result == "HIT" {
+		h.Set("Age", strconv.Itoa(int(age.Seconds())))
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), entry.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(entry.Body)
+}
+
+// hasDirective reports whether the Cache-Control header value contains
+// directive
+func hasDirective(header, directive string) bool {
+	for _, d := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header value lists etag,
+// comparing weakly as RFC 9110 requires
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseBuffer holds a response so it can be cached before it is sent
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+// copyTo sends the buffered response to w
+func (b *responseBuffer) copyTo(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+	if b.status != 0 {
+		w.WriteHeader(b.status)
+	}
+	w.Write(b.body.Bytes())
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"api-gateway/cache"
+	"api-gateway/grpc"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stockMenu lists one menu item whose stock is held by the orders
+type stockMenu struct {
+	menuv1.UnimplementedMenuServiceServer
+	stock *atomic.Int32
+}
+
+func (m stockMenu) GetMenu(ctx context.Context, req *menuv1.GetMenuRequest) (*menuv1.GetMenuResponse, error) {
+	stock := m.stock.Load()
+	return &menuv1.GetMenuResponse{MenuItems: []*menuv1.MenuItem{
+		{Id: 1, Name: "Tea", Stock: &stock, Available: stock > 0},
+	}}, nil
+}
+
+// stockOrders takes a unit of stock for every order placed and returns it
+// when an order is cancelled or deleted
+type stockOrders struct {
+	orderv1.UnimplementedOrderServiceServer
+	stock *atomic.Int32
+}
+
+func (o stockOrders) CreateOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.CreateOrderResponse, error) {
+	if o.stock.Add(-1) < 0 {
+		o.stock.Add(1)
+		return nil, status.Error(codes.FailedPrecondition, "out of stock")
+	}
+	return &orderv1.CreateOrderResponse{Order: &orderv1.Order{Id: 1}}, nil
+}
+
+func (o stockOrders) CancelOrder(ctx context.Context, req *orderv1.CancelOrderRequest) (*orderv1.CancelOrderResponse, error) {
+	o.stock.Add(1)
+	return &orderv1.CancelOrderResponse{Order: &orderv1.Order{Id: req.Id}}, nil
+}
+
+func (o stockOrders) DeleteOrder(ctx context.Context, req *orderv1.DeleteOrderRequest) (*orderv1.DeleteOrderResponse, error) {
+	o.stock.Add(1)
+	return &orderv1.DeleteOrderResponse{}, nil
+}
+
+func TestCache_OrdersPurgeMenu(t *testing.T) {
+	var stock atomic.Int32
+	stock.Store(1)
+
+	// Serve both backends on one local port, and point the clients at it
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := googlegrpc.NewServer()
+	menuv1.RegisterMenuServiceServer(srv, stockMenu{stock: &stock})
+	orderv1.RegisterOrderServiceServer(srv, stockOrders{stock: &stock})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	for _, env := range []string{"USER_SERVICE_GRPC_ADDR", "MENU_SERVICE_GRPC_ADDR", "ORDER_SERVICE_GRPC_ADDR"} {
+		t.Setenv(env, lis.Addr().String())
+	}
+	menuCache := cache.New("menu", time.Minute)
+	clients, err := grpc.NewServiceClients(menuCache.Purge)
+	require.NoError(t, err)
+	t.Cleanup(func() { clients.Close() })
+
+	h := NewHandlers(clients)
+	r := chi.NewRouter()
+	r.With(Cache(menuCache)).Get("/api/menu", h.GetMenu)
+	r.Post("/api/orders", h.CreateOrder)
+	r.Post("/api/orders/{id}/cancel", h.CancelOrder)
+	r.Delete("/api/orders/{id}", h.DeleteOrder)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	// getMenu returns the X-Cache header and the stock listed for the item
+	getMenu := func() (string, int32) {
+		rec := serve(http.MethodGet, "/api/menu", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var items []struct {
+			Stock int32 `json:"stock"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+		require.Len(t, items, 1)
+		return rec.Header().Get("X-Cache"), items[0].Stock
+	}
+
+	hit, got := getMenu()
+	assert.Equal(t, "MISS", hit)
+	assert.Equal(t, int32(1), got)
+	hit, _ = getMenu()
+	assert.Equal(t, "HIT", hit)
+
+	// Placing an order takes the last one
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/api/orders", `{"user_id": 1, "items": [{"menu_item_id": 1, "quantity": 1}]}`).Code)
+	hit, got = getMenu()
+	assert.Equal(t, "MISS", hit, "placing an order purges the menu")
+	assert.Equal(t, int32(0), got)
+
+	// A rejected order changes nothing, so the menu stays cached
+	require.Equal(t, http.StatusPreconditionFailed, serve(http.MethodPost, "/api/orders", `{"user_id": 1, "items": [{"menu_item_id": 1, "quantity": 1}]}`).Code)
+	hit, _ = getMenu()
+	assert.Equal(t, "HIT", hit)
+
+	require.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/orders/1/cancel", "").Code)
+	hit, got = getMenu()
+	assert.Equal(t, "MISS", hit, "cancelling an order purges the menu")
+	assert.Equal(t, int32(1), got)
+
+	require.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/api/orders/1", "").Code)
+	hit, got = getMenu()
+	assert.Equal(t, "MISS", hit, "deleting an order purges the menu")
+	assert.Equal(t, int32(2), got)
+}
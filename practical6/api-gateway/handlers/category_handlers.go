@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	"google.golang.org/grpc/codes"
+)
+
+// CreateCategory handles POST /api/categories
+// Translates HTTP request to gRPC CreateCategory call
+func (h *Handlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	// Parse HTTP JSON request body
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.MenuClient.CreateCategory(r.Context(), &menuv1.CreateCategoryRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp.Category)
+}
+
+// GetCategories handles GET /api/categories
+// Translates HTTP request to gRPC GetCategories call
+func (h *Handlers) GetCategories(w http.ResponseWriter, r *http.Request) {
+	// Call gRPC service
+	resp, err := h.clients.MenuClient.GetCategories(r.Context(), &menuv1.GetCategoriesRequest{})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.Categories)
+}
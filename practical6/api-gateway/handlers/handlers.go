@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"api-gateway/grpc"
 
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"student-cafe-shared/logging"
 )
 
 // Handlers holds the HTTP handlers and gRPC clients
@@ -19,37 +23,113 @@ func NewHandlers(clients *grpc.ServiceClients) *Handlers {
 	return &Handlers{clients: clients}
 }
 
+// errorResponse is the JSON body of every error response
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+// errorBody describes an error. Code is the gRPC status code name, e.g.
+// "INVALID_ARGUMENT"; Reason and Metadata come from a google.rpc.ErrorInfo
+// detail and FieldViolations from a google.rpc.BadRequest detail.
+type errorBody struct {
+	Code            string            `json:"code"`
+	Message         string            `json:"message"`
+	RequestID       string            `json:"request_id,omitempty"`
+	Reason          string            `json:"reason,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	FieldViolations []fieldViolation  `json:"field_violations,omitempty"`
+}
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// newErrorResponse builds the error body for a gRPC status
+func newErrorResponse(r *http.Request, st *status.Status) errorResponse {
+	body := errorBody{
+		Code:      code.Code(st.Code()).String(),
+		Message:   st.Message(),
+		RequestID: logging.RequestID(r.Context()),
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				body.FieldViolations = append(body.FieldViolations, fieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+			}
+		case *errdetails.ErrorInfo:
+			body.Reason = d.Reason
+			body.Metadata = d.Metadata
+		}
+	}
+
+	return errorResponse{Error: body}
+}
+
+// writeError writes an error detected by the gateway itself, such as a
+// malformed request body
+func writeError(w http.ResponseWriter, r *http.Request, c codes.Code, msg string) {
+	writeStatus(w, r, status.New(c, msg))
+}
+
 // handleGRPCError converts gRPC errors to appropriate HTTP status codes
-func handleGRPCError(w http.ResponseWriter, err error) {
+func handleGRPCError(w http.ResponseWriter, r *http.Request, err error) {
 	st, ok := status.FromError(err)
 	if !ok {
 		// Not a gRPC error, return generic internal server error
-		http.Error(w, "internal server error", http.StatusInternalServerError)
-		return
+		st = status.New(codes.Internal, "internal server error")
 	}
+	writeStatus(w, r, st)
+}
+
+// writeStatus writes st as a JSON error response
+func writeStatus(w http.ResponseWriter, r *http.Request, st *status.Status) {
+	writeStatusCode(w, r, httpStatus(st.Code()), st)
+}
+
+// writeStatusCode writes st as a JSON error response with the HTTP status
+// code, for errors whose code httpStatus would map imprecisely
+func writeStatusCode(w http.ResponseWriter, r *http.Request, code int, st *status.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(newErrorResponse(r, st))
+}
 
-	// Map gRPC status codes to HTTP status codes
-	var httpStatus int
-	switch st.Code() {
+// statusClientClosedRequest is the non-standard status, popularised by
+// nginx, for requests the client gave up on before they were answered
+const statusClientClosedRequest = 499
+
+// httpStatus maps gRPC status codes to HTTP status codes
+func httpStatus(c codes.Code) int {
+	switch c {
 	case codes.NotFound:
-		httpStatus = http.StatusNotFound
+		return http.StatusNotFound
 	case codes.InvalidArgument:
-		httpStatus = http.StatusBadRequest
-	case codes.AlreadyExists:
-		httpStatus = http.StatusConflict
+		return http.StatusBadRequest
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
 	case codes.PermissionDenied:
-		httpStatus = http.StatusForbidden
+		return http.StatusForbidden
 	case codes.Unauthenticated:
-		httpStatus = http.StatusUnauthorized
+		return http.StatusUnauthorized
 	case codes.FailedPrecondition:
-		httpStatus = http.StatusPreconditionFailed
+		return http.StatusPreconditionFailed
 	case codes.Unimplemented:
-		httpStatus = http.StatusNotImplemented
+		return http.StatusNotImplemented
 	case codes.Unavailable:
-		httpStatus = http.StatusServiceUnavailable
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return statusClientClosedRequest
 	default:
-		httpStatus = http.StatusInternalServerError
+		return http.StatusInternalServerError
 	}
-
-	http.Error(w, st.Message(), httpStatus)
 }
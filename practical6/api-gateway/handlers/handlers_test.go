@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	for code, want := range map[codes.Code]int{
+		codes.NotFound:           http.StatusNotFound,
+		codes.InvalidArgument:    http.StatusBadRequest,
+		codes.AlreadyExists:      http.StatusConflict,
+		codes.Aborted:            http.StatusConflict,
+		codes.PermissionDenied:   http.StatusForbidden,
+		codes.Unauthenticated:    http.StatusUnauthorized,
+		codes.FailedPrecondition: http.StatusPreconditionFailed,
+		codes.Unimplemented:      http.StatusNotImplemented,
+		codes.Unavailable:        http.StatusServiceUnavailable,
+		codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+		codes.ResourceExhausted:  http.StatusTooManyRequests,
+		codes.Canceled:           statusClientClosedRequest,
+		codes.Internal:           http.StatusInternalServerError,
+		codes.Unknown:            http.StatusInternalServerError,
+	} {
+		assert.Equal(t, want, httpStatus(code), code.String())
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"student-cafe-shared/health"
+)
+
+// readyTimeout bounds the backend health checks made by Readyz
+const readyTimeout = 2 * time.Second
+
+// healthResponse is the JSON body of /healthz and /readyz
+type healthResponse struct {
+	Status   string            `json:"status"`
+	Services map[string]string `json:"services,omitempty"`
+}
+
+// Healthz reports that the gateway process is up. It does not call the
+// backends, so an outage behind the gateway never gets it restarted.
+func (h *Handlers) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// Readyz reports whether the gateway can serve requests: every backend
+// service must be reachable and report SERVING through grpc.health.v1. The
+// body lists each backend's state.
+func (h *Handlers) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	resp := healthResponse{Status: "ready", Services: make(map[string]string)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, b := range h.clients.Backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			state := "SERVING"
+			if err := health.Remote(b.Conn, b.Service)(ctx); err != nil {
+				state = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Services[b.Name] = state
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	for _, state := range resp.Services {
+		if state != "SERVING" {
+			resp.Status = "not ready"
+			code = http.StatusServiceUnavailable
+		}
+	}
+	writeHealth(w, code, resp)
+}
+
+func writeHealth(w http.ResponseWriter, code int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc/codes"
+	"student-cafe-shared/logging"
+)
+
+// RequestID returns middleware that gives every request an ID, keeping the
+// one the caller sent in X-Request-ID when it is safe to log. The ID is
+// echoed in the response header, stored in the request context for log
+// records and error responses, and forwarded to the backend services by
+// the gRPC clients.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// Logger returns middleware that logs every request when it finishes
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		level := slog.LevelInfo
+		switch {
+		case code >= 500:
+			level = slog.LevelError
+		case code >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", code),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote_addr", r.RemoteAddr),
+		}
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			attrs = append(attrs, slog.String("route", rctx.RoutePattern()))
+		}
+		slog.LogAttrs(r.Context(), level, "HTTP request finished", attrs...)
+	})
+}
+
+// Recover returns middleware that turns a panicking handler into a 500
+// error response and logs the panic with its stack
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+			if rvr == http.ErrAbortHandler {
+				// Deliberate aborts are handled by net/http
+				panic(rvr)
+			}
+			slog.ErrorContext(r.Context(), "Handler panicked",
+				"panic", rvr, "stack", string(debug.Stack()))
+			writeError(w, r, codes.Internal, "internal server error")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,39 +1,59 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 
+	commonv1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
 )
 
 // CreateMenuItem handles POST /api/menu
 // Translates HTTP request to gRPC CreateMenuItem call
 func (h *Handlers) CreateMenuItem(w http.ResponseWriter, r *http.Request) {
 	// Parse HTTP JSON request body
+	// Price is a decimal amount in major units (e.g. 3.50); currency is optional.
+	// Items are available unless "available" is false; stock is only tracked
+	// when "stock" is given.
 	var req struct {
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
+		Name        string      `json:"name"`
+		Description string      `json:"description"`
+		Price       json.Number `json:"price"`
+		Currency    string      `json:"currency"`
+		CategoryID  uint32      `json:"category_id"`
+		Available   *bool       `json:"available"`
+		Stock       *int32      `json:"stock"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	priceMinor, err := parseMinorUnits(req.Price.String())
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid price: "+err.Error())
 		return
 	}
 
 	// Call gRPC service
-	resp, err := h.clients.MenuClient.CreateMenuItem(context.Background(), &menuv1.CreateMenuItemRequest{
+	resp, err := h.clients.MenuClient.CreateMenuItem(r.Context(), &menuv1.CreateMenuItemRequest{
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
+		Price: &commonv1.Money{
+			CurrencyCode: req.Currency,
+			AmountMinor:  priceMinor,
+		},
+		CategoryId: req.CategoryID,
+		Available:  req.Available,
+		Stock:      req.Stock,
 	})
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
@@ -50,17 +70,17 @@ func (h *Handlers) GetMenuItem(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		http.Error(w, "invalid menu item ID", http.StatusBadRequest)
+		writeError(w, r, codes.InvalidArgument, "invalid menu item ID")
 		return
 	}
 
 	// Call gRPC service
-	resp, err := h.clients.MenuClient.GetMenuItem(context.Background(), &menuv1.GetMenuItemRequest{
+	resp, err := h.clients.MenuClient.GetMenuItem(r.Context(), &menuv1.GetMenuItemRequest{
 		Id: uint32(id),
 	})
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
@@ -72,15 +92,234 @@ func (h *Handlers) GetMenuItem(w http.ResponseWriter, r *http.Request) {
 // GetMenu handles GET /api/menu
 // Translates HTTP request to gRPC GetMenu call
 func (h *Handlers) GetMenu(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageParams(r)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	req := &menuv1.GetMenuRequest{
+		PageSize:     page.PageSize,
+		PageToken:    page.PageToken,
+		OrderBy:      page.OrderBy,
+		NameContains: query.Get("name"),
+	}
+
+	if raw := query.Get("category_id"); raw != "" {
+		categoryID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid category_id")
+			return
+		}
+		id := uint32(categoryID)
+		req.CategoryId = &id
+	}
+	if raw := query.Get("available"); raw != "" {
+		availableOnly, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid available")
+			return
+		}
+		req.AvailableOnly = availableOnly
+	}
+
+	// Price bounds are decimal amounts, like the price of a new item
+	if raw := query.Get("min_price"); raw != "" {
+		minPrice, err := parseMinorUnits(raw)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid min_price: "+err.Error())
+			return
+		}
+		req.MinPriceMinor = &minPrice
+	}
+	if raw := query.Get("max_price"); raw != "" {
+		maxPrice, err := parseMinorUnits(raw)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid max_price: "+err.Error())
+			return
+		}
+		req.MaxPriceMinor = &maxPrice
+	}
+
 	// Call gRPC service
-	resp, err := h.clients.MenuClient.GetMenu(context.Background(), &menuv1.GetMenuRequest{})
+	resp, err := h.clients.MenuClient.GetMenu(r.Context(), req)
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
 	// Return HTTP JSON response
 	w.Header().Set("Content-Type", "application/json")
+	setNextPageToken(w, resp.NextPageToken)
 	json.NewEncoder(w).Encode(resp.MenuItems)
 }
+
+// SetMenuItemAvailability handles PUT /api/menu/{id}/availability
+// Translates HTTP request to gRPC SetMenuItemAvailability call
+func (h *Handlers) SetMenuItemAvailability(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid menu item ID")
+		return
+	}
+
+	// Parse HTTP JSON request body
+	var req struct {
+		Available *bool `json:"available"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Available == nil {
+		writeError(w, r, codes.InvalidArgument, "request body must set available")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.MenuClient.SetMenuItemAvailability(r.Context(), &menuv1.SetMenuItemAvailabilityRequest{
+		Id:        uint32(id),
+		Available: *req.Available,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.MenuItem)
+}
+
+// SetMenuItemStock handles PUT /api/menu/{id}/stock
+// Translates HTTP request to gRPC SetMenuItemStock call
+func (h *Handlers) SetMenuItemStock(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid menu item ID")
+		return
+	}
+
+	// Parse HTTP JSON request body; a null stock stops tracking stock
+	var req struct {
+		Stock *int32 `json:"stock"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.MenuClient.SetMenuItemStock(r.Context(), &menuv1.SetMenuItemStockRequest{
+		Id:    uint32(id),
+		Stock: req.Stock,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.MenuItem)
+}
+
+// UpdateMenuItem handles PUT and PATCH /api/menu/{id}
+// Translates HTTP request to gRPC UpdateMenuItem call
+func (h *Handlers) UpdateMenuItem(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid menu item ID")
+		return
+	}
+
+	// Parse HTTP JSON request body; PUT takes the same body as POST, PATCH
+	// only updates the fields it sets
+	var req struct {
+		Name        string      `json:"name"`
+		Description string      `json:"description"`
+		Price       json.Number `json:"price"`
+		Currency    string      `json:"currency"`
+		CategoryID  uint32      `json:"category_id"`
+		Available   *bool       `json:"available"`
+		Stock       *int32      `json:"stock"`
+	}
+
+	mask, err := decodeUpdate(r, &req, map[string]string{
+		"name":        "name",
+		"description": "description",
+		"price":       "price",
+		"currency":    "price",
+		"category_id": "category_id",
+		"available":   "available",
+		"stock":       "stock",
+	})
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	item := &menuv1.MenuItem{
+		Id:          uint32(id),
+		Name:        req.Name,
+		Description: req.Description,
+		CategoryId:  req.CategoryID,
+		Available:   req.Available == nil || *req.Available,
+		Stock:       req.Stock,
+	}
+	if hasPath(mask, "price") {
+		priceMinor, err := parseMinorUnits(req.Price.String())
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid price: "+err.Error())
+			return
+		}
+		item.Price = &commonv1.Money{
+			CurrencyCode: req.Currency,
+			AmountMinor:  priceMinor,
+		}
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.MenuClient.UpdateMenuItem(r.Context(), &menuv1.UpdateMenuItemRequest{
+		MenuItem:   item,
+		UpdateMask: mask,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.MenuItem)
+}
+
+// DeleteMenuItem handles DELETE /api/menu/{id}
+// Translates HTTP request to gRPC DeleteMenuItem call
+func (h *Handlers) DeleteMenuItem(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid menu item ID")
+		return
+	}
+
+	// Call gRPC service
+	if _, err := h.clients.MenuClient.DeleteMenuItem(r.Context(), &menuv1.DeleteMenuItemRequest{
+		Id: uint32(id),
+	}); err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
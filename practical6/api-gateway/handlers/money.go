@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseMinorUnits converts a decimal amount such as "3.50" into minor units
+// (350) without going through floating point. At most two decimal places are
+// accepted.
+func parseMinorUnits(amount string) (int64, error) {
+	whole, frac, _ := strings.Cut(amount, ".")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("amount %q has more than two decimal places", amount)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	// Only plain digits are accepted in each part, so exponents and nested
+	// signs are rejected
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+	if whole == "" || strings.Trim(whole+frac, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	if negative {
+		minor = -minor
+	}
+	return minor, nil
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // CreateOrder handles POST /api/orders
@@ -23,7 +27,7 @@ func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
 		return
 	}
 
@@ -36,14 +40,21 @@ func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	// Call gRPC service
-	resp, err := h.clients.OrderClient.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
-		UserId: req.UserID,
-		Items:  items,
+	// Orders are placed for the caller unless the body names another user
+	if req.UserID == 0 {
+		req.UserID = callerID(r.Context())
+	}
+
+	// Call gRPC service; retries carrying the same Idempotency-Key header
+	// return the original order
+	resp, err := h.clients.OrderClient.CreateOrder(r.Context(), &orderv1.CreateOrderRequest{
+		UserId:         req.UserID,
+		Items:          items,
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
 	})
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
@@ -60,17 +71,17 @@ func (h *Handlers) GetOrder(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		http.Error(w, "invalid order ID", http.StatusBadRequest)
+		writeError(w, r, codes.InvalidArgument, "invalid order ID")
 		return
 	}
 
 	// Call gRPC service
-	resp, err := h.clients.OrderClient.GetOrder(context.Background(), &orderv1.GetOrderRequest{
+	resp, err := h.clients.OrderClient.GetOrder(r.Context(), &orderv1.GetOrderRequest{
 		Id: uint32(id),
 	})
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
@@ -82,15 +93,253 @@ func (h *Handlers) GetOrder(w http.ResponseWriter, r *http.Request) {
 // GetOrders handles GET /api/orders
 // Translates HTTP request to gRPC GetOrders call
 func (h *Handlers) GetOrders(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageParams(r)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	req := &orderv1.GetOrdersRequest{
+		PageSize:      page.PageSize,
+		PageToken:     page.PageToken,
+		OrderBy:       page.OrderBy,
+		Status:        query.Get("status"),
+		CreatedAfter:  query.Get("created_after"),
+		CreatedBefore: query.Get("created_before"),
+	}
+
+	// Apply filters from query parameters
+	if raw := query.Get("user_id"); raw != "" {
+		userID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid user_id")
+			return
+		}
+		id := uint32(userID)
+		req.UserId = &id
+	}
+
 	// Call gRPC service
-	resp, err := h.clients.OrderClient.GetOrders(context.Background(), &orderv1.GetOrdersRequest{})
+	resp, err := h.clients.OrderClient.GetOrders(r.Context(), req)
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
 	// Return HTTP JSON response
 	w.Header().Set("Content-Type", "application/json")
+	setNextPageToken(w, resp.NextPageToken)
 	json.NewEncoder(w).Encode(resp.Orders)
 }
+
+// UpdateOrderStatus handles PATCH /api/orders/{id}/status
+// Translates HTTP request to gRPC UpdateOrderStatus call
+func (h *Handlers) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid order ID")
+		return
+	}
+
+	// Parse HTTP JSON request body
+	var req struct {
+		Status string `json:"status"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.OrderClient.UpdateOrderStatus(r.Context(), &orderv1.UpdateOrderStatusRequest{
+		Id:     uint32(id),
+		Status: req.Status,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.Order)
+}
+
+// CancelOrder handles POST /api/orders/{id}/cancel
+// Translates HTTP request to gRPC CancelOrder call
+func (h *Handlers) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid order ID")
+		return
+	}
+
+	// Reason is optional, so an empty body is accepted
+	var req struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.OrderClient.CancelOrder(r.Context(), &orderv1.CancelOrderRequest{
+		Id:     uint32(id),
+		Reason: req.Reason,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.Order)
+}
+
+// watchRetryDelay is how long the gateway waits before re-opening a
+// WatchOrder stream after losing the connection to the order service
+const watchRetryDelay = time.Second
+
+// DeleteOrder handles DELETE /api/orders/{id}
+// Translates HTTP request to gRPC DeleteOrder call
+func (h *Handlers) DeleteOrder(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid order ID")
+		return
+	}
+
+	// Call gRPC service
+	if _, err := h.clients.OrderClient.DeleteOrder(r.Context(), &orderv1.DeleteOrderRequest{
+		Id: uint32(id),
+	}); err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StreamOrderEvents handles GET /api/orders/{id}/events
+// Bridges the gRPC WatchOrder stream to Server-Sent Events. Clients resume
+// with the standard Last-Event-ID header (or ?last_event_id=), and the gateway
+// re-opens the gRPC stream from the last forwarded event if the order service
+// connection drops mid-stream.
+func (h *Handlers) StreamOrderEvents(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid order ID")
+		return
+	}
+
+	lastEventIDStr := r.Header.Get("Last-Event-ID")
+	if lastEventIDStr == "" {
+		lastEventIDStr = r.URL.Query().Get("last_event_id")
+	}
+	var lastEventID uint64
+	if lastEventIDStr != "" {
+		lastEventID, err = strconv.ParseUint(lastEventIDStr, 10, 64)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid last event ID")
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	streaming := false
+	startStreaming := func() {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		rc.Flush()
+		streaming = true
+	}
+
+	// A resuming client has already seen the order, so there is no error
+	// to report before the next event and the stream can start right away
+	if lastEventID > 0 {
+		startStreaming()
+	}
+
+	for {
+		err = h.forwardOrderEvents(w, r, rc, uint32(id), &lastEventID, func() {
+			if !streaming {
+				startStreaming()
+			}
+		})
+		if err == nil || r.Context().Err() != nil {
+			// The order reached a terminal status or the client went away
+			return
+		}
+
+		if !streaming {
+			handleGRPCError(w, r, err)
+			return
+		}
+
+		// Only a lost connection to the order service is worth retrying
+		if status.Code(err) != codes.Unavailable {
+			data, _ := json.Marshal(newErrorResponse(r, status.Convert(err)))
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			rc.Flush()
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// forwardOrderEvents opens a WatchOrder stream after *lastEventID and writes
+// each update as an SSE event until the stream ends. It returns nil when the
+// order service closes the stream normally.
+func (h *Handlers) forwardOrderEvents(w http.ResponseWriter, r *http.Request, rc *http.ResponseController, id uint32, lastEventID *uint64, beforeFirstEvent func()) error {
+	stream, err := h.clients.OrderClient.WatchOrder(r.Context(), &orderv1.WatchOrderRequest{
+		Id:           id,
+		AfterEventId: *lastEventID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(resp.Order)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to encode order: %v", err)
+		}
+
+		beforeFirstEvent()
+		fmt.Fprintf(w, "id: %d\nevent: order\ndata: %s\n\n", resp.EventId, data)
+		if err := rc.Flush(); err != nil {
+			return status.FromContextError(r.Context().Err()).Err()
+		}
+		*lastEventID = resp.EventId
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// nextPageTokenHeader carries the token for the next page of a list response,
+// so list bodies can stay plain JSON arrays
+const nextPageTokenHeader = "X-Next-Page-Token"
+
+// pageParams holds the pagination and sorting query parameters shared by the
+// list endpoints
+type pageParams struct {
+	PageSize  int32
+	PageToken string
+	OrderBy   string
+}
+
+// parsePageParams reads page_size, page_token and order_by from the query string
+func parsePageParams(r *http.Request) (pageParams, error) {
+	query := r.URL.Query()
+	params := pageParams{
+		PageToken: query.Get("page_token"),
+		OrderBy:   query.Get("order_by"),
+	}
+
+	if raw := query.Get("page_size"); raw != "" {
+		size, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || size < 0 {
+			return pageParams{}, fmt.Errorf("invalid page_size %q", raw)
+		}
+		params.PageSize = int32(size)
+	}
+	return params, nil
+}
+
+// setNextPageToken exposes the next page token, if any, as a response header
+func setNextPageToken(w http.ResponseWriter, token string) {
+	if token != "" {
+		w.Header().Set(nextPageTokenHeader, token)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"api-gateway/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/grpcerr"
+)
+
+var rateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_rate_limited_total",
+	Help: "Requests rejected by the gateway's rate limits, by bucket.",
+}, []string{"bucket"})
+
+// RateLimits are the token buckets RateLimit gives each caller. A request to
+// a route in Routes takes from that route's own bucket; the rest share the
+// Reads bucket (GET, HEAD and OPTIONS) or the Writes bucket.
+type RateLimits struct {
+	Reads  ratelimit.Limit
+	Writes ratelimit.Limit
+	// Routes are keyed by method and route pattern, e.g. "POST /api/orders"
+	Routes map[string]ratelimit.Limit
+}
+
+// bucket returns the name and limit of the bucket a request to route takes
+// from
+func (l RateLimits) bucket(method, route string) (string, ratelimit.Limit) {
+	name := method + " " + route
+	if limit, ok := l.Routes[name]; ok {
+		return name, limit
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "reads", l.Reads
+	default:
+		return "writes", l.Writes
+	}
+}
+
+// RateLimit returns middleware that limits each caller, the authenticated
+// user or else the client IP, to limits, with the buckets held in store.
+// Every response carries RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy headers, and rejected requests get
+// 429 with Retry-After. Install it after Authenticate. When the store fails
+// requests are let through, as the limits protect the backends rather than
+// guard access to them.
+func RateLimit(store ratelimit.Store, limits RateLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name, limit := limits.bucket(r.Method, matchRoute(r))
+			if limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), name+"|"+caller(r), limit, time.Now())
+			if err != nil {
+				slog.WarnContext(r.Context(), "Rate limit store failed, allowing request", "bucket", name, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, seconds(limit.Period), limit.Burst))
+			if !result.Allowed {
+				retryAfter := seconds(result.RetryAfter)
+				h.Set("Retry-After", strconv.Itoa(retryAfter))
+				rateLimited.WithLabelValues(name).Inc()
+				writeStatus(w, r, status.Convert(grpcerr.New(codes.ResourceExhausted,
+					fmt.Sprintf("rate limit exceeded, retry after %ds", retryAfter),
+					grpcerr.Info("RATE_LIMITED", map[string]string{"bucket": name}))))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchRoute returns the pattern of the route r will be routed to, or "" if
+// none matches. Middleware installed with Use runs before routing, so the
+// router is asked directly.
+func matchRoute(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	match := chi.NewRouteContext()
+	if !rctx.Routes.Match(match, r.Method, r.URL.Path) {
+		return ""
+	}
+	return match.RoutePattern()
+}
+
+// caller identifies who a request counts against: the authenticated user,
+// or the client's IP address for anonymous requests
+func caller(r *http.Request) string {
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		return "user:" + strconv.FormatUint(uint64(identity.UserID), 10)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds rounds d up to whole seconds, as the headers require
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"api-gateway/grpc"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// REST returns a handler serving the routes generated from the services'
+// google.api.http annotations, e.g. GET /v1/orders/{id}, so an annotated RPC
+// needs no handler of its own. Calls go through clients, which forward the
+// caller's identity and request ID, and the backends authorize them as they
+// do any other call. JSON fields use the proto names, as the hand-written
+// routes do, and errors have the gateway's usual body.
+func REST(ctx context.Context, clients *grpc.ServiceClients) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		// The identity the backends trust is added by the clients; request
+		// headers, which callers control, are never turned into metadata
+		runtime.WithIncomingHeaderMatcher(func(string) (string, bool) { return "", false }),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(restError),
+		runtime.WithRoutingErrorHandler(restRoutingError),
+	)
+
+	if err := userv1.RegisterUserServiceHandlerClient(ctx, mux, clients.UserClient); err != nil {
+		return nil, err
+	}
+	if err := menuv1.RegisterMenuServiceHandlerClient(ctx, mux, clients.MenuClient); err != nil {
+		return nil, err
+	}
+	if err := orderv1.RegisterOrderServiceHandlerClient(ctx, mux, clients.OrderClient); err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate has verified the access token; the runtime would
+		// otherwise pass it on to the backends as metadata
+		r = r.Clone(r.Context())
+		r.Header.Del("Authorization")
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// restError writes errors from the generated routes like those of the
+// hand-written ones
+func restError(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	var malformed *runtime.HTTPStatusError
+	if errors.As(err, &malformed) {
+		// A path the runtime cannot parse, e.g. with a bad escape sequence
+		restRoutingError(ctx, mux, m, w, r, malformed.HTTPStatus)
+		return
+	}
+	handleGRPCError(w, r, err)
+}
+
+// restRoutingError answers requests no generated route matches, keeping the
+// HTTP status the runtime chose, e.g. 405 for a known path requested with
+// the wrong method
+func restRoutingError(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, httpStatus int) {
+	c := codes.NotFound
+	switch httpStatus {
+	case http.StatusBadRequest:
+		c = codes.InvalidArgument
+	case http.StatusMethodNotAllowed:
+		c = codes.Unimplemented
+	}
+	writeStatusCode(w, r, httpStatus, status.New(c, http.StatusText(httpStatus)))
+}
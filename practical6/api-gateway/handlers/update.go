@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// decodeUpdate decodes a PUT or PATCH body into dst and returns the update
+// mask to send with it. PUT replaces the resource, so it returns a nil mask,
+// which updates every field; PATCH returns the paths of the keys present in
+// the body. fieldPaths maps body keys to update mask paths.
+func decodeUpdate(r *http.Request, dst any, fieldPaths map[string]string) (*fieldmaskpb.FieldMask, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.New("invalid request body")
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return nil, errors.New("invalid request body")
+	}
+	if r.Method != http.MethodPatch {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, errors.New("request body must be a JSON object")
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mask := &fieldmaskpb.FieldMask{}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		path, ok := fieldPaths[key]
+		if !ok {
+			return nil, fmt.Errorf("field %q cannot be updated", key)
+		}
+		if !seen[path] {
+			mask.Paths = append(mask.Paths, path)
+			seen[path] = true
+		}
+	}
+	if len(mask.Paths) == 0 {
+		return nil, errors.New("request body does not set any fields")
+	}
+	return mask, nil
+}
+
+// hasPath reports whether an update mask includes path. A nil mask includes
+// every path.
+func hasPath(mask *fieldmaskpb.FieldMask, path string) bool {
+	if mask == nil {
+		return true
+	}
+	for _, p := range mask.Paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
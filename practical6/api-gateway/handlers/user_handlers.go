@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
 )
 
 // CreateUser handles POST /api/users
@@ -18,22 +18,24 @@ func (h *Handlers) CreateUser(w http.ResponseWriter, r *http.Request) {
 		Name        string `json:"name"`
 		Email       string `json:"email"`
 		IsCafeOwner bool   `json:"is_cafe_owner"`
+		Password    string `json:"password"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, r, codes.InvalidArgument, "invalid request body")
 		return
 	}
 
 	// Call gRPC service
-	resp, err := h.clients.UserClient.CreateUser(context.Background(), &userv1.CreateUserRequest{
+	resp, err := h.clients.UserClient.CreateUser(r.Context(), &userv1.CreateUserRequest{
 		Name:        req.Name,
 		Email:       req.Email,
 		IsCafeOwner: req.IsCafeOwner,
+		Password:    req.Password,
 	})
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
@@ -50,17 +52,17 @@ func (h *Handlers) GetUser(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		writeError(w, r, codes.InvalidArgument, "invalid user ID")
 		return
 	}
 
 	// Call gRPC service
-	resp, err := h.clients.UserClient.GetUser(context.Background(), &userv1.GetUserRequest{
+	resp, err := h.clients.UserClient.GetUser(r.Context(), &userv1.GetUserRequest{
 		Id: uint32(id),
 	})
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
@@ -72,15 +74,108 @@ func (h *Handlers) GetUser(w http.ResponseWriter, r *http.Request) {
 // GetUsers handles GET /api/users
 // Translates HTTP request to gRPC GetUsers call
 func (h *Handlers) GetUsers(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageParams(r)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, err.Error())
+		return
+	}
+	req := &userv1.GetUsersRequest{
+		PageSize:  page.PageSize,
+		PageToken: page.PageToken,
+		OrderBy:   page.OrderBy,
+	}
+
+	// Apply filters from query parameters
+	if raw := r.URL.Query().Get("is_cafe_owner"); raw != "" {
+		isCafeOwner, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, codes.InvalidArgument, "invalid is_cafe_owner")
+			return
+		}
+		req.IsCafeOwner = &isCafeOwner
+	}
+
 	// Call gRPC service
-	resp, err := h.clients.UserClient.GetUsers(context.Background(), &userv1.GetUsersRequest{})
+	resp, err := h.clients.UserClient.GetUsers(r.Context(), req)
 
 	if err != nil {
-		handleGRPCError(w, err)
+		handleGRPCError(w, r, err)
 		return
 	}
 
 	// Return HTTP JSON response
 	w.Header().Set("Content-Type", "application/json")
+	setNextPageToken(w, resp.NextPageToken)
 	json.NewEncoder(w).Encode(resp.Users)
 }
+
+// UpdateUser handles PUT and PATCH /api/users/{id}
+// Translates HTTP request to gRPC UpdateUser call
+func (h *Handlers) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid user ID")
+		return
+	}
+
+	// Parse HTTP JSON request body; PATCH only updates the fields it sets
+	var req struct {
+		Name        string `json:"name"`
+		Email       string `json:"email"`
+		IsCafeOwner bool   `json:"is_cafe_owner"`
+	}
+
+	mask, err := decodeUpdate(r, &req, map[string]string{
+		"name":          "name",
+		"email":         "email",
+		"is_cafe_owner": "is_cafe_owner",
+	})
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	// Call gRPC service
+	resp, err := h.clients.UserClient.UpdateUser(r.Context(), &userv1.UpdateUserRequest{
+		User: &userv1.User{
+			Id:          uint32(id),
+			Name:        req.Name,
+			Email:       req.Email,
+			IsCafeOwner: req.IsCafeOwner,
+		},
+		UpdateMask: mask,
+	})
+
+	if err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	// Return HTTP JSON response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.User)
+}
+
+// DeleteUser handles DELETE /api/users/{id}
+// Translates HTTP request to gRPC DeleteUser call
+func (h *Handlers) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeError(w, r, codes.InvalidArgument, "invalid user ID")
+		return
+	}
+
+	// Call gRPC service
+	if _, err := h.clients.UserClient.DeleteUser(r.Context(), &userv1.DeleteUserRequest{
+		Id: uint32(id),
+	}); err != nil {
+		handleGRPCError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
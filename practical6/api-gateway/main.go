@@ -1,49 +1,238 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"api-gateway/cache"
+	"api-gateway/graph"
 	"api-gateway/grpc"
 	"api-gateway/handlers"
+	"api-gateway/ratelimit"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	"github.com/redis/go-redis/v9"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/logging"
+	"student-cafe-shared/metrics"
+	"student-cafe-shared/shutdown"
+	"student-cafe-shared/tracing"
 )
 
 func main() {
+	// Log JSON lines to stdout
+	if err := logging.Setup("api-gateway"); err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
+	// SIGTERM (or Ctrl-C) starts a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// How long in-flight requests may run after shutdown starts (e.g. "30s")
+	drainTimeout, err := shutdown.Timeout()
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
+	// Export spans to the backend named by OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(ctx, "api-gateway")
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", "error", err)
+	}
+
+	// Get metrics port from environment
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9100"
+	}
+
+	// Cache menu reads for CACHE_TTL, dropping them whenever the menu is
+	// changed through this gateway
+	cacheTTL, err := cache.TTL()
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+	menuCache := cache.New("menu", cacheTTL)
+
 	// Initialize gRPC clients for all backend services
-	clients, err := grpc.NewServiceClients()
+	clients, err := grpc.NewServiceClients(menuCache.Purge)
+	if err != nil {
+		logging.Fatal("Failed to create gRPC clients", "error", err)
+	}
+	slog.Info("gRPC clients initialized successfully")
+
+	// Load the public key used to verify access tokens issued by user-service
+	keyFile := os.Getenv("JWT_PUBLIC_KEY_FILE")
+	if keyFile == "" {
+		logging.Fatal("JWT_PUBLIC_KEY_FILE must be set (run 'make jwt-keys' to generate a key pair)")
+	}
+	publicKey, err := auth.LoadPublicKey(keyFile)
 	if err != nil {
-		log.Fatalf("Failed to create gRPC clients: %v", err)
+		logging.Fatal("Failed to load JWT public key", "error", err)
+	}
+
+	// Keep rate limit buckets in Redis when REDIS_URL is set, so every
+	// gateway replica shares them, and in memory otherwise
+	var limitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			logging.Fatal("Invalid REDIS_URL", "error", err)
+		}
+		redisClient := redis.NewClient(opts)
+		defer redisClient.Close()
+		limitStore = ratelimit.NewRedisStore(redisClient)
+	}
+
+	// Token buckets per user, or per IP for anonymous callers: reads are
+	// generous, writes stricter, and logins and orders have their own
+	// buckets
+	limits := handlers.RateLimits{
+		Reads:  ratelimit.PerMinute(300, 60),
+		Writes: ratelimit.PerMinute(60, 20),
+		Routes: map[string]ratelimit.Limit{
+			"POST /api/auth/login": ratelimit.PerMinute(10, 5),
+			"POST /v1/auth/login":  ratelimit.PerMinute(10, 5),
+			"POST /api/orders":     ratelimit.PerMinute(30, 10),
+			"POST /v1/orders":      ratelimit.PerMinute(30, 10),
+			"POST /graphql":        ratelimit.PerMinute(300, 60), // queries only read
+			"GET /healthz":         {},                           // probes are never limited
+			"GET /readyz":          {},
+		},
 	}
-	log.Println("gRPC clients initialized successfully")
 
 	// Create handlers with gRPC clients
 	h := handlers.NewHandlers(clients)
 
+	// Routes generated from the services' google.api.http annotations, and
+	// the OpenAPI document describing them
+	rest, err := handlers.REST(ctx, clients)
+	if err != nil {
+		logging.Fatal("Failed to register REST routes", "error", err)
+	}
+	openAPI, err := handlers.OpenAPI()
+	if err != nil {
+		logging.Fatal("Failed to load OpenAPI document", "error", err)
+	}
+
 	// Setup HTTP router
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(handlers.RequestID)
+	r.Use(handlers.Trace)
+	r.Use(handlers.Metrics)
+	r.Use(handlers.Logger)
+	r.Use(handlers.Recover)
 
-	// User routes - HTTP to gRPC translation
+	r.Use(handlers.Authenticate(auth.NewTokenVerifier(publicKey)))
+	r.Use(handlers.RateLimit(limitStore, limits))
+
+	// Liveness and readiness probes
+	r.Get("/healthz", h.Healthz)
+	r.Get("/readyz", h.Readyz)
+
+	// Public routes: sign-up, login and browsing the menu
+	r.Post("/api/auth/login", h.Login)
+	r.Post("/api/auth/refresh", h.RefreshToken)
 	r.Post("/api/users", h.CreateUser)
-	r.Get("/api/users/{id}", h.GetUser)
-	r.Get("/api/users", h.GetUsers)
+	r.With(handlers.Cache(menuCache)).Get("/api/menu/{id}", h.GetMenuItem)
+	r.With(handlers.Cache(menuCache)).Get("/api/menu", h.GetMenu)
+	r.Get("/api/categories", h.GetCategories)
+
+	// GraphQL queries over users, the menu and orders; resolvers require an
+	// access token where the /api routes do
+	r.Handle("/graphql", graph.NewHandler(clients))
+
+	// API description, and a page to browse and try it
+	r.Get("/openapi.json", openAPI)
+	r.Get("/docs", handlers.SwaggerUI)
+
+	// Generated REST routes open to anonymous callers, as above
+	r.Post("/v1/auth/login", rest.ServeHTTP)
+	r.Post("/v1/auth/refresh", rest.ServeHTTP)
+	r.Post("/v1/users", rest.ServeHTTP)
+	r.Get("/v1/menu/{id}", rest.ServeHTTP)
+	r.Get("/v1/menu:batchGet", rest.ServeHTTP)
+	r.Get("/v1/menu", rest.ServeHTTP)
+	r.Get("/v1/categories", rest.ServeHTTP)
 
-	// Menu routes - HTTP to gRPC translation
-	r.Post("/api/menu", h.CreateMenuItem)
-	r.Get("/api/menu/{id}", h.GetMenuItem)
-	r.Get("/api/menu", h.GetMenu)
+	// Everything else requires a valid access token
+	r.Group(func(r chi.Router) {
+		r.Use(handlers.RequireAuth)
 
-	// Order routes - HTTP to gRPC translation
-	r.Post("/api/orders", h.CreateOrder)
-	r.Get("/api/orders/{id}", h.GetOrder)
-	r.Get("/api/orders", h.GetOrders)
+		// User routes - HTTP to gRPC translation
+		r.Get("/api/users/{id}", h.GetUser)
+		r.Get("/api/users", h.GetUsers)
+		r.Put("/api/users/{id}", h.UpdateUser)
+		r.Patch("/api/users/{id}", h.UpdateUser)
+		r.Delete("/api/users/{id}", h.DeleteUser)
 
-	log.Println("API Gateway starting on :8080 (HTTP→gRPC translation layer)")
-	if err := http.ListenAndServe(":8080", r); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		// Menu routes - HTTP to gRPC translation
+		r.Post("/api/menu", h.CreateMenuItem)
+		r.Put("/api/menu/{id}", h.UpdateMenuItem)
+		r.Patch("/api/menu/{id}", h.UpdateMenuItem)
+		r.Delete("/api/menu/{id}", h.DeleteMenuItem)
+		r.Put("/api/menu/{id}/availability", h.SetMenuItemAvailability)
+		r.Put("/api/menu/{id}/stock", h.SetMenuItemStock)
+
+		// Category routes - HTTP to gRPC translation
+		r.Post("/api/categories", h.CreateCategory)
+
+		// Order routes - HTTP to gRPC translation
+		r.Post("/api/orders", h.CreateOrder)
+		r.Get("/api/orders/{id}", h.GetOrder)
+		r.Get("/api/orders", h.GetOrders)
+		r.Patch("/api/orders/{id}/status", h.UpdateOrderStatus)
+		r.Post("/api/orders/{id}/cancel", h.CancelOrder)
+		r.Delete("/api/orders/{id}", h.DeleteOrder)
+		r.Get("/api/orders/{id}/events", h.StreamOrderEvents)
+
+		// The other generated REST routes; new orders are routed separately
+		// to match their rate limit bucket
+		r.Post("/v1/orders", rest.ServeHTTP)
+		r.Handle("/v1/*", rest)
+	})
+
+	// Serve Prometheus metrics on their own port until shutdown
+	go func() {
+		if err := metrics.Serve(ctx, metrics.NewServer(":"+metricsPort)); err != nil {
+			logging.Fatal("Metrics server failed", "error", err)
+		}
+	}()
+
+	srv := &http.Server{Addr: ":8080", Handler: r}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	slog.Info("API Gateway starting (HTTP→gRPC translation layer)", "http_port", "8080", "metrics_port", metricsPort)
+
+	select {
+	case err := <-serveErr:
+		logging.Fatal("Failed to start server", "error", err)
+	case <-ctx.Done():
+		stop() // a second signal kills the process immediately
+	}
+
+	// Stop accepting connections and let in-flight requests finish
+	slog.Info("Shutting down, draining in-flight requests", "timeout", drainTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Long-lived responses such as order event streams are cut off
+		slog.Warn("Drain timeout exceeded, closing remaining connections", "error", err)
+		srv.Close()
+	}
+	if err := clients.Close(); err != nil {
+		slog.Error("Failed to close backend connections", "error", err)
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
+	slog.Info("API Gateway stopped")
 }
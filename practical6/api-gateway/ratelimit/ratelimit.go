@@ -0,0 +1,83 @@
+// Package ratelimit limits how often each caller may use the api-gateway
+// with token buckets.
+//
+// A bucket holds up to Limit.Burst tokens and refills at Limit.Requests
+// tokens per Limit.Period; every request takes a token and is rejected when
+// none is left. Buckets live in a Store: MemoryStore keeps them in the
+// gateway process, and RedisStore in a Redis-compatible server so that
+// several gateway replicas share them.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is the size and refill rate of a token bucket. The zero Limit is
+// unlimited.
+type Limit struct {
+	Requests int           // tokens added per Period
+	Period   time.Duration // e.g. time.Minute
+	Burst    int           // capacity of the bucket
+}
+
+// PerMinute returns a limit of requests per minute, of which up to burst
+// may be made at once
+func PerMinute(requests, burst int) Limit {
+	return Limit{Requests: requests, Period: time.Minute, Burst: burst}
+}
+
+// Unlimited reports whether l lets every request through
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Period <= 0 || l.Burst <= 0
+}
+
+// interval returns the time it takes to add one token
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left in the bucket
+	RetryAfter time.Duration // until a token is available, when not Allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+// Store holds token buckets
+type Store interface {
+	// Take refills the bucket named key up to now at the rate of limit and
+	// takes a token from it if one is available. A bucket that does not
+	// exist yet is full.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is the state of a token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills b up to now and takes a token from it if one is available.
+// RedisStore's script does the same.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	interval := limit.interval()
+	burst := float64(limit.Burst)
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+float64(elapsed)/float64(interval))
+		b.updated = now
+	}
+
+	var result Result
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) * float64(interval)))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration(math.Ceil((burst - b.tokens) * float64(interval)))
+	return result
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stores returns every Store implementation, the Redis one backed by an
+// in-process stand-in for a Redis server
+func stores(t *testing.T) map[string]Store {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"redis":  NewRedisStore(client),
+	}
+}
+
+func TestStore(t *testing.T) {
+	// One token a second, three at once
+	limit := PerMinute(60, 3)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			take := func(key string, at time.Duration) Result {
+				t.Helper()
+				result, err := store.Take(ctx, key, limit, start.Add(at))
+				require.NoError(t, err)
+				return result
+			}
+
+			t.Run("the burst is allowed, then requests are rejected", func(t *testing.T) {
+				for remaining := 2; remaining >= 0; remaining-- {
+					result := take("burst", 0)
+					assert.True(t, result.Allowed)
+					assert.Equal(t, remaining, result.Remaining)
+				}
+
+				result := take("burst", 0)
+				assert.False(t, result.Allowed)
+				assert.Equal(t, 0, result.Remaining)
+				assert.Equal(t, time.Second, result.RetryAfter)
+				assert.Equal(t, 3*time.Second, result.Reset)
+			})
+
+			t.Run("tokens are added over time", func(t *testing.T) {
+				result := take("burst", 1500*time.Millisecond)
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 0, result.Remaining)
+
+				result = take("burst", 1600*time.Millisecond)
+				assert.False(t, result.Allowed)
+				assert.Equal(t, 400*time.Millisecond, result.RetryAfter)
+			})
+
+			t.Run("buckets never hold more than the burst", func(t *testing.T) {
+				result := take("burst", time.Hour)
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 2, result.Remaining)
+				assert.Equal(t, time.Second, result.Reset)
+			})
+
+			t.Run("a clock running behind adds no tokens", func(t *testing.T) {
+				take("skew", time.Minute)
+				take("skew", time.Minute)
+				result := take("skew", 0)
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 0, result.Remaining)
+			})
+
+			t.Run("callers have their own buckets", func(t *testing.T) {
+				for range 3 {
+					take("alice", 0)
+				}
+				assert.False(t, take("alice", 0).Allowed)
+				assert.True(t, take("bob", 0).Allowed)
+			})
+		})
+	}
+}
+
+func TestMemoryStoreForgetsFullBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	limit := PerMinute(60, 3)
+	start := time.Now()
+
+	_, err := store.Take(context.Background(), "idle", limit, start)
+	require.NoError(t, err)
+	_, err = store.Take(context.Background(), "busy", limit, start.Add(sweepInterval))
+	require.NoError(t, err)
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
+
+func TestRedisStoreExpiresBuckets(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	_, err := NewRedisStore(client).Take(context.Background(), "user:1", PerMinute(60, 3), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, server.TTL("ratelimit:user:1"))
+}
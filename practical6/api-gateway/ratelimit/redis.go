@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript is bucket.take run atomically in Redis. The bucket is a hash
+// of its tokens and the time in milliseconds it was last refilled, and
+// expires once it would have refilled anyway.
+var takeScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens, updated = burst, now
+end
+if now > updated then
+	tokens = math.min(burst, tokens + (now - updated) / interval)
+	updated = now
+end
+
+local allowed, retry = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * interval)
+end
+local reset = math.ceil((burst - tokens) * interval)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(updated))
+redis.call('PEXPIRE', KEYS[1], math.max(reset, 1))
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// RedisStore keeps token buckets in a Redis-compatible server, so every
+// gateway replica using the same server shares them. Keys are prefixed with
+// "ratelimit:".
+type RedisStore struct {
+	client redis.Scripter
+}
+
+// NewRedisStore returns a RedisStore using client, e.g. a *redis.Client
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Take implements Store. Replicas' clocks should roughly agree, as each
+// passes its own time to the server.
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	interval := float64(limit.interval()) / float64(time.Millisecond)
+	values, err := takeScript.Run(ctx, s.client, []string{"ratelimit:" + key},
+		limit.Burst, interval, now.UnixMilli()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("take token from %s: %w", key, err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("take token from %s: unexpected reply %v", key, values)
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
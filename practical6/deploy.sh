@@ -29,6 +29,23 @@ cd ..
 echo -e "${GREEN}✓ Proto code generated successfully${NC}"
 echo ""
 
+# Generate the token signing keys once; existing keys are kept so issued
+# tokens stay valid across deployments
+if [ ! -f keys/jwt-private.pem ]; then
+    echo "Generating JWT signing keys..."
+    (cd student-cafe-shared && go run ./cmd/jwtkeys -dir ../keys)
+    echo -e "${GREEN}✓ JWT signing keys written to keys/${NC}"
+    echo ""
+fi
+
+# Generate the local CA and service certificates for mutual TLS once
+if [ ! -f certs/ca.pem ]; then
+    echo "Generating development certificates..."
+    (cd student-cafe-shared && go run ./cmd/devcerts -dir ../certs)
+    echo -e "${GREEN}✓ Certificates written to certs/${NC}"
+    echo ""
+fi
+
 # Step 2: Stop and remove existing containers
 echo -e "${BLUE}Step 2: Cleaning up existing containers${NC}"
 docker-compose down -v 2>/dev/null || true
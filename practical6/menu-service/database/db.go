@@ -1,28 +1,63 @@
 package database
 
 import (
-	"log"
-	"menu-service/models"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"student-cafe-shared/migrate"
+	"student-cafe-shared/tracing"
 )
 
-var DB *gorm.DB
+// MigrationsDir is the source directory of the menu database migrations,
+// relative to the service module
+const MigrationsDir = "database/migrations"
 
-func Connect(dsn string) error {
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations returns the SQL migrations built into the binary
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrationFiles, "migrations")
 	if err != nil {
-		return err
+		panic(err)
 	}
+	return sub
+}
 
-	// Only migrate menu-related tables
-	err = DB.AutoMigrate(&models.MenuItem{})
+// Open opens the menu database without checking its schema. Every query is
+// traced as a child of the span in its context.
+func Open(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Connect opens the menu database and refuses to use it unless its schema
+// is at exactly the version of the migrations built into the binary
+func Connect(dsn string) (*gorm.DB, error) {
+	db, err := Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrate.New(db, Migrations())
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		return nil, fmt.Errorf("checking menu database schema: %w", err)
 	}
 
-	log.Println("Menu database connected")
-	return nil
+	slog.Info("Menu database connected", "schema_version", migrator.Latest())
+	return db, nil
 }
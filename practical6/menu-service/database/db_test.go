@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"menu-service/models"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"student-cafe-shared/migrate"
+)
+
+// legacyMenuItem is the MenuItem schema from before prices were stored in
+// minor units and items had a category, availability or stock
+type legacyMenuItem struct {
+	gorm.Model
+	Name        string
+	Description string
+	Price       float64
+}
+
+func (legacyMenuItem) TableName() string { return "menu_items" }
+
+func TestMigrations(t *testing.T) {
+	migrations, err := migrate.Load(Migrations())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, "0001_baseline", migrations[0].String())
+}
+
+// openTestDB connects to the Postgres database named by POSTGRES_TEST_DSN,
+// which must not be shared with anything else: its menu tables are dropped
+func openTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	drop := func() {
+		require.NoError(t, db.Migrator().DropTable("menu_items", "categories", "schema_migrations"))
+	}
+	drop()
+	t.Cleanup(drop)
+	return db
+}
+
+func TestBaseline_UpgradesLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// Recreate the schema AutoMigrate built for the first release
+	require.NoError(t, db.AutoMigrate(&legacyMenuItem{}))
+	require.NoError(t, db.Create([]legacyMenuItem{
+		{Name: "Coffee", Price: 2.50},
+		{Name: "Muffin", Price: 3.99},
+		{Name: "Water", Price: 0.10},
+	}).Error)
+
+	migrator, err := migrate.New(db, Migrations())
+	require.NoError(t, err)
+	assert.ErrorIs(t, migrator.Check(ctx), migrate.ErrSchemaBehind)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	assert.False(t, db.Migrator().HasColumn(&models.MenuItem{}, "price"))
+
+	var items []models.MenuItem
+	require.NoError(t, db.Order("id").Find(&items).Error)
+	require.Len(t, items, 3)
+	assert.Equal(t, int64(250), items[0].PriceMinor)
+	assert.Equal(t, int64(399), items[1].PriceMinor)
+	assert.Equal(t, int64(10), items[2].PriceMinor)
+	assert.Equal(t, models.DefaultCurrency, items[0].Currency)
+
+	// Existing items stay on the menu; new items keep the availability they
+	// are created with
+	assert.True(t, items[0].Available)
+	assert.Nil(t, items[0].Stock)
+
+	category := models.Category{Name: "Pies"}
+	require.NoError(t, db.Create(&category).Error)
+	hidden := models.MenuItem{Name: "Seasonal Pie", Available: false, CategoryID: &category.ID}
+	require.NoError(t, db.Create(&hidden).Error)
+
+	var reloaded models.MenuItem
+	require.NoError(t, db.First(&reloaded, hidden.ID).Error)
+	assert.False(t, reloaded.Available)
+	assert.Equal(t, models.DefaultCurrency, reloaded.Currency)
+}
+
+func TestBaseline_UpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrator, err := migrate.New(db, Migrations())
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// The schema accepts everything the models write
+	require.NoError(t, db.Create(&models.Category{Name: "Drinks"}).Error)
+	assert.Error(t, db.Create(&models.Category{Name: "Drinks"}).Error)
+	stock := int32(5)
+	require.NoError(t, db.Create(&models.MenuItem{Name: "Coffee", PriceMinor: 250, Currency: "EUR", Stock: &stock}).Error)
+
+	_, err = migrator.Down(ctx, int(migrator.Latest()))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable(&models.MenuItem{}))
+	assert.False(t, db.Migrator().HasTable(&models.Category{}))
+}
@@ -0,0 +1,52 @@
+-- Baseline schema. Databases created by the AutoMigrate calls this service
+-- used to make at startup already have some of it, so every statement is
+-- written to adopt an existing table rather than fail on it.
+
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name VARCHAR(100),
+    description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS menu_items (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name TEXT,
+    description TEXT
+);
+
+-- Columns added after the first release. available is added with a true
+-- default so items that predate availability tracking stay on the menu;
+-- new items must say whether they are available.
+ALTER TABLE menu_items
+    ADD COLUMN IF NOT EXISTS price_minor BIGINT,
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS category_id BIGINT
+        CONSTRAINT fk_categories_menu_items REFERENCES categories (id),
+    ADD COLUMN IF NOT EXISTS available BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS stock INTEGER;
+ALTER TABLE menu_items ALTER COLUMN available SET DEFAULT false;
+
+-- The first release stored prices as floats; convert them to minor units
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND table_name = 'menu_items' AND column_name = 'price'
+    ) THEN
+        UPDATE menu_items SET price_minor = ROUND(price * 100);
+        ALTER TABLE menu_items DROP COLUMN price;
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_menu_items_deleted_at ON menu_items (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_menu_items_category_id ON menu_items (category_id);
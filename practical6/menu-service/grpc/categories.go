@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"menu-service/models"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/grpcerr"
+)
+
+// CreateCategory creates a new menu category
+func (s *MenuServer) CreateCategory(ctx context.Context, req *menuv1.CreateCategoryRequest) (*menuv1.CreateCategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, grpcerr.InvalidField("name", "category name is required")
+	}
+
+	category := models.Category{
+		Name:        name,
+		Description: req.Description,
+	}
+	if err := s.Menu.CreateCategory(ctx, &category); err != nil {
+		// Category names carry a unique index
+		if dberr.IsUniqueViolation(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "category %q already exists", name)
+		}
+		return nil, dberr.Error(err, "failed to create category")
+	}
+
+	return &menuv1.CreateCategoryResponse{
+		Category: categoryToProto(&category),
+	}, nil
+}
+
+// GetCategories retrieves all categories ordered by name
+func (s *MenuServer) GetCategories(ctx context.Context, req *menuv1.GetCategoriesRequest) (*menuv1.GetCategoriesResponse, error) {
+	categories, err := s.Menu.ListCategories(ctx)
+	if err != nil {
+		return nil, dberr.Error(err, "failed to get categories")
+	}
+
+	protoCategories := make([]*menuv1.Category, len(categories))
+	for i, category := range categories {
+		protoCategories[i] = categoryToProto(&category)
+	}
+
+	return &menuv1.GetCategoriesResponse{
+		Categories: protoCategories,
+	}, nil
+}
+
+// categoryToProto converts a GORM Category model to proto Category message
+func categoryToProto(category *models.Category) *menuv1.Category {
+	return &menuv1.Category{
+		Id:          uint32(category.ID),
+		Name:        category.Name,
+		Description: category.Description,
+		CreatedAt:   category.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   category.UpdatedAt.Format(time.RFC3339),
+	}
+}
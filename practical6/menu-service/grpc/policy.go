@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+	"student-cafe-shared/mtls"
+)
+
+// Policy returns the authorization policy of the MenuService: only cafe
+// owners may change the menu. Reads stay public, and ReserveStock and
+// ReleaseStock are open to every signed-in user because order-service calls
+// them on behalf of students placing orders; Callers keeps other services
+// from calling them.
+func Policy() authz.Policy {
+	ownerOnly := authz.RequireRole(auth.RoleCafeOwner)
+	anyUser := authz.RequireRole(auth.RoleCafeOwner, auth.RoleStudent)
+	return authz.Policy{
+		menuv1.MenuService_ReserveStock_FullMethodName:            anyUser,
+		menuv1.MenuService_ReleaseStock_FullMethodName:            anyUser,
+		menuv1.MenuService_CreateMenuItem_FullMethodName:          ownerOnly,
+		menuv1.MenuService_UpdateMenuItem_FullMethodName:          ownerOnly,
+		menuv1.MenuService_DeleteMenuItem_FullMethodName:          ownerOnly,
+		menuv1.MenuService_SetMenuItemAvailability_FullMethodName: ownerOnly,
+		menuv1.MenuService_SetMenuItemStock_FullMethodName:        ownerOnly,
+		menuv1.MenuService_CreateCategory_FullMethodName:          ownerOnly,
+	}
+}
+
+// Callers returns the services allowed to call each MenuService method, told
+// apart by their certificates under mutual TLS: only order-service holds
+// stock for orders. The other methods are open to every service.
+func Callers() mtls.Policy {
+	orderService := []string{"order-service"}
+	return mtls.Policy{
+		menuv1.MenuService_ReserveStock_FullMethodName: orderService,
+		menuv1.MenuService_ReleaseStock_FullMethodName: orderService,
+	}
+}
@@ -2,46 +2,112 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	commonv1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
-	"menu-service/database"
 	"menu-service/models"
+	"menu-service/repository"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/fieldmask"
+	"student-cafe-shared/grpcerr"
+	"student-cafe-shared/pagination"
 )
 
 // MenuServer implements the gRPC MenuService
 type MenuServer struct {
 	menuv1.UnimplementedMenuServiceServer
+
+	// Menu stores the service's menu items and categories
+	Menu repository.MenuRepository
 }
 
-// NewMenuServer creates a new gRPC menu server
-func NewMenuServer() *MenuServer {
-	return &MenuServer{}
+// NewMenuServer creates a new gRPC menu server backed by menu
+func NewMenuServer(menu repository.MenuRepository) *MenuServer {
+	return &MenuServer{Menu: menu}
 }
 
 // GetMenuItem retrieves a menu item by ID
 func (s *MenuServer) GetMenuItem(ctx context.Context, req *menuv1.GetMenuItemRequest) (*menuv1.GetMenuItemResponse, error) {
-	var menuItem models.MenuItem
-	if err := database.DB.First(&menuItem, req.Id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	menuItem, err := s.Menu.GetItem(ctx, uint(req.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "menu item not found")
 		}
-		return nil, status.Errorf(codes.Internal, "failed to get menu item: %v", err)
+		return nil, dberr.Error(err, "failed to get menu item")
 	}
 
 	return &menuv1.GetMenuItemResponse{
-		MenuItem: modelToProto(&menuItem),
+		MenuItem: modelToProto(menuItem),
 	}, nil
 }
 
-// GetMenu retrieves all menu items
+// BatchGetMenuItems retrieves several menu items by ID in one query and
+// reports the IDs that do not exist
+func (s *MenuServer) BatchGetMenuItems(ctx context.Context, req *menuv1.BatchGetMenuItemsRequest) (*menuv1.BatchGetMenuItemsResponse, error) {
+	ids := make([]uint, len(req.Ids))
+	for i, id := range req.Ids {
+		ids[i] = uint(id)
+	}
+	items, err := s.Menu.GetItems(ctx, ids)
+	if err != nil {
+		return nil, dberr.Error(err, "failed to get menu items")
+	}
+
+	found := make(map[uint32]*models.MenuItem, len(items))
+	for i := range items {
+		found[uint32(items[i].ID)] = &items[i]
+	}
+
+	resp := &menuv1.BatchGetMenuItemsResponse{}
+	seen := make(map[uint32]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if item, ok := found[id]; ok {
+			resp.MenuItems = append(resp.MenuItems, modelToProto(item))
+		} else {
+			resp.MissingIds = append(resp.MissingIds, id)
+		}
+	}
+	return resp, nil
+}
+
+// GetMenu retrieves a page of menu items, optionally filtered by price range
+// and name
 func (s *MenuServer) GetMenu(ctx context.Context, req *menuv1.GetMenuRequest) (*menuv1.GetMenuResponse, error) {
-	var menuItems []models.MenuItem
-	if err := database.DB.Find(&menuItems).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get menu: %v", err)
+	sort, err := pagination.ParseSort(req.OrderBy, repository.SortFields, pagination.Sort{Field: "id", Column: "id"})
+	if err != nil {
+		return nil, grpcerr.InvalidField("order_by", "%v", err)
+	}
+
+	filter := repository.ItemFilter{
+		MinPriceMinor: req.MinPriceMinor,
+		MaxPriceMinor: req.MaxPriceMinor,
+		AvailableOnly: req.AvailableOnly,
+		NameContains:  req.NameContains,
+	}
+	if req.CategoryId != nil {
+		categoryID := uint(req.GetCategoryId())
+		filter.CategoryID = &categoryID
+	}
+
+	menuItems, nextPageToken, err := s.Menu.ListItems(ctx, filter, pagination.Request{
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+		Sort:      sort,
+	})
+	if errors.Is(err, pagination.ErrInvalidPageToken) {
+		return nil, grpcerr.InvalidField("page_token", "%v", err)
+	}
+	if err != nil {
+		return nil, dberr.Error(err, "failed to get menu")
 	}
 
 	protoItems := make([]*menuv1.MenuItem, len(menuItems))
@@ -50,20 +116,39 @@ func (s *MenuServer) GetMenu(ctx context.Context, req *menuv1.GetMenuRequest) (*
 	}
 
 	return &menuv1.GetMenuResponse{
-		MenuItems: protoItems,
+		MenuItems:     protoItems,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
 // CreateMenuItem creates a new menu item
 func (s *MenuServer) CreateMenuItem(ctx context.Context, req *menuv1.CreateMenuItemRequest) (*menuv1.CreateMenuItemResponse, error) {
+	currency := req.GetPrice().GetCurrencyCode()
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	if req.Stock != nil && req.GetStock() < 0 {
+		return nil, grpcerr.InvalidField("stock", "stock cannot be negative")
+	}
+
 	menuItem := models.MenuItem{
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
+		PriceMinor:  req.GetPrice().GetAmountMinor(),
+		Currency:    currency,
+		Available:   req.Available == nil || req.GetAvailable(),
+		Stock:       req.Stock,
+	}
+
+	categoryID, err := s.lookupCategoryID(ctx, "category_id", req.CategoryId)
+	if err != nil {
+		return nil, err
 	}
+	menuItem.CategoryID = categoryID
 
-	if err := database.DB.Create(&menuItem).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create menu item: %v", err)
+	if err := s.Menu.CreateItem(ctx, &menuItem); err != nil {
+		return nil, dberr.Error(err, "failed to create menu item")
 	}
 
 	return &menuv1.CreateMenuItemResponse{
@@ -71,14 +156,120 @@ func (s *MenuServer) CreateMenuItem(ctx context.Context, req *menuv1.CreateMenuI
 	}, nil
 }
 
+// updatableMenuItemFields are the update_mask paths UpdateMenuItem accepts
+var updatableMenuItemFields = []string{"name", "description", "price", "category_id", "available", "stock"}
+
+// UpdateMenuItem updates the fields of a menu item named in the update mask
+func (s *MenuServer) UpdateMenuItem(ctx context.Context, req *menuv1.UpdateMenuItemRequest) (*menuv1.UpdateMenuItemResponse, error) {
+	if req.MenuItem == nil {
+		return nil, grpcerr.InvalidField("menu_item", "menu_item is required")
+	}
+
+	paths, err := fieldmask.Paths(req.UpdateMask, updatableMenuItemFields)
+	if err != nil {
+		return nil, grpcerr.InvalidField("update_mask", "%v", err)
+	}
+
+	menuItem, err := s.Menu.GetItem(ctx, uint(req.MenuItem.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "menu item not found")
+		}
+		return nil, dberr.Error(err, "failed to get menu item")
+	}
+
+	columns := make([]string, 0, len(paths)+1)
+	for _, path := range paths {
+		switch path {
+		case "name":
+			menuItem.Name = req.MenuItem.Name
+			columns = append(columns, "name")
+		case "description":
+			menuItem.Description = req.MenuItem.Description
+			columns = append(columns, "description")
+		case "price":
+			// Keep the item's currency when only the amount is given
+			menuItem.PriceMinor = req.MenuItem.GetPrice().GetAmountMinor()
+			columns = append(columns, "price_minor")
+			if currency := req.MenuItem.GetPrice().GetCurrencyCode(); currency != "" {
+				menuItem.Currency = currency
+				columns = append(columns, "currency")
+			}
+		case "category_id":
+			categoryID, err := s.lookupCategoryID(ctx, "menu_item.category_id", req.MenuItem.CategoryId)
+			if err != nil {
+				return nil, err
+			}
+			menuItem.CategoryID = categoryID
+			columns = append(columns, "category_id")
+		case "available":
+			menuItem.Available = req.MenuItem.Available
+			columns = append(columns, "available")
+		case "stock":
+			if req.MenuItem.Stock != nil && req.MenuItem.GetStock() < 0 {
+				return nil, grpcerr.InvalidField("menu_item.stock", "stock cannot be negative")
+			}
+			menuItem.Stock = req.MenuItem.Stock
+			columns = append(columns, "stock")
+		}
+	}
+
+	if err := s.Menu.UpdateItem(ctx, menuItem, columns...); err != nil {
+		return nil, dberr.Error(err, "failed to update menu item")
+	}
+
+	return &menuv1.UpdateMenuItemResponse{
+		MenuItem: modelToProto(menuItem),
+	}, nil
+}
+
+// DeleteMenuItem soft-deletes a menu item. Orders keep their price snapshot
+// of it.
+func (s *MenuServer) DeleteMenuItem(ctx context.Context, req *menuv1.DeleteMenuItemRequest) (*menuv1.DeleteMenuItemResponse, error) {
+	if err := s.Menu.DeleteItem(ctx, uint(req.Id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "menu item not found")
+		}
+		return nil, dberr.Error(err, "failed to delete menu item")
+	}
+
+	return &menuv1.DeleteMenuItemResponse{}, nil
+}
+
+// lookupCategoryID checks that the category referenced by a request field
+// exists. It returns nil for id 0, which means no category.
+func (s *MenuServer) lookupCategoryID(ctx context.Context, field string, id uint32) (*uint, error) {
+	if id == 0 {
+		return nil, nil
+	}
+
+	category, err := s.Menu.GetCategory(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, grpcerr.InvalidField(field, "category %d not found", id)
+		}
+		return nil, dberr.Error(err, "failed to get category")
+	}
+	return &category.ID, nil
+}
+
 // modelToProto converts a GORM MenuItem model to proto MenuItem message
 func modelToProto(item *models.MenuItem) *menuv1.MenuItem {
-	return &menuv1.MenuItem{
+	protoItem := &menuv1.MenuItem{
 		Id:          uint32(item.ID),
 		Name:        item.Name,
 		Description: item.Description,
-		Price:       item.Price,
-		CreatedAt:   item.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   item.UpdatedAt.Format(time.RFC3339),
+		Price: &commonv1.Money{
+			CurrencyCode: item.Currency,
+			AmountMinor:  item.PriceMinor,
+		},
+		Available: item.Available,
+		Stock:     item.Stock,
+		CreatedAt: item.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: item.UpdatedAt.Format(time.RFC3339),
+	}
+	if item.CategoryID != nil {
+		protoItem.CategoryId = uint32(*item.CategoryID)
 	}
+	return protoItem
 }
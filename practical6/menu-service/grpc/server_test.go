@@ -2,18 +2,29 @@ package grpc
 
 import (
 	"context"
-	"menu-service/database"
+	"crypto/tls"
+	"crypto/x509"
 	"menu-service/models"
+	"menu-service/repository"
 	"testing"
 	"time"
 
+	commonv1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+	"student-cafe-shared/mtls"
 )
 
 // setupTestDB creates an in-memory SQLite database for testing
@@ -21,8 +32,8 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
 	require.NoError(t, err, "Failed to open test database")
 
-	// Auto-migrate the MenuItem model
-	err = db.AutoMigrate(&models.MenuItem{})
+	// Auto-migrate the menu models
+	err = db.AutoMigrate(&models.Category{}, &models.MenuItem{})
 	require.NoError(t, err, "Failed to migrate test database")
 
 	return db
@@ -39,9 +50,7 @@ func TestCreateMenuItem(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
-
-	server := NewMenuServer()
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
 
 	tests := []struct {
 		name    string
@@ -53,7 +62,7 @@ func TestCreateMenuItem(t *testing.T) {
 			request: &menuv1.CreateMenuItemRequest{
 				Name:        "Cappuccino",
 				Description: "Espresso with steamed milk and foam",
-				Price:       4.50,
+				Price:       &commonv1.Money{AmountMinor: 450},
 			},
 			wantErr: false,
 		},
@@ -62,7 +71,7 @@ func TestCreateMenuItem(t *testing.T) {
 			request: &menuv1.CreateMenuItemRequest{
 				Name:        "Water",
 				Description: "Free water",
-				Price:       0.0,
+				Price:       &commonv1.Money{AmountMinor: 0},
 			},
 			wantErr: false,
 		},
@@ -71,7 +80,7 @@ func TestCreateMenuItem(t *testing.T) {
 			request: &menuv1.CreateMenuItemRequest{
 				Name:        "Special Brew",
 				Description: "A very long description that describes the coffee in great detail with many words",
-				Price:       5.99,
+				Price:       &commonv1.Money{AmountMinor: 599},
 			},
 			wantErr: false,
 		},
@@ -90,7 +99,8 @@ func TestCreateMenuItem(t *testing.T) {
 				assert.NotZero(t, resp.MenuItem.Id)
 				assert.Equal(t, tt.request.Name, resp.MenuItem.Name)
 				assert.Equal(t, tt.request.Description, resp.MenuItem.Description)
-				assert.InDelta(t, tt.request.Price, resp.MenuItem.Price, 0.001)
+				assert.Equal(t, tt.request.Price.AmountMinor, resp.MenuItem.Price.AmountMinor)
+				assert.Equal(t, models.DefaultCurrency, resp.MenuItem.Price.CurrencyCode)
 				assert.NotEmpty(t, resp.MenuItem.CreatedAt)
 				assert.NotEmpty(t, resp.MenuItem.UpdatedAt)
 			}
@@ -102,15 +112,14 @@ func TestGetMenuItem(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
-
-	server := NewMenuServer()
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
 
 	// Create a test menu item
 	testItem := models.MenuItem{
 		Name:        "Latte",
 		Description: "Espresso with steamed milk",
-		Price:       4.00,
+		PriceMinor:  400,
+		Currency:    "USD",
 	}
 	err := db.Create(&testItem).Error
 	require.NoError(t, err)
@@ -158,19 +167,53 @@ func TestGetMenuItem(t *testing.T) {
 				assert.Equal(t, tt.itemID, resp.MenuItem.Id)
 				assert.Equal(t, testItem.Name, resp.MenuItem.Name)
 				assert.Equal(t, testItem.Description, resp.MenuItem.Description)
-				assert.InDelta(t, testItem.Price, resp.MenuItem.Price, 0.001)
+				assert.Equal(t, testItem.PriceMinor, resp.MenuItem.Price.AmountMinor)
 			}
 		})
 	}
 }
 
-func TestGetMenu(t *testing.T) {
+func TestBatchGetMenuItems(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+
+	testItems := []models.MenuItem{
+		{Name: "Coffee", PriceMinor: 250, Currency: "USD"},
+		{Name: "Tea", PriceMinor: 200, Currency: "USD"},
+		{Name: "Sandwich", PriceMinor: 550, Currency: "USD"},
+	}
+	require.NoError(t, db.Create(&testItems).Error)
+	coffee, sandwich := uint32(testItems[0].ID), uint32(testItems[2].ID)
+
+	// Items come back in request order with duplicates and missing IDs
+	// reported once
+	resp, err := server.BatchGetMenuItems(context.Background(), &menuv1.BatchGetMenuItemsRequest{
+		Ids: []uint32{sandwich, 9999, coffee, sandwich, 8888},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.MenuItems, 2)
+	assert.Equal(t, "Sandwich", resp.MenuItems[0].Name)
+	assert.Equal(t, int64(550), resp.MenuItems[0].Price.AmountMinor)
+	assert.Equal(t, "Coffee", resp.MenuItems[1].Name)
+	assert.Equal(t, []uint32{9999, 8888}, resp.MissingIds)
+
+	// Deleted items are missing
+	require.NoError(t, db.Delete(&testItems[0]).Error)
+	resp, err = server.BatchGetMenuItems(context.Background(), &menuv1.BatchGetMenuItemsRequest{
+		Ids: []uint32{coffee},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.MenuItems)
+	assert.Equal(t, []uint32{coffee}, resp.MissingIds)
+}
 
-	server := NewMenuServer()
+func TestGetMenu(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
 
 	// Test empty menu
 	t.Run("empty menu", func(t *testing.T) {
@@ -184,9 +227,9 @@ func TestGetMenu(t *testing.T) {
 
 	// Create multiple test menu items
 	testItems := []models.MenuItem{
-		{Name: "Coffee", Description: "Black coffee", Price: 2.50},
-		{Name: "Tea", Description: "Green tea", Price: 2.00},
-		{Name: "Sandwich", Description: "Ham and cheese", Price: 5.50},
+		{Name: "Coffee", Description: "Black coffee", PriceMinor: 250, Currency: "USD"},
+		{Name: "Tea", Description: "Green tea", PriceMinor: 200, Currency: "USD"},
+		{Name: "Sandwich", Description: "Ham and cheese", PriceMinor: 550, Currency: "USD"},
 	}
 
 	for _, item := range testItems {
@@ -207,7 +250,89 @@ func TestGetMenu(t *testing.T) {
 		for i, item := range resp.MenuItems {
 			assert.Equal(t, testItems[i].Name, item.Name)
 			assert.Equal(t, testItems[i].Description, item.Description)
-			assert.InDelta(t, testItems[i].Price, item.Price, 0.001)
+			assert.Equal(t, testItems[i].PriceMinor, item.Price.AmountMinor)
+		}
+	})
+}
+
+func TestGetMenu_PaginationAndFilters(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+	ctx := context.Background()
+
+	testItems := []models.MenuItem{
+		{Name: "Coffee", PriceMinor: 250, Currency: "USD"},
+		{Name: "Iced Coffee", PriceMinor: 350, Currency: "USD"},
+		{Name: "Tea", PriceMinor: 200, Currency: "USD"},
+		{Name: "Sandwich", PriceMinor: 550, Currency: "USD"},
+		{Name: "Muffin", PriceMinor: 250, Currency: "USD"},
+	}
+	for _, item := range testItems {
+		require.NoError(t, db.Create(&item).Error)
+	}
+
+	names := func(items []*menuv1.MenuItem) []string {
+		result := make([]string, len(items))
+		for i, item := range items {
+			result[i] = item.Name
+		}
+		return result
+	}
+
+	t.Run("pages by price with ties", func(t *testing.T) {
+		var got []string
+		req := &menuv1.GetMenuRequest{PageSize: 2, OrderBy: "price"}
+		for {
+			resp, err := server.GetMenu(ctx, req)
+			require.NoError(t, err)
+			got = append(got, names(resp.MenuItems)...)
+			if resp.NextPageToken == "" {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+		assert.Equal(t, []string{"Tea", "Coffee", "Muffin", "Iced Coffee", "Sandwich"}, got)
+	})
+
+	t.Run("price range", func(t *testing.T) {
+		resp, err := server.GetMenu(ctx, &menuv1.GetMenuRequest{
+			MinPriceMinor: proto.Int64(250),
+			MaxPriceMinor: proto.Int64(350),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Coffee", "Iced Coffee", "Muffin"}, names(resp.MenuItems))
+	})
+
+	t.Run("name substring is case-insensitive", func(t *testing.T) {
+		resp, err := server.GetMenu(ctx, &menuv1.GetMenuRequest{NameContains: "COFFEE", OrderBy: "price desc"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Iced Coffee", "Coffee"}, names(resp.MenuItems))
+
+		// LIKE wildcards in the filter are matched literally
+		resp, err = server.GetMenu(ctx, &menuv1.GetMenuRequest{NameContains: "%"})
+		require.NoError(t, err)
+		assert.Empty(t, resp.MenuItems)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		first, err := server.GetMenu(ctx, &menuv1.GetMenuRequest{PageSize: 1, OrderBy: "price"})
+		require.NoError(t, err)
+		require.NotEmpty(t, first.NextPageToken)
+
+		for _, req := range []*menuv1.GetMenuRequest{
+			{OrderBy: "description"},
+			{OrderBy: "price sideways"},
+			{PageToken: "garbage"},
+			// Tokens are bound to the sort order they were issued for
+			{OrderBy: "name", PageToken: first.NextPageToken},
+		} {
+			_, err := server.GetMenu(ctx, req)
+			require.Error(t, err)
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
 		}
 	})
 }
@@ -222,7 +347,8 @@ func TestModelToProto(t *testing.T) {
 		},
 		Name:        "Test Item",
 		Description: "Test Description",
-		Price:       3.99,
+		PriceMinor:  399,
+		Currency:    "EUR",
 	}
 
 	protoItem := modelToProto(item)
@@ -230,7 +356,8 @@ func TestModelToProto(t *testing.T) {
 	assert.Equal(t, uint32(1), protoItem.Id)
 	assert.Equal(t, "Test Item", protoItem.Name)
 	assert.Equal(t, "Test Description", protoItem.Description)
-	assert.InDelta(t, 3.99, protoItem.Price, 0.001)
+	assert.Equal(t, int64(399), protoItem.Price.AmountMinor)
+	assert.Equal(t, "EUR", protoItem.Price.CurrencyCode)
 	assert.Equal(t, now.Format(time.RFC3339), protoItem.CreatedAt)
 	assert.Equal(t, now.Format(time.RFC3339), protoItem.UpdatedAt)
 }
@@ -239,20 +366,18 @@ func TestPriceHandling(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
-
-	server := NewMenuServer()
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
 
-	// Test various price formats
+	// Prices are integer minor units, so they round-trip exactly
 	testCases := []struct {
 		name  string
-		price float64
+		price *commonv1.Money
 	}{
-		{"integer price", 5.0},
-		{"two decimal places", 5.99},
-		{"three decimal places", 5.999},
-		{"very small price", 0.01},
-		{"large price", 999.99},
+		{"zero price", &commonv1.Money{AmountMinor: 0}},
+		{"whole units", &commonv1.Money{AmountMinor: 500}},
+		{"very small price", &commonv1.Money{AmountMinor: 1}},
+		{"large price", &commonv1.Money{AmountMinor: 99999}},
+		{"explicit currency", &commonv1.Money{CurrencyCode: "EUR", AmountMinor: 599}},
 	}
 
 	for _, tc := range testCases {
@@ -265,7 +390,381 @@ func TestPriceHandling(t *testing.T) {
 			})
 
 			require.NoError(t, err)
-			assert.InDelta(t, tc.price, resp.MenuItem.Price, 0.001)
+			assert.Equal(t, tc.price.AmountMinor, resp.MenuItem.Price.AmountMinor)
+			if tc.price.CurrencyCode != "" {
+				assert.Equal(t, tc.price.CurrencyCode, resp.MenuItem.Price.CurrencyCode)
+			}
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+	ctx := context.Background()
+
+	snacks, err := server.CreateCategory(ctx, &menuv1.CreateCategoryRequest{Name: "Snacks"})
+	require.NoError(t, err)
+	drinks, err := server.CreateCategory(ctx, &menuv1.CreateCategoryRequest{Name: "Drinks", Description: "Hot and cold"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hot and cold", drinks.Category.Description)
+
+	t.Run("duplicate and empty names are rejected", func(t *testing.T) {
+		_, err := server.CreateCategory(ctx, &menuv1.CreateCategoryRequest{Name: "Drinks"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+		_, err = server.CreateCategory(ctx, &menuv1.CreateCategoryRequest{Name: "  "})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("categories are listed by name", func(t *testing.T) {
+		resp, err := server.GetCategories(ctx, &menuv1.GetCategoriesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Categories, 2)
+		assert.Equal(t, "Drinks", resp.Categories[0].Name)
+		assert.Equal(t, "Snacks", resp.Categories[1].Name)
+	})
+
+	t.Run("menu items are filed and filtered by category", func(t *testing.T) {
+		coffee, err := server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+			Name: "Coffee", Price: &commonv1.Money{AmountMinor: 250}, CategoryId: drinks.Category.Id,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, drinks.Category.Id, coffee.MenuItem.CategoryId)
+
+		_, err = server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+			Name: "Crisps", Price: &commonv1.Money{AmountMinor: 150}, CategoryId: snacks.Category.Id,
+		})
+		require.NoError(t, err)
+
+		resp, err := server.GetMenu(ctx, &menuv1.GetMenuRequest{CategoryId: proto.Uint32(drinks.Category.Id)})
+		require.NoError(t, err)
+		require.Len(t, resp.MenuItems, 1)
+		assert.Equal(t, "Coffee", resp.MenuItems[0].Name)
+
+		_, err = server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+			Name: "Mystery", Price: &commonv1.Money{AmountMinor: 100}, CategoryId: 999,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestMenuItemAvailabilityAndStock(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+	ctx := context.Background()
+
+	t.Run("new items are available with untracked stock by default", func(t *testing.T) {
+		resp, err := server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{Name: "Tea", Price: &commonv1.Money{AmountMinor: 200}})
+		require.NoError(t, err)
+		assert.True(t, resp.MenuItem.Available)
+		assert.Nil(t, resp.MenuItem.Stock)
+	})
+
+	muffin, err := server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+		Name: "Muffin", Price: &commonv1.Money{AmountMinor: 300}, Stock: proto.Int32(2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), muffin.MenuItem.GetStock())
+	id := muffin.MenuItem.Id
+
+	t.Run("toggle availability", func(t *testing.T) {
+		resp, err := server.SetMenuItemAvailability(ctx, &menuv1.SetMenuItemAvailabilityRequest{Id: id, Available: false})
+		require.NoError(t, err)
+		assert.False(t, resp.MenuItem.Available)
+
+		menu, err := server.GetMenu(ctx, &menuv1.GetMenuRequest{AvailableOnly: true})
+		require.NoError(t, err)
+		require.Len(t, menu.MenuItems, 1)
+		assert.Equal(t, "Tea", menu.MenuItems[0].Name)
+
+		resp, err = server.SetMenuItemAvailability(ctx, &menuv1.SetMenuItemAvailabilityRequest{Id: id, Available: true})
+		require.NoError(t, err)
+		assert.True(t, resp.MenuItem.Available)
+
+		_, err = server.SetMenuItemAvailability(ctx, &menuv1.SetMenuItemAvailabilityRequest{Id: 999, Available: true})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("set and clear stock", func(t *testing.T) {
+		_, err := server.SetMenuItemStock(ctx, &menuv1.SetMenuItemStockRequest{Id: id, Stock: proto.Int32(-1)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		resp, err := server.SetMenuItemStock(ctx, &menuv1.SetMenuItemStockRequest{Id: id})
+		require.NoError(t, err)
+		assert.Nil(t, resp.MenuItem.Stock)
+
+		resp, err = server.SetMenuItemStock(ctx, &menuv1.SetMenuItemStockRequest{Id: id, Stock: proto.Int32(2)})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), resp.MenuItem.GetStock())
+	})
+}
+
+func TestReserveAndReleaseStock(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+	ctx := context.Background()
+
+	create := func(req *menuv1.CreateMenuItemRequest) uint32 {
+		req.Price = &commonv1.Money{AmountMinor: 100}
+		resp, err := server.CreateMenuItem(ctx, req)
+		require.NoError(t, err)
+		return resp.MenuItem.Id
+	}
+	coffee := create(&menuv1.CreateMenuItemRequest{Name: "Coffee"})
+	muffin := create(&menuv1.CreateMenuItemRequest{Name: "Muffin", Stock: proto.Int32(3)})
+	pie := create(&menuv1.CreateMenuItemRequest{Name: "Pie", Available: proto.Bool(false)})
+
+	stockOf := func(id uint32) *int32 {
+		resp, err := server.GetMenuItem(ctx, &menuv1.GetMenuItemRequest{Id: id})
+		require.NoError(t, err)
+		return resp.MenuItem.Stock
+	}
+
+	t.Run("reserves tracked stock", func(t *testing.T) {
+		_, err := server.ReserveStock(ctx, &menuv1.ReserveStockRequest{Items: []*menuv1.StockReservation{
+			{MenuItemId: coffee, Quantity: 10},
+			{MenuItemId: muffin, Quantity: 2},
+		}})
+		require.NoError(t, err)
+		assert.Nil(t, stockOf(coffee))
+		assert.Equal(t, int32(1), *stockOf(muffin))
+	})
+
+	t.Run("all or nothing", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			items []*menuv1.StockReservation
+			code  codes.Code
+		}{
+			{"out of stock", []*menuv1.StockReservation{{MenuItemId: muffin, Quantity: 1}, {MenuItemId: muffin, Quantity: 1}}, codes.FailedPrecondition},
+			{"unavailable", []*menuv1.StockReservation{{MenuItemId: muffin, Quantity: 1}, {MenuItemId: pie, Quantity: 1}}, codes.FailedPrecondition},
+			{"not found", []*menuv1.StockReservation{{MenuItemId: muffin, Quantity: 1}, {MenuItemId: 999, Quantity: 1}}, codes.NotFound},
+			{"bad quantity", []*menuv1.StockReservation{{MenuItemId: muffin, Quantity: 1}, {MenuItemId: coffee, Quantity: 0}}, codes.InvalidArgument},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := server.ReserveStock(ctx, &menuv1.ReserveStockRequest{Items: tt.items})
+				assert.Equal(t, tt.code, status.Code(err))
+				assert.Equal(t, int32(1), *stockOf(muffin), "nothing is reserved")
+			})
+		}
+	})
+
+	t.Run("release returns tracked stock", func(t *testing.T) {
+		_, err := server.ReleaseStock(ctx, &menuv1.ReleaseStockRequest{Items: []*menuv1.StockReservation{
+			{MenuItemId: coffee, Quantity: 10},
+			{MenuItemId: muffin, Quantity: 2},
+		}})
+		require.NoError(t, err)
+		assert.Nil(t, stockOf(coffee))
+		assert.Equal(t, int32(3), *stockOf(muffin))
+	})
+}
+
+func TestUpdateMenuItem(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+	ctx := context.Background()
+
+	drinks, err := server.CreateCategory(ctx, &menuv1.CreateCategoryRequest{Name: "Drinks"})
+	require.NoError(t, err)
+
+	created, err := server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+		Name:        "Latte",
+		Description: "Milky coffee",
+		Price:       &commonv1.Money{CurrencyCode: "EUR", AmountMinor: 300},
+		CategoryId:  drinks.Category.Id,
+		Stock:       proto.Int32(10),
+	})
+	require.NoError(t, err)
+	id := created.MenuItem.Id
+
+	update := func(item *menuv1.MenuItem, paths ...string) (*menuv1.MenuItem, error) {
+		item.Id = id
+		req := &menuv1.UpdateMenuItemRequest{MenuItem: item}
+		if len(paths) > 0 {
+			req.UpdateMask = &fieldmaskpb.FieldMask{Paths: paths}
+		}
+		resp, err := server.UpdateMenuItem(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.MenuItem, nil
+	}
+
+	t.Run("rename only", func(t *testing.T) {
+		item, err := update(&menuv1.MenuItem{Name: "Caffe Latte", Description: "ignored"}, "name")
+		require.NoError(t, err)
+		assert.Equal(t, "Caffe Latte", item.Name)
+		assert.Equal(t, "Milky coffee", item.Description)
+		assert.Equal(t, int64(300), item.Price.AmountMinor)
+	})
+
+	t.Run("price keeps currency when none is given", func(t *testing.T) {
+		item, err := update(&menuv1.MenuItem{Price: &commonv1.Money{AmountMinor: 350}}, "price")
+		require.NoError(t, err)
+		assert.Equal(t, int64(350), item.Price.AmountMinor)
+		assert.Equal(t, "EUR", item.Price.CurrencyCode)
+	})
+
+	t.Run("clear category and stock", func(t *testing.T) {
+		item, err := update(&menuv1.MenuItem{}, "category_id", "stock")
+		require.NoError(t, err)
+		assert.Zero(t, item.CategoryId)
+		assert.Nil(t, item.Stock)
+	})
+
+	t.Run("empty mask replaces every field", func(t *testing.T) {
+		item, err := update(&menuv1.MenuItem{
+			Name:       "Flat White",
+			Price:      &commonv1.Money{CurrencyCode: "USD", AmountMinor: 325},
+			CategoryId: drinks.Category.Id,
+			Available:  false,
+			Stock:      proto.Int32(4),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Flat White", item.Name)
+		assert.Empty(t, item.Description)
+		assert.Equal(t, "USD", item.Price.CurrencyCode)
+		assert.Equal(t, drinks.Category.Id, item.CategoryId)
+		assert.False(t, item.Available)
+		assert.Equal(t, int32(4), item.GetStock())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := server.UpdateMenuItem(ctx, &menuv1.UpdateMenuItemRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = update(&menuv1.MenuItem{}, "id")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = update(&menuv1.MenuItem{CategoryId: 999}, "category_id")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = update(&menuv1.MenuItem{Stock: proto.Int32(-2)}, "stock")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = server.UpdateMenuItem(ctx, &menuv1.UpdateMenuItemRequest{
+			MenuItem:   &menuv1.MenuItem{Id: 999, Name: "Ghost"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
 		})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestDeleteMenuItem(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewMenuServer(repository.NewGormMenuRepository(db))
+	ctx := context.Background()
+
+	created, err := server.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{Name: "Scone", Price: &commonv1.Money{AmountMinor: 200}})
+	require.NoError(t, err)
+	id := created.MenuItem.Id
+
+	_, err = server.DeleteMenuItem(ctx, &menuv1.DeleteMenuItemRequest{Id: id})
+	require.NoError(t, err)
+
+	// Deleted items disappear from the menu and cannot be ordered
+	_, err = server.GetMenuItem(ctx, &menuv1.GetMenuItemRequest{Id: id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	menu, err := server.GetMenu(ctx, &menuv1.GetMenuRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, menu.MenuItems)
+
+	_, err = server.ReserveStock(ctx, &menuv1.ReserveStockRequest{Items: []*menuv1.StockReservation{{MenuItemId: id, Quantity: 1}}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.DeleteMenuItem(ctx, &menuv1.DeleteMenuItemRequest{Id: id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestPolicy(t *testing.T) {
+	interceptor := authz.UnaryServerInterceptor(Policy())
+	call := func(ctx context.Context, method string) codes.Code {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return status.Code(err)
+	}
+
+	owner := auth.NewContext(context.Background(), auth.Identity{UserID: 1, Role: auth.RoleCafeOwner})
+	student := auth.NewContext(context.Background(), auth.Identity{UserID: 2, Role: auth.RoleStudent})
+
+	// Only cafe owners change the menu
+	for _, method := range []string{
+		menuv1.MenuService_CreateMenuItem_FullMethodName,
+		menuv1.MenuService_UpdateMenuItem_FullMethodName,
+		menuv1.MenuService_DeleteMenuItem_FullMethodName,
+		menuv1.MenuService_SetMenuItemAvailability_FullMethodName,
+		menuv1.MenuService_SetMenuItemStock_FullMethodName,
+		menuv1.MenuService_CreateCategory_FullMethodName,
+	} {
+		assert.Equal(t, codes.OK, call(owner, method), method)
+		assert.Equal(t, codes.PermissionDenied, call(student, method), method)
+		assert.Equal(t, codes.Unauthenticated, call(context.Background(), method), method)
+	}
+
+	// Reads are public
+	for _, method := range []string{
+		menuv1.MenuService_GetMenu_FullMethodName,
+		menuv1.MenuService_GetMenuItem_FullMethodName,
+	} {
+		assert.Equal(t, codes.OK, call(student, method), method)
+		assert.Equal(t, codes.OK, call(context.Background(), method), method)
+	}
+
+	// Stock reservations come from order-service on behalf of signed-in
+	// students
+	for _, method := range []string{
+		menuv1.MenuService_ReserveStock_FullMethodName,
+		menuv1.MenuService_ReleaseStock_FullMethodName,
+	} {
+		assert.Equal(t, codes.OK, call(owner, method), method)
+		assert.Equal(t, codes.OK, call(student, method), method)
+		assert.Equal(t, codes.Unauthenticated, call(context.Background(), method), method)
+	}
+}
+
+func TestCallers(t *testing.T) {
+	interceptor := mtls.UnaryServerInterceptor(Callers())
+	call := func(service, method string) codes.Code {
+		cert := &x509.Certificate{DNSNames: []string{service}}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return status.Code(err)
+	}
+
+	// Only order-service holds stock for orders
+	for _, method := range []string{
+		menuv1.MenuService_ReserveStock_FullMethodName,
+		menuv1.MenuService_ReleaseStock_FullMethodName,
+	} {
+		assert.Equal(t, codes.OK, call("order-service", method), method)
+		assert.Equal(t, codes.PermissionDenied, call("api-gateway", method), method)
+	}
+
+	// Everything else is open to both callers
+	for _, method := range []string{
+		menuv1.MenuService_GetMenuItem_FullMethodName,
+		menuv1.MenuService_BatchGetMenuItems_FullMethodName,
+		menuv1.MenuService_CreateMenuItem_FullMethodName,
+	} {
+		assert.Equal(t, codes.OK, call("order-service", method), method)
+		assert.Equal(t, codes.OK, call("api-gateway", method), method)
 	}
 }
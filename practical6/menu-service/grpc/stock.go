@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"menu-service/models"
+	"menu-service/repository"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/grpcerr"
+)
+
+// SetMenuItemAvailability takes a menu item off the menu or puts it back
+func (s *MenuServer) SetMenuItemAvailability(ctx context.Context, req *menuv1.SetMenuItemAvailabilityRequest) (*menuv1.SetMenuItemAvailabilityResponse, error) {
+	menuItem, err := s.updateMenuItem(ctx, req.Id, func(item *models.MenuItem) string {
+		item.Available = req.Available
+		return "available"
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &menuv1.SetMenuItemAvailabilityResponse{
+		MenuItem: modelToProto(menuItem),
+	}, nil
+}
+
+// SetMenuItemStock sets a menu item's stock, or stops tracking it when the
+// request leaves stock unset
+func (s *MenuServer) SetMenuItemStock(ctx context.Context, req *menuv1.SetMenuItemStockRequest) (*menuv1.SetMenuItemStockResponse, error) {
+	if req.Stock != nil && req.GetStock() < 0 {
+		return nil, grpcerr.InvalidField("stock", "stock cannot be negative")
+	}
+
+	menuItem, err := s.updateMenuItem(ctx, req.Id, func(item *models.MenuItem) string {
+		item.Stock = req.Stock
+		return "stock"
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &menuv1.SetMenuItemStockResponse{
+		MenuItem: modelToProto(menuItem),
+	}, nil
+}
+
+// updateMenuItem changes one column of a menu item with set, which returns
+// the column's name, and returns the updated item
+func (s *MenuServer) updateMenuItem(ctx context.Context, id uint32, set func(*models.MenuItem) string) (*models.MenuItem, error) {
+	menuItem, err := s.Menu.GetItem(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "menu item not found")
+		}
+		return nil, dberr.Error(err, "failed to get menu item")
+	}
+
+	if err := s.Menu.UpdateItem(ctx, menuItem, set(menuItem)); err != nil {
+		return nil, dberr.Error(err, "failed to update menu item")
+	}
+	return menuItem, nil
+}
+
+// ReserveStock decrements stock for every requested item in one transaction.
+// It fails with FailedPrecondition, reserving nothing, if any item is
+// unavailable or does not have enough stock left.
+func (s *MenuServer) ReserveStock(ctx context.Context, req *menuv1.ReserveStockRequest) (*menuv1.ReserveStockResponse, error) {
+	changes, err := stockChanges(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Menu.ReserveStock(ctx, changes); err != nil {
+		var reservationErr *repository.ReservationError
+		if errors.As(err, &reservationErr) {
+			return nil, reservationError(reservationErr)
+		}
+		return nil, dberr.Error(err, "failed to reserve stock")
+	}
+
+	return &menuv1.ReserveStockResponse{}, nil
+}
+
+// stockChanges converts the items of a stock request, which must all have a
+// positive quantity
+func stockChanges(items []*menuv1.StockReservation) ([]repository.StockChange, error) {
+	changes := make([]repository.StockChange, len(items))
+	for i, item := range items {
+		if item.Quantity <= 0 {
+			return nil, grpcerr.InvalidField(fmt.Sprintf("items[%d].quantity", i),
+				"quantity for menu item %d must be positive", item.MenuItemId)
+		}
+		changes[i] = repository.StockChange{MenuItemID: uint(item.MenuItemId), Quantity: item.Quantity}
+	}
+	return changes, nil
+}
+
+// reservationError explains why a reservation could not be made. The
+// error's ErrorInfo names the item in its menu_item_id metadata.
+func reservationError(err *repository.ReservationError) error {
+	id := err.Change.MenuItemID
+	info := func(reason string) *errdetails.ErrorInfo {
+		return grpcerr.Info(reason, map[string]string{"menu_item_id": strconv.FormatUint(uint64(id), 10)})
+	}
+
+	switch {
+	case err.Item == nil:
+		return grpcerr.New(codes.NotFound, fmt.Sprintf("menu item %d not found", id),
+			info("MENU_ITEM_NOT_FOUND"))
+	case !err.Item.Available:
+		return grpcerr.New(codes.FailedPrecondition, fmt.Sprintf("menu item %d is not available", id),
+			info("MENU_ITEM_UNAVAILABLE"))
+	default:
+		return grpcerr.New(codes.FailedPrecondition,
+			fmt.Sprintf("menu item %d is out of stock: %d requested, %d left", id, err.Change.Quantity, *err.Item.Stock),
+			info("INSUFFICIENT_STOCK"))
+	}
+}
+
+// ReleaseStock returns reserved units to the stock of tracked items
+func (s *MenuServer) ReleaseStock(ctx context.Context, req *menuv1.ReleaseStockRequest) (*menuv1.ReleaseStockResponse, error) {
+	changes, err := stockChanges(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Menu.ReleaseStock(ctx, changes); err != nil {
+		return nil, dberr.Error(err, "failed to release stock")
+	}
+
+	return &menuv1.ReleaseStockResponse{}, nil
+}
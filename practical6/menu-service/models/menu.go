@@ -2,16 +2,32 @@ package models
 
 import "gorm.io/gorm"
 
-type Menu struct {
+// DefaultCurrency is used for menu items created without a currency
+const DefaultCurrency = "USD"
+
+// Category groups menu items, e.g. drinks, snacks or meals
+type Category struct {
 	gorm.Model
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	MenuItems   []MenuItem `json:"menu_items" gorm:"references:MenuID"`
+	Name        string     `json:"name" gorm:"size:100;uniqueIndex"`
+	Description string     `json:"description"`
+	MenuItems   []MenuItem `json:"menu_items" gorm:"foreignKey:CategoryID"`
 }
 
 type MenuItem struct {
 	gorm.Model
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	PriceMinor  int64  `json:"price_minor"` // Price in currency minor units, e.g. cents
+	Currency    string `json:"currency" gorm:"size:3;not null;default:USD"`
+	CategoryID  *uint  `json:"category_id" gorm:"index"`
+	// Available is false while an item is temporarily off the menu. Its column
+	// default is false so GORM writes explicit false values; items that existed
+	// before the column are marked available by the migration.
+	Available bool   `json:"available" gorm:"not null;default:false"`
+	Stock     *int32 `json:"stock"` // Units left; nil when stock is not tracked
+}
+
+// InStock reports whether quantity units can be ordered
+func (m *MenuItem) InStock(quantity int32) bool {
+	return m.Stock == nil || *m.Stock >= quantity
 }
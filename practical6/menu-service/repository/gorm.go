@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"menu-service/models"
+	"student-cafe-shared/pagination"
+)
+
+// GormMenuRepository stores the menu in a SQL database through GORM
+type GormMenuRepository struct {
+	db *gorm.DB
+}
+
+// NewGormMenuRepository returns a repository backed by db, which must
+// already be migrated
+func NewGormMenuRepository(db *gorm.DB) *GormMenuRepository {
+	return &GormMenuRepository{db: db}
+}
+
+// GetItem returns the menu item with the given ID
+func (r *GormMenuRepository) GetItem(ctx context.Context, id uint) (*models.MenuItem, error) {
+	var item models.MenuItem
+	if err := r.db.WithContext(ctx).First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetItems returns the menu items with the given IDs ordered by ID
+func (r *GormMenuRepository) GetItems(ctx context.Context, ids []uint) ([]models.MenuItem, error) {
+	items := []models.MenuItem{}
+	if len(ids) == 0 {
+		return items, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// likeEscaper escapes LIKE wildcards so name filters match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ListItems returns a page of menu items matching filter
+func (r *GormMenuRepository) ListItems(ctx context.Context, filter ItemFilter, page pagination.Request) ([]models.MenuItem, string, error) {
+	query := r.db.WithContext(ctx).Model(&models.MenuItem{})
+	if filter.MinPriceMinor != nil {
+		query = query.Where("price_minor >= ?", *filter.MinPriceMinor)
+	}
+	if filter.MaxPriceMinor != nil {
+		query = query.Where("price_minor <= ?", *filter.MaxPriceMinor)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+	if filter.AvailableOnly {
+		query = query.Where("available = ? AND (stock IS NULL OR stock > 0)", true)
+	}
+	if filter.NameContains != "" {
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(strings.ToLower(filter.NameContains))+"%")
+	}
+	return pagination.Find(query, page, sortKey(page.Sort))
+}
+
+// CreateItem saves a new menu item
+func (r *GormMenuRepository) CreateItem(ctx context.Context, item *models.MenuItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// UpdateItem saves the named columns of an existing menu item. Nil pointer
+// fields clear their column.
+func (r *GormMenuRepository) UpdateItem(ctx context.Context, item *models.MenuItem, columns ...string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).Model(item).Select(columns).Updates(item)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteItem soft-deletes the menu item with the given ID
+func (r *GormMenuRepository) DeleteItem(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.MenuItem{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReserveStock takes stock for every change in one transaction
+func (r *GormMenuRepository) ReserveStock(ctx context.Context, changes []StockChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			// Check and decrement in one statement so concurrent orders cannot
+			// oversell; untracked (NULL) stock stays NULL
+			result := tx.Model(&models.MenuItem{}).
+				Where("id = ? AND available = ? AND (stock IS NULL OR stock >= ?)", change.MenuItemID, true, change.Quantity).
+				Update("stock", gorm.Expr("stock - ?", change.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				continue
+			}
+
+			// Load the item to explain why it could not be reserved
+			var item models.MenuItem
+			err := tx.First(&item, change.MenuItemID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &ReservationError{Change: change}
+			}
+			if err != nil {
+				return err
+			}
+			return &ReservationError{Change: change, Item: &item}
+		}
+		return nil
+	})
+}
+
+// ReleaseStock returns stock to tracked items in one transaction
+func (r *GormMenuRepository) ReleaseStock(ctx context.Context, changes []StockChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			if err := tx.Model(&models.MenuItem{}).
+				Where("id = ? AND stock IS NOT NULL", change.MenuItemID).
+				Update("stock", gorm.Expr("stock + ?", change.Quantity)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetCategory returns the category with the given ID
+func (r *GormMenuRepository) GetCategory(ctx context.Context, id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateCategory saves a new category
+func (r *GormMenuRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+// ListCategories returns every category ordered by name
+func (r *GormMenuRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.WithContext(ctx).Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
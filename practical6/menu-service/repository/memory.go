@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"menu-service/models"
+	"student-cafe-shared/pagination"
+)
+
+// MemoryMenuRepository stores the menu in memory. It is safe for concurrent
+// use and the zero value is not usable; create one with
+// NewMemoryMenuRepository.
+type MemoryMenuRepository struct {
+	mu             sync.RWMutex
+	items          map[uint]models.MenuItem
+	categories     map[uint]models.Category
+	nextItemID     uint
+	nextCategoryID uint
+}
+
+// NewMemoryMenuRepository returns an empty in-memory repository
+func NewMemoryMenuRepository() *MemoryMenuRepository {
+	return &MemoryMenuRepository{
+		items:      make(map[uint]models.MenuItem),
+		categories: make(map[uint]models.Category),
+	}
+}
+
+// cloneItem copies a menu item so callers never share its pointer fields
+// with the store
+func cloneItem(item models.MenuItem) models.MenuItem {
+	if item.CategoryID != nil {
+		id := *item.CategoryID
+		item.CategoryID = &id
+	}
+	if item.Stock != nil {
+		stock := *item.Stock
+		item.Stock = &stock
+	}
+	return item
+}
+
+// item returns a stored menu item that has not been deleted
+func (r *MemoryMenuRepository) item(id uint) (models.MenuItem, bool) {
+	item, ok := r.items[id]
+	if !ok || item.DeletedAt.Valid {
+		return models.MenuItem{}, false
+	}
+	return item, true
+}
+
+// GetItem returns the menu item with the given ID
+func (r *MemoryMenuRepository) GetItem(ctx context.Context, id uint) (*models.MenuItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.item(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	item = cloneItem(item)
+	return &item, nil
+}
+
+// GetItems returns the menu items with the given IDs ordered by ID
+func (r *MemoryMenuRepository) GetItems(ctx context.Context, ids []uint) ([]models.MenuItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := []models.MenuItem{}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if item, ok := r.item(id); ok {
+			items = append(items, cloneItem(item))
+		}
+	}
+	slices.SortFunc(items, func(a, b models.MenuItem) int { return cmp.Compare(a.ID, b.ID) })
+	return items, nil
+}
+
+// ListItems returns a page of menu items matching filter
+func (r *MemoryMenuRepository) ListItems(ctx context.Context, filter ItemFilter, page pagination.Request) ([]models.MenuItem, string, error) {
+	nameContains := strings.ToLower(filter.NameContains)
+
+	r.mu.RLock()
+	items := make([]models.MenuItem, 0, len(r.items))
+	for _, item := range r.items {
+		switch {
+		case item.DeletedAt.Valid,
+			filter.MinPriceMinor != nil && item.PriceMinor < *filter.MinPriceMinor,
+			filter.MaxPriceMinor != nil && item.PriceMinor > *filter.MaxPriceMinor,
+			filter.CategoryID != nil && (item.CategoryID == nil || *item.CategoryID != *filter.CategoryID),
+			filter.AvailableOnly && (!item.Available || !item.InStock(1)),
+			!strings.Contains(strings.ToLower(item.Name), nameContains):
+			continue
+		}
+		items = append(items, cloneItem(item))
+	}
+	r.mu.RUnlock()
+
+	return pagination.Slice(items, page, sortKey(page.Sort))
+}
+
+// CreateItem saves a new menu item
+func (r *MemoryMenuRepository) CreateItem(ctx context.Context, item *models.MenuItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextItemID++
+	item.ID = r.nextItemID
+	setTimestamps(&item.Model)
+	r.items[item.ID] = cloneItem(*item)
+	return nil
+}
+
+// UpdateItem saves the named columns of an existing menu item
+func (r *MemoryMenuRepository) UpdateItem(ctx context.Context, item *models.MenuItem, columns ...string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.item(item.ID)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	update := cloneItem(*item)
+	for _, column := range columns {
+		switch column {
+		case "name":
+			stored.Name = update.Name
+		case "description":
+			stored.Description = update.Description
+		case "price_minor":
+			stored.PriceMinor = update.PriceMinor
+		case "currency":
+			stored.Currency = update.Currency
+		case "category_id":
+			stored.CategoryID = update.CategoryID
+		case "available":
+			stored.Available = update.Available
+		case "stock":
+			stored.Stock = update.Stock
+		default:
+			return fmt.Errorf("cannot update column %q", column)
+		}
+	}
+
+	stored.UpdatedAt = time.Now()
+	item.UpdatedAt = stored.UpdatedAt
+	r.items[item.ID] = stored
+	return nil
+}
+
+// DeleteItem soft-deletes the menu item with the given ID
+func (r *MemoryMenuRepository) DeleteItem(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.item(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	item.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.items[id] = item
+	return nil
+}
+
+// ReserveStock takes stock for every change or for none of them
+func (r *MemoryMenuRepository) ReserveStock(ctx context.Context, changes []StockChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Apply the changes to copies and only store them once all succeed
+	reserved := make(map[uint]models.MenuItem, len(changes))
+	for _, change := range changes {
+		item, ok := reserved[change.MenuItemID]
+		if !ok {
+			if item, ok = r.item(change.MenuItemID); !ok {
+				return &ReservationError{Change: change}
+			}
+			item = cloneItem(item)
+		}
+
+		if !item.Available || !item.InStock(change.Quantity) {
+			return &ReservationError{Change: change, Item: &item}
+		}
+		if item.Stock != nil {
+			*item.Stock -= change.Quantity
+		}
+		reserved[item.ID] = item
+	}
+
+	for id, item := range reserved {
+		r.items[id] = item
+	}
+	return nil
+}
+
+// ReleaseStock returns stock to tracked items
+func (r *MemoryMenuRepository) ReleaseStock(ctx context.Context, changes []StockChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, change := range changes {
+		item, ok := r.item(change.MenuItemID)
+		if !ok || item.Stock == nil {
+			continue
+		}
+		item = cloneItem(item)
+		*item.Stock += change.Quantity
+		r.items[item.ID] = item
+	}
+	return nil
+}
+
+// GetCategory returns the category with the given ID
+func (r *MemoryMenuRepository) GetCategory(ctx context.Context, id uint) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	category, ok := r.categories[id]
+	if !ok || category.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &category, nil
+}
+
+// CreateCategory saves a new category
+func (r *MemoryMenuRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Category names carry a unique index
+	for _, existing := range r.categories {
+		if existing.Name == category.Name {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.nextCategoryID++
+	category.ID = r.nextCategoryID
+	setTimestamps(&category.Model)
+	r.categories[category.ID] = *category
+	return nil
+}
+
+// ListCategories returns every category ordered by name
+func (r *MemoryMenuRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	r.mu.RLock()
+	categories := make([]models.Category, 0, len(r.categories))
+	for _, category := range r.categories {
+		if !category.DeletedAt.Valid {
+			categories = append(categories, category)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(categories, func(a, b models.Category) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return categories, nil
+}
+
+// setTimestamps fills in unset creation and update times, as GORM does
+func setTimestamps(model *gorm.Model) {
+	now := time.Now()
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = now
+	}
+}
@@ -0,0 +1,109 @@
+// Package repository stores the menu service's menu items and categories.
+//
+// MenuServer depends on the MenuRepository interface rather than on a
+// database handle, so each server owns its storage: GormMenuRepository
+// backs the service in production and MemoryMenuRepository keeps the menu in
+// memory for tests. Both report missing rows with gorm.ErrRecordNotFound and
+// duplicate category names with a unique violation, so callers can classify
+// errors with dberr.
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"menu-service/models"
+	"student-cafe-shared/pagination"
+)
+
+// MenuRepository stores menu items and their categories
+type MenuRepository interface {
+	// GetItem returns the menu item with the given ID
+	GetItem(ctx context.Context, id uint) (*models.MenuItem, error)
+	// GetItems returns the menu items with the given IDs ordered by ID,
+	// skipping IDs that do not exist
+	GetItems(ctx context.Context, ids []uint) ([]models.MenuItem, error)
+	// ListItems returns a page of menu items matching filter and the next
+	// page token
+	ListItems(ctx context.Context, filter ItemFilter, page pagination.Request) ([]models.MenuItem, string, error)
+	// CreateItem saves a new menu item, setting its ID and timestamps
+	CreateItem(ctx context.Context, item *models.MenuItem) error
+	// UpdateItem saves the named columns of an existing menu item
+	UpdateItem(ctx context.Context, item *models.MenuItem, columns ...string) error
+	// DeleteItem soft-deletes the menu item with the given ID
+	DeleteItem(ctx context.Context, id uint) error
+
+	// ReserveStock takes stock for every change or for none of them. It
+	// fails with a *ReservationError naming the first change that cannot be
+	// met. Items whose stock is not tracked only need to be available.
+	ReserveStock(ctx context.Context, changes []StockChange) error
+	// ReleaseStock returns stock to tracked items
+	ReleaseStock(ctx context.Context, changes []StockChange) error
+
+	// GetCategory returns the category with the given ID
+	GetCategory(ctx context.Context, id uint) (*models.Category, error)
+	// CreateCategory saves a new category, setting its ID and timestamps
+	CreateCategory(ctx context.Context, category *models.Category) error
+	// ListCategories returns every category ordered by name
+	ListCategories(ctx context.Context) ([]models.Category, error)
+}
+
+// ItemFilter narrows the menu items returned by ListItems
+type ItemFilter struct {
+	MinPriceMinor *int64
+	MaxPriceMinor *int64
+	CategoryID    *uint
+	// AvailableOnly skips items that are off the menu or out of stock
+	AvailableOnly bool
+	// NameContains matches names case-insensitively
+	NameContains string
+}
+
+// StockChange is a quantity of one menu item to reserve or release
+type StockChange struct {
+	MenuItemID uint
+	Quantity   int32
+}
+
+// ReservationError reports a stock change that ReserveStock could not make
+type ReservationError struct {
+	Change StockChange
+	// Item is the menu item as it was when the reservation failed, or nil if
+	// it does not exist
+	Item *models.MenuItem
+}
+
+func (e *ReservationError) Error() string {
+	switch {
+	case e.Item == nil:
+		return fmt.Sprintf("menu item %d not found", e.Change.MenuItemID)
+	case !e.Item.Available:
+		return fmt.Sprintf("menu item %d is not available", e.Change.MenuItemID)
+	default:
+		return fmt.Sprintf("menu item %d is out of stock", e.Change.MenuItemID)
+	}
+}
+
+// SortFields maps the order_by fields menu items can be listed by to columns
+var SortFields = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"price":      "price_minor",
+	"created_at": "created_at",
+}
+
+// sortKey returns the pagination key for a sort order
+func sortKey(sort pagination.Sort) pagination.Key[models.MenuItem] {
+	return func(item *models.MenuItem) (any, uint) {
+		switch sort.Field {
+		case "name":
+			return item.Name, item.ID
+		case "price":
+			return item.PriceMinor, item.ID
+		case "created_at":
+			return item.CreatedAt, item.ID
+		default:
+			return item.ID, item.ID
+		}
+	}
+}
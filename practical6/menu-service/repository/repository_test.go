@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"menu-service/models"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/pagination"
+)
+
+// implementations returns a fresh instance of every MenuRepository
+func implementations(t *testing.T) map[string]MenuRepository {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Category{}, &models.MenuItem{}))
+
+	return map[string]MenuRepository{
+		"gorm":   NewGormMenuRepository(db),
+		"memory": NewMemoryMenuRepository(),
+	}
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+// names returns the names of menu items in order
+func names(items []models.MenuItem) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.Name
+	}
+	return result
+}
+
+func TestMenuRepository(t *testing.T) {
+	ctx := context.Background()
+	byID := pagination.Request{Sort: pagination.Sort{Field: "id", Column: "id"}}
+
+	for name, repo := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			drinks := &models.Category{Name: "Drinks"}
+			require.NoError(t, repo.CreateCategory(ctx, drinks))
+			require.NoError(t, repo.CreateCategory(ctx, &models.Category{Name: "Bakery"}))
+
+			coffee := &models.MenuItem{Name: "Coffee", PriceMinor: 250, Currency: "USD", CategoryID: &drinks.ID, Available: true}
+			muffin := &models.MenuItem{Name: "Blueberry Muffin", PriceMinor: 300, Currency: "USD", Available: true, Stock: int32Ptr(2)}
+			pie := &models.MenuItem{Name: "Seasonal Pie", PriceMinor: 450, Currency: "USD", Available: false, Stock: int32Ptr(5)}
+			for _, item := range []*models.MenuItem{coffee, muffin, pie} {
+				require.NoError(t, repo.CreateItem(ctx, item))
+				assert.NotZero(t, item.ID)
+			}
+
+			t.Run("categories", func(t *testing.T) {
+				err := repo.CreateCategory(ctx, &models.Category{Name: "Drinks"})
+				assert.True(t, dberr.IsUniqueViolation(err), "got %v", err)
+
+				categories, err := repo.ListCategories(ctx)
+				require.NoError(t, err)
+				require.Len(t, categories, 2)
+				assert.Equal(t, "Bakery", categories[0].Name)
+
+				got, err := repo.GetCategory(ctx, drinks.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "Drinks", got.Name)
+				_, err = repo.GetCategory(ctx, 9999)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("list filters", func(t *testing.T) {
+				minPrice, maxPrice := int64(260), int64(400)
+				tests := []struct {
+					name   string
+					filter ItemFilter
+					want   []string
+				}{
+					{"no filter", ItemFilter{}, []string{"Coffee", "Blueberry Muffin", "Seasonal Pie"}},
+					{"price range", ItemFilter{MinPriceMinor: &minPrice, MaxPriceMinor: &maxPrice}, []string{"Blueberry Muffin"}},
+					{"category", ItemFilter{CategoryID: &drinks.ID}, []string{"Coffee"}},
+					{"available only", ItemFilter{AvailableOnly: true}, []string{"Coffee", "Blueberry Muffin"}},
+					{"name contains", ItemFilter{NameContains: "MUFF"}, []string{"Blueberry Muffin"}},
+					{"name wildcards match literally", ItemFilter{NameContains: "%"}, []string{}},
+				}
+				for _, tt := range tests {
+					items, _, err := repo.ListItems(ctx, tt.filter, byID)
+					require.NoError(t, err, tt.name)
+					assert.Equal(t, tt.want, names(items), tt.name)
+				}
+			})
+
+			t.Run("get items", func(t *testing.T) {
+				items, err := repo.GetItems(ctx, []uint{pie.ID, 9999, coffee.ID, pie.ID})
+				require.NoError(t, err)
+				assert.Equal(t, []string{"Coffee", "Seasonal Pie"}, names(items))
+
+				items, err = repo.GetItems(ctx, nil)
+				require.NoError(t, err)
+				assert.Empty(t, items)
+			})
+
+			t.Run("update only the named columns", func(t *testing.T) {
+				item, err := repo.GetItem(ctx, muffin.ID)
+				require.NoError(t, err)
+				item.Name = "Muffin"
+				item.Stock = nil
+				item.PriceMinor = 999
+				require.NoError(t, repo.UpdateItem(ctx, item, "name", "stock"))
+
+				got, err := repo.GetItem(ctx, muffin.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "Muffin", got.Name)
+				assert.Nil(t, got.Stock, "a nil stock stops tracking it")
+				assert.Equal(t, int64(300), got.PriceMinor)
+
+				item.Stock = int32Ptr(2)
+				require.NoError(t, repo.UpdateItem(ctx, item, "stock"))
+			})
+
+			t.Run("reserve and release stock", func(t *testing.T) {
+				require.NoError(t, repo.ReserveStock(ctx, []StockChange{
+					{MenuItemID: coffee.ID, Quantity: 10},
+					{MenuItemID: muffin.ID, Quantity: 1},
+				}))
+				got, err := repo.GetItem(ctx, muffin.ID)
+				require.NoError(t, err)
+				assert.Equal(t, int32(1), *got.Stock)
+
+				// Nothing is reserved when any change fails
+				err = repo.ReserveStock(ctx, []StockChange{
+					{MenuItemID: muffin.ID, Quantity: 1},
+					{MenuItemID: muffin.ID, Quantity: 1},
+				})
+				var reservationErr *ReservationError
+				require.ErrorAs(t, err, &reservationErr)
+				assert.Equal(t, int32(0), *reservationErr.Item.Stock)
+				got, err = repo.GetItem(ctx, muffin.ID)
+				require.NoError(t, err)
+				assert.Equal(t, int32(1), *got.Stock)
+
+				err = repo.ReserveStock(ctx, []StockChange{{MenuItemID: pie.ID, Quantity: 1}})
+				require.ErrorAs(t, err, &reservationErr)
+				assert.False(t, reservationErr.Item.Available)
+
+				err = repo.ReserveStock(ctx, []StockChange{{MenuItemID: 9999, Quantity: 1}})
+				require.ErrorAs(t, err, &reservationErr)
+				assert.Nil(t, reservationErr.Item)
+
+				require.NoError(t, repo.ReleaseStock(ctx, []StockChange{
+					{MenuItemID: coffee.ID, Quantity: 10},
+					{MenuItemID: muffin.ID, Quantity: 1},
+				}))
+				got, err = repo.GetItem(ctx, muffin.ID)
+				require.NoError(t, err)
+				assert.Equal(t, int32(2), *got.Stock)
+				got, err = repo.GetItem(ctx, coffee.ID)
+				require.NoError(t, err)
+				assert.Nil(t, got.Stock, "untracked stock stays untracked")
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				require.NoError(t, repo.DeleteItem(ctx, pie.ID))
+				_, err := repo.GetItem(ctx, pie.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, repo.DeleteItem(ctx, pie.ID), gorm.ErrRecordNotFound)
+				items, err := repo.GetItems(ctx, []uint{pie.ID})
+				require.NoError(t, err)
+				assert.Empty(t, items)
+
+				items, _, err = repo.ListItems(ctx, ItemFilter{}, byID)
+				require.NoError(t, err)
+				assert.Len(t, items, 2)
+			})
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"order-service/models"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"student-cafe-shared/migrate"
+)
+
+// legacyOrder and legacyOrderItem are the schemas from before prices were
+// stored in minor units and orders carried totals
+type legacyOrder struct {
+	gorm.Model
+	UserID     uint
+	Status     string
+	OrderItems []legacyOrderItem `gorm:"foreignKey:OrderID"`
+}
+
+func (legacyOrder) TableName() string { return "orders" }
+
+type legacyOrderItem struct {
+	gorm.Model
+	OrderID    uint
+	MenuItemID uint
+	Quantity   int
+	Price      float64
+}
+
+func (legacyOrderItem) TableName() string { return "order_items" }
+
+func TestMigrations(t *testing.T) {
+	migrations, err := migrate.Load(Migrations())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, "0001_baseline", migrations[0].String())
+}
+
+// openTestDB connects to the Postgres database named by POSTGRES_TEST_DSN,
+// which must not be shared with anything else: its order tables are dropped
+func openTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	drop := func() {
+		require.NoError(t, db.Migrator().DropTable(
+			"idempotency_keys", "order_status_transitions", "order_items", "orders", "schema_migrations"))
+	}
+	drop()
+	t.Cleanup(drop)
+	return db
+}
+
+func TestBaseline_UpgradesLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// Recreate the schema AutoMigrate built for the first release
+	require.NoError(t, db.AutoMigrate(&legacyOrder{}, &legacyOrderItem{}))
+	legacy := legacyOrder{
+		UserID: 1,
+		Status: "pending",
+		OrderItems: []legacyOrderItem{
+			{MenuItemID: 1, Quantity: 2, Price: 2.50},
+			{MenuItemID: 2, Quantity: 1, Price: 3.99},
+		},
+	}
+	require.NoError(t, db.Create(&legacy).Error)
+
+	migrator, err := migrate.New(db, Migrations())
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	assert.False(t, db.Migrator().HasColumn(&models.OrderItem{}, "price"))
+
+	var order models.Order
+	require.NoError(t, db.Preload("OrderItems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&order, legacy.ID).Error)
+	require.Len(t, order.OrderItems, 2)
+	assert.Equal(t, int64(250), order.OrderItems[0].PriceMinor)
+	assert.Equal(t, int64(399), order.OrderItems[1].PriceMinor)
+	assert.Equal(t, models.DefaultCurrency, order.OrderItems[0].Currency)
+
+	assert.Equal(t, models.DefaultCurrency, order.Currency)
+	assert.Equal(t, int64(899), order.SubtotalMinor)
+	assert.Equal(t, int64(0), order.TaxMinor)
+	assert.Equal(t, int64(899), order.TotalMinor)
+}
+
+func TestBaseline_UpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrator, err := migrate.New(db, Migrations())
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// The schema accepts everything the models write
+	order := models.Order{
+		UserID:     1,
+		Status:     models.StatusPending,
+		Currency:   models.DefaultCurrency,
+		OrderItems: []models.OrderItem{{MenuItemID: 1, Quantity: 1, PriceMinor: 250, Currency: models.DefaultCurrency}},
+		StatusHistory: []models.OrderStatusTransition{
+			{ToStatus: models.StatusPending},
+		},
+	}
+	require.NoError(t, db.Create(&order).Error)
+	key := models.IdempotencyKey{UserID: 1, Key: "retry-1", OrderID: order.ID, CreatedAt: time.Now()}
+	require.NoError(t, db.Create(&key).Error)
+	assert.Error(t, db.Create(&key).Error)
+
+	_, err = migrator.Down(ctx, int(migrator.Latest()))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable(&models.Order{}))
+	assert.False(t, db.Migrator().HasTable(&models.IdempotencyKey{}))
+}
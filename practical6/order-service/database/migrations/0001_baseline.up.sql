@@ -0,0 +1,80 @@
+-- Baseline schema. Databases created by the AutoMigrate calls this service
+-- used to make at startup already have some of it, so every statement is
+-- written to adopt an existing table rather than fail on it.
+
+CREATE TABLE IF NOT EXISTS orders (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    user_id BIGINT,
+    status TEXT
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    order_id BIGINT CONSTRAINT fk_orders_order_items REFERENCES orders (id),
+    menu_item_id BIGINT,
+    quantity BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS order_status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    order_id BIGINT CONSTRAINT fk_orders_status_history REFERENCES orders (id),
+    from_status TEXT,
+    to_status TEXT,
+    reason TEXT
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id BIGINT,
+    idempotency_key VARCHAR(255),
+    request_hash VARCHAR(64),
+    order_id BIGINT,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+-- Columns added after the first release
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS subtotal_minor BIGINT,
+    ADD COLUMN IF NOT EXISTS tax_minor BIGINT,
+    ADD COLUMN IF NOT EXISTS total_minor BIGINT;
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS price_minor BIGINT,
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+-- The first release stored item prices as floats and orders had no totals.
+-- Convert prices to minor units and backfill totals from the items; legacy
+-- orders were never taxed.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND table_name = 'order_items' AND column_name = 'price'
+    ) THEN
+        UPDATE order_items SET price_minor = ROUND(price * 100), currency = 'USD';
+        UPDATE orders SET currency = 'USD', tax_minor = 0, subtotal_minor = (
+            SELECT COALESCE(SUM(order_items.price_minor * order_items.quantity), 0)
+            FROM order_items WHERE order_items.order_id = orders.id
+        );
+        UPDATE orders SET total_minor = subtotal_minor + tax_minor;
+        ALTER TABLE order_items DROP COLUMN price;
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON orders (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_order_items_deleted_at ON order_items (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_order_status_transitions_deleted_at ON order_status_transitions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_order_status_transitions_order_id ON order_status_transitions (order_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_order_id ON idempotency_keys (order_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"order-service/repository"
+)
+
+// menuLatency simulates the network round trip of each call to
+// menu-service
+const menuLatency = time.Millisecond
+
+// benchMenuServer is a fake menu service whose every item exists, is
+// available and costs $2.50
+type benchMenuServer struct {
+	menuv1.UnimplementedMenuServiceServer
+	calls atomic.Int64
+}
+
+func (s *benchMenuServer) roundTrip() {
+	s.calls.Add(1)
+	time.Sleep(menuLatency)
+}
+
+func benchMenuItem(id uint32) *menuv1.MenuItem {
+	return &menuv1.MenuItem{Id: id, Name: fmt.Sprintf("Item %d", id), Available: true, Price: usd(250)}
+}
+
+func (s *benchMenuServer) GetMenuItem(ctx context.Context, req *menuv1.GetMenuItemRequest) (*menuv1.GetMenuItemResponse, error) {
+	s.roundTrip()
+	return &menuv1.GetMenuItemResponse{MenuItem: benchMenuItem(req.Id)}, nil
+}
+
+func (s *benchMenuServer) BatchGetMenuItems(ctx context.Context, req *menuv1.BatchGetMenuItemsRequest) (*menuv1.BatchGetMenuItemsResponse, error) {
+	s.roundTrip()
+	resp := &menuv1.BatchGetMenuItemsResponse{}
+	for _, id := range req.Ids {
+		resp.MenuItems = append(resp.MenuItems, benchMenuItem(id))
+	}
+	return resp, nil
+}
+
+func (s *benchMenuServer) ReserveStock(ctx context.Context, req *menuv1.ReserveStockRequest) (*menuv1.ReserveStockResponse, error) {
+	s.roundTrip()
+	return &menuv1.ReserveStockResponse{}, nil
+}
+
+// benchUserServer is a fake user service where every user exists
+type benchUserServer struct {
+	userv1.UnimplementedUserServiceServer
+}
+
+func (s *benchUserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	return &userv1.GetUserResponse{User: &userv1.User{Id: req.Id}}, nil
+}
+
+// benchServer returns an OrderServer whose user and menu clients call the
+// fakes over in-memory connections
+func benchServer(b *testing.B) (*OrderServer, *benchMenuServer) {
+	b.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	menu := &benchMenuServer{}
+	menuv1.RegisterMenuServiceServer(s, menu)
+	userv1.RegisterUserServiceServer(s, &benchUserServer{})
+	go s.Serve(lis)
+	b.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(b, err)
+	b.Cleanup(func() { conn.Close() })
+
+	return &OrderServer{
+		Orders:     repository.NewMemoryOrderRepository(),
+		UserClient: userv1.NewUserServiceClient(conn),
+		MenuClient: menuv1.NewMenuServiceClient(conn),
+	}, menu
+}
+
+// BenchmarkCreateOrder shows CreateOrder's latency staying flat as orders
+// grow: the menu items are looked up with a single BatchGetMenuItems call,
+// then reserved with a single ReserveStock call
+func BenchmarkCreateOrder(b *testing.B) {
+	for _, n := range []int{1, 5, 10, 50} {
+		b.Run(fmt.Sprintf("items=%d", n), func(b *testing.B) {
+			server, menu := benchServer(b)
+			req := &orderv1.CreateOrderRequest{UserId: 1}
+			for i := range n {
+				req.Items = append(req.Items, &orderv1.OrderItemRequest{MenuItemId: uint32(i + 1), Quantity: 1})
+			}
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for range b.N {
+				if _, err := server.CreateOrder(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(menu.calls.Load())/float64(b.N), "menu-rpcs/op")
+		})
+	}
+}
+
+// BenchmarkMenuItemLookup compares looking up an order's menu items with
+// one BatchGetMenuItems call against one GetMenuItem call per item
+func BenchmarkMenuItemLookup(b *testing.B) {
+	for _, n := range []int{1, 5, 10, 50} {
+		ids := make([]uint32, n)
+		for i := range ids {
+			ids[i] = uint32(i + 1)
+		}
+		ctx := context.Background()
+
+		b.Run(fmt.Sprintf("batch/items=%d", n), func(b *testing.B) {
+			server, _ := benchServer(b)
+			b.ResetTimer()
+			for range b.N {
+				if _, err := server.MenuClient.BatchGetMenuItems(ctx, &menuv1.BatchGetMenuItemsRequest{Ids: ids}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("per-item/items=%d", n), func(b *testing.B) {
+			server, _ := benchServer(b)
+			b.ResetTimer()
+			for range b.N {
+				for _, id := range ids {
+					if _, err := server.MenuClient.GetMenuItem(ctx, &menuv1.GetMenuItemRequest{Id: id}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -3,13 +3,54 @@ package grpc
 import (
 	"fmt"
 	"os"
+	"time"
 
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/logging"
+	"student-cafe-shared/mtls"
+	"student-cafe-shared/resilience"
+	"student-cafe-shared/tracing"
 )
 
+// Idempotent reads, retried with backoff while a service is unavailable
+var (
+	userReads = []string{"GetUser", "GetUsers"}
+	menuReads = []string{"GetMenuItem", "BatchGetMenuItems", "GetMenu", "GetCategories"}
+)
+
+// Circuit breakers shared by every connection to the user and menu services
+var (
+	userBreaker = resilience.NewBreaker("user-service", resilience.BreakerConfig{})
+	menuBreaker = resilience.NewBreaker("menu-service", resilience.BreakerConfig{})
+)
+
+// dialUserService connects to the user service at addr
+func dialUserService(addr string, callTimeout time.Duration) (*grpc.ClientConn, error) {
+	return dial(addr, resilience.DialOptions(userv1.UserService_ServiceDesc, callTimeout, userBreaker, userReads...))
+}
+
+// dialMenuService connects to the menu service at addr
+func dialMenuService(addr string, callTimeout time.Duration) (*grpc.ClientConn, error) {
+	return dial(addr, resilience.DialOptions(menuv1.MenuService_ServiceDesc, callTimeout, menuBreaker, menuReads...))
+}
+
+// dial connects to addr over TLS when TLS_CA_FILE is set, forwarding the
+// caller identity, request ID and trace context on every call
+func dial(addr string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	creds, err := mtls.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewClient(addr, append([]grpc.DialOption{
+		creds,
+		tracing.DialOption(),
+		grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor(), logging.UnaryClientInterceptor()),
+	}, opts...)...)
+}
+
 // Clients holds gRPC client connections
 type Clients struct {
 	UserClient userv1.UserServiceClient
@@ -29,20 +70,19 @@ func NewClients() (*Clients, error) {
 		menuServiceAddr = "menu-service:9092"
 	}
 
+	callTimeout, err := resilience.CallTimeout()
+	if err != nil {
+		return nil, err
+	}
+
 	// Connect to user service
-	userConn, err := grpc.NewClient(
-		userServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	userConn, err := dialUserService(userServiceAddr, callTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service at %s: %w", userServiceAddr, err)
 	}
 
 	// Connect to menu service
-	menuConn, err := grpc.NewClient(
-		menuServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	menuConn, err := dialMenuService(menuServiceAddr, callTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to menu service at %s: %w", menuServiceAddr, err)
 	}
@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"errors"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"student-cafe-shared/health"
+)
+
+// AddHealthChecks makes the order service's health depend on the user and
+// menu services being reachable and serving, since no order can be placed
+// without them
+func (s *OrderServer) AddHealthChecks(checker *health.Checker) {
+	if s.userConn != nil {
+		checker.Add("user-service", health.Remote(s.userConn, userv1.UserService_ServiceDesc.ServiceName))
+	}
+	if s.menuConn != nil {
+		checker.Add("menu-service", health.Remote(s.menuConn, menuv1.MenuService_ServiceDesc.ServiceName))
+	}
+}
+
+// Close closes the connections to the user and menu services
+func (s *OrderServer) Close() error {
+	var errs []error
+	if s.userConn != nil {
+		errs = append(errs, s.userConn.Close())
+	}
+	if s.menuConn != nil {
+		errs = append(errs, s.menuConn.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+	"order-service/models"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/grpcerr"
+)
+
+// DefaultIdempotencyKeyRetention is how long idempotency keys are honoured
+// when OrderServer.IdempotencyKeyRetention is not set
+const DefaultIdempotencyKeyRetention = 24 * time.Hour
+
+// idempotencyKeyExpiry returns the creation time at or before which keys
+// are no longer honoured
+func (s *OrderServer) idempotencyKeyExpiry() time.Time {
+	retention := s.IdempotencyKeyRetention
+	if retention <= 0 {
+		retention = DefaultIdempotencyKeyRetention
+	}
+	return time.Now().Add(-retention)
+}
+
+// requestHash fingerprints a CreateOrder request, ignoring the key itself,
+// so a key reused with a different request can be detected
+func requestHash(req *orderv1.CreateOrderRequest) (string, error) {
+	clone := proto.Clone(req).(*orderv1.CreateOrderRequest)
+	clone.IdempotencyKey = ""
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(clone)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// findIdempotentOrder returns the order previously created with the request's
+// idempotency key, or nil if the key is unused or has expired
+func (s *OrderServer) findIdempotentOrder(ctx context.Context, req *orderv1.CreateOrderRequest, hash string) (*models.Order, error) {
+	key, err := s.Orders.FindIdempotencyKey(ctx, uint(req.UserId), req.IdempotencyKey, s.idempotencyKeyExpiry())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, dberr.Error(err, "failed to look up idempotency key")
+	}
+
+	if key.RequestHash != hash {
+		return nil, grpcerr.InvalidField("idempotency_key", "idempotency key %q was already used with a different request", req.IdempotencyKey)
+	}
+
+	order, err := s.Orders.Get(ctx, key.OrderID)
+	if err != nil {
+		return nil, dberr.Error(err, "failed to load order for idempotency key")
+	}
+	return order, nil
+}
+
+// PurgeExpiredIdempotencyKeys deletes keys older than the retention window
+// and returns how many were removed
+func (s *OrderServer) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	return s.Orders.DeleteIdempotencyKeys(ctx, s.idempotencyKeyExpiry())
+}
+
+// RunIdempotencyKeyGC purges expired idempotency keys every interval until
+// ctx is cancelled
+func (s *OrderServer) RunIdempotencyKeyGC(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to purge expired idempotency keys", "error", err)
+			} else if purged > 0 {
+				slog.InfoContext(ctx, "Purged expired idempotency keys", "count", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"order-service/models"
+)
+
+var (
+	ordersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cafe_orders_created_total",
+		Help: "Orders placed, by currency.",
+	}, []string{"currency"})
+
+	orderValue = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "cafe_order_value_minor",
+		Help: "Order totals including tax in minor currency units, by currency.",
+		// 1.00 to 512.00 in the major unit
+		Buckets: prometheus.ExponentialBuckets(100, 2, 10),
+	}, []string{"currency"})
+)
+
+// recordOrderCreated counts a newly placed order and its value
+func recordOrderCreated(order *models.Order) {
+	ordersCreated.WithLabelValues(order.Currency).Inc()
+	orderValue.WithLabelValues(order.Currency).Observe(float64(order.TotalMinor))
+}
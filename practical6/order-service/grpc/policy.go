@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"order-service/repository"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+	"student-cafe-shared/dberr"
+)
+
+// Policy returns the authorization policy of the OrderService: students may
+// place orders for themselves and read, watch and cancel their own orders;
+// cafe owners may do anything, and only they may advance or delete orders.
+// Order ownership is looked up in orders.
+func Policy(orders repository.OrderRepository) authz.Policy {
+	ownerOnly := authz.RequireRole(auth.RoleCafeOwner)
+	ownOrder := authz.RoleOrOwner(auth.RoleCafeOwner, orderOwner(orders))
+	return authz.Policy{
+		orderv1.OrderService_CreateOrder_FullMethodName:       authz.RoleOrOwner(auth.RoleCafeOwner, orderRequestUser),
+		orderv1.OrderService_GetOrder_FullMethodName:          ownOrder,
+		orderv1.OrderService_GetOrders_FullMethodName:         scopeOrdersToCaller,
+		orderv1.OrderService_WatchOrder_FullMethodName:        ownOrder,
+		orderv1.OrderService_CancelOrder_FullMethodName:       ownOrder,
+		orderv1.OrderService_UpdateOrderStatus_FullMethodName: ownerOnly,
+		orderv1.OrderService_DeleteOrder_FullMethodName:       ownerOnly,
+	}
+}
+
+// orderRequestUser returns the user a CreateOrder request orders for
+func orderRequestUser(ctx context.Context, req any) (uint32, error) {
+	return req.(*orderv1.CreateOrderRequest).UserId, nil
+}
+
+// orderOwner returns the user who placed the order a request refers to
+func orderOwner(orders repository.OrderRepository) authz.OwnerFunc {
+	return func(ctx context.Context, req any) (uint32, error) {
+		id := req.(interface{ GetId() uint32 }).GetId()
+
+		order, err := orders.Get(ctx, uint(id))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, status.Errorf(codes.NotFound, "order not found")
+			}
+			return 0, dberr.Error(err, "failed to get order")
+		}
+		return uint32(order.UserID), nil
+	}
+}
+
+// scopeOrdersToCaller limits a student's GetOrders to their own orders,
+// filling in the user_id filter when it is unset
+func scopeOrdersToCaller(ctx context.Context, caller auth.Identity, req any) error {
+	if caller.Role == auth.RoleCafeOwner {
+		return nil
+	}
+
+	listReq := req.(*orderv1.GetOrdersRequest)
+	if listReq.UserId == nil {
+		listReq.UserId = &caller.UserID
+		return nil
+	}
+	if listReq.GetUserId() != caller.UserID {
+		return status.Errorf(codes.PermissionDenied, "not allowed to access another user's resources")
+	}
+	return nil
+}
@@ -2,18 +2,24 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	commonv1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
-	"order-service/database"
+	"gorm.io/gorm"
 	"order-service/models"
+	"order-service/repository"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/grpcerr"
+	"student-cafe-shared/pagination"
 )
 
 // OrderServer implements the gRPC OrderService
@@ -21,78 +27,234 @@ type OrderServer struct {
 	orderv1.UnimplementedOrderServiceServer
 	UserClient userv1.UserServiceClient
 	MenuClient menuv1.MenuServiceClient
+
+	// Orders stores the service's orders and idempotency keys
+	Orders repository.OrderRepository
+
+	// TaxRateBasisPoints is the tax charged on order subtotals, e.g. 1500 for 15%
+	TaxRateBasisPoints int64
+
+	// IdempotencyKeyRetention is how long CreateOrder idempotency keys are
+	// honoured; DefaultIdempotencyKeyRetention is used when zero
+	IdempotencyKeyRetention time.Duration
+
+	// WatchPollInterval is how often WatchOrder re-checks the database for
+	// changes made by other replicas; DefaultWatchPollInterval is used when zero
+	WatchPollInterval time.Duration
+
+	watchers orderWatchers
+
+	// userConn and menuConn are the connections NewOrderServer dialled for
+	// UserClient and MenuClient; nil when the clients were set directly
+	userConn, menuConn *grpc.ClientConn
 }
 
-// NewOrderServer creates a new gRPC order server
-func NewOrderServer(userServiceAddr, menuServiceAddr string) (*OrderServer, error) {
-	// Connect to user service
-	userConn, err := grpc.NewClient(
-		userServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+// NewOrderServer creates a new gRPC order server backed by orders, with
+// clients to the user and menu services. Every call to them is bounded by
+// callTimeout and guarded by a circuit breaker.
+func NewOrderServer(orders repository.OrderRepository, userServiceAddr, menuServiceAddr string, callTimeout time.Duration) (*OrderServer, error) {
+	userConn, err := dialUserService(userServiceAddr, callTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service: %w", err)
 	}
 
-	// Connect to menu service
-	menuConn, err := grpc.NewClient(
-		menuServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	menuConn, err := dialMenuService(menuServiceAddr, callTimeout)
 	if err != nil {
+		userConn.Close()
 		return nil, fmt.Errorf("failed to connect to menu service: %w", err)
 	}
 
 	return &OrderServer{
 		UserClient: userv1.NewUserServiceClient(userConn),
 		MenuClient: menuv1.NewMenuServiceClient(menuConn),
+		Orders:     orders,
+		userConn:   userConn,
+		menuConn:   menuConn,
 	}, nil
 }
 
 // CreateOrder creates a new order
 func (s *OrderServer) CreateOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.CreateOrderResponse, error) {
+	// Replay the original order when a request is retried with the same key
+	var hash string
+	if req.IdempotencyKey != "" {
+		var err error
+		if hash, err = requestHash(req); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash request: %v", err)
+		}
+
+		existing, err := s.findIdempotentOrder(ctx, req, hash)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return &orderv1.CreateOrderResponse{
+				Order: modelToProto(existing),
+			}, nil
+		}
+	}
+
 	// Validate user exists via gRPC
 	_, err := s.UserClient.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId})
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "user not found: %v", err)
+	if status.Code(err) == codes.NotFound {
+		return nil, grpcerr.InvalidField("user_id", "user not found: %v", err)
+	} else if err != nil {
+		// e.g. Unavailable while the user service is down
+		return nil, grpcerr.Wrap(err, "failed to look up user")
 	}
 
-	// Create order
+	// Create order in the initial lifecycle status
 	order := models.Order{
 		UserID: uint(req.UserId),
-		Status: "pending",
+		Status: models.StatusPending,
+		StatusHistory: []models.OrderStatusTransition{
+			{ToStatus: models.StatusPending},
+		},
 	}
 
-	// Validate menu items and snapshot prices via gRPC
-	for _, item := range req.Items {
-		menuItemResp, err := s.MenuClient.GetMenuItem(ctx, &menuv1.GetMenuItemRequest{Id: item.MenuItemId})
-		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "menu item %d not found: %v", item.MenuItemId, err)
+	// Look up every menu item in one call
+	ids := make([]uint32, len(req.Items))
+	for i, item := range req.Items {
+		ids[i] = item.MenuItemId
+	}
+	menuResp, err := s.MenuClient.BatchGetMenuItems(ctx, &menuv1.BatchGetMenuItemsRequest{Ids: ids})
+	if err != nil {
+		return nil, grpcerr.Wrap(err, "failed to look up menu items")
+	}
+	menuItems := make(map[uint32]*menuv1.MenuItem, len(menuResp.MenuItems))
+	for _, menuItem := range menuResp.MenuItems {
+		menuItems[menuItem.Id] = menuItem
+	}
+
+	// Validate menu items and snapshot prices
+	for i, item := range req.Items {
+		menuItem, ok := menuItems[item.MenuItemId]
+		if !ok {
+			return nil, orderItemError(codes.InvalidArgument, "MENU_ITEM_NOT_FOUND", i, item.MenuItemId,
+				"menu item %d not found", item.MenuItemId)
+		}
+
+		if !menuItem.Available {
+			return nil, orderItemError(codes.FailedPrecondition, "MENU_ITEM_UNAVAILABLE", i, item.MenuItemId,
+				"menu item %d is not available", item.MenuItemId)
+		}
+		if menuItem.Stock != nil && menuItem.GetStock() < item.Quantity {
+			return nil, orderItemError(codes.FailedPrecondition, "INSUFFICIENT_STOCK", i, item.MenuItemId,
+				"menu item %d is out of stock", item.MenuItemId)
+		}
+
+		price := menuItem.GetPrice()
+		if order.Currency == "" {
+			order.Currency = price.GetCurrencyCode()
+		} else if price.GetCurrencyCode() != order.Currency {
+			return nil, orderItemError(codes.InvalidArgument, "CURRENCY_MISMATCH", i, item.MenuItemId,
+				"menu item %d is priced in %s, order is in %s", item.MenuItemId, price.GetCurrencyCode(), order.Currency)
 		}
 
 		orderItem := models.OrderItem{
 			MenuItemID: uint(item.MenuItemId),
 			Quantity:   int(item.Quantity),
-			Price:      menuItemResp.MenuItem.Price,
+			PriceMinor: price.GetAmountMinor(),
+			Currency:   price.GetCurrencyCode(),
 		}
 		order.OrderItems = append(order.OrderItems, orderItem)
 	}
 
-	// Save order to database
-	if err := database.DB.Create(&order).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create order: %v", err)
+	order.ComputeTotals(s.TaxRateBasisPoints)
+
+	// Reserve stock last so a rejected order holds none; the menu service
+	// checks availability and stock again atomically
+	if _, err := s.MenuClient.ReserveStock(ctx, &menuv1.ReserveStockRequest{
+		Items: stockReservations(&order),
+	}); err != nil {
+		return nil, grpcerr.Wrap(err, "failed to reserve stock")
 	}
 
+	// Save order and its idempotency key atomically
+	var key *models.IdempotencyKey
+	if req.IdempotencyKey != "" {
+		key = &models.IdempotencyKey{
+			UserID:      uint(req.UserId),
+			Key:         req.IdempotencyKey,
+			RequestHash: hash,
+		}
+	}
+	if err := s.Orders.Create(ctx, &order, key, s.idempotencyKeyExpiry()); err != nil {
+		// The order was not saved, so hand its stock back
+		s.releaseStock(ctx, &order)
+
+		// A concurrent retry may have claimed the key first
+		if req.IdempotencyKey != "" {
+			existing, findErr := s.findIdempotentOrder(ctx, req, hash)
+			if findErr != nil {
+				return nil, findErr
+			}
+			if existing != nil {
+				return &orderv1.CreateOrderResponse{
+					Order: modelToProto(existing),
+				}, nil
+			}
+		}
+		return nil, dberr.Error(err, "failed to create order")
+	}
+	recordOrderCreated(&order)
+
 	return &orderv1.CreateOrderResponse{
 		Order: modelToProto(&order),
 	}, nil
 }
 
-// GetOrders retrieves all orders
+// orderItemError returns an error about items[i] of a CreateOrder request.
+// It names the offending field in a BadRequest detail and the menu item in
+// an ErrorInfo with the given reason.
+func orderItemError(code codes.Code, reason string, i int, menuItemID uint32, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	return grpcerr.New(code, msg,
+		grpcerr.BadRequest(grpcerr.Violation(fmt.Sprintf("items[%d].menu_item_id", i), msg)),
+		grpcerr.Info(reason, map[string]string{"menu_item_id": strconv.FormatUint(uint64(menuItemID), 10)}),
+	)
+}
+
+// GetOrders retrieves a page of orders, optionally filtered by user, status
+// and creation time
 func (s *OrderServer) GetOrders(ctx context.Context, req *orderv1.GetOrdersRequest) (*orderv1.GetOrdersResponse, error) {
-	var orders []models.Order
-	if err := database.DB.Preload("OrderItems").Find(&orders).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get orders: %v", err)
+	sort, err := pagination.ParseSort(req.OrderBy, repository.SortFields, pagination.Sort{Field: "id", Column: "id"})
+	if err != nil {
+		return nil, grpcerr.InvalidField("order_by", "%v", err)
+	}
+
+	var filter repository.OrderFilter
+	if req.UserId != nil {
+		userID := uint(req.GetUserId())
+		filter.UserID = &userID
+	}
+	if req.Status != "" {
+		if !models.IsValidStatus(req.Status) {
+			return nil, grpcerr.InvalidField("status", "unknown order status %q", req.Status)
+		}
+		filter.Status = req.Status
+	}
+	if req.CreatedAfter != "" {
+		if filter.CreatedAfter, err = time.Parse(time.RFC3339, req.CreatedAfter); err != nil {
+			return nil, grpcerr.InvalidField("created_after", "invalid created_after: %v", err)
+		}
+	}
+	if req.CreatedBefore != "" {
+		if filter.CreatedBefore, err = time.Parse(time.RFC3339, req.CreatedBefore); err != nil {
+			return nil, grpcerr.InvalidField("created_before", "invalid created_before: %v", err)
+		}
+	}
+
+	orders, nextPageToken, err := s.Orders.List(ctx, filter, pagination.Request{
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+		Sort:      sort,
+	})
+	if errors.Is(err, pagination.ErrInvalidPageToken) {
+		return nil, grpcerr.InvalidField("page_token", "%v", err)
+	}
+	if err != nil {
+		return nil, dberr.Error(err, "failed to get orders")
 	}
 
 	protoOrders := make([]*orderv1.Order, len(orders))
@@ -101,22 +263,120 @@ func (s *OrderServer) GetOrders(ctx context.Context, req *orderv1.GetOrdersReque
 	}
 
 	return &orderv1.GetOrdersResponse{
-		Orders: protoOrders,
+		Orders:        protoOrders,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
 // GetOrder retrieves an order by ID
 func (s *OrderServer) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
-	var order models.Order
-	if err := database.DB.Preload("OrderItems").First(&order, req.Id).Error; err != nil {
-		return nil, status.Errorf(codes.NotFound, "order not found")
+	order, err := s.Orders.Get(ctx, uint(req.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order not found")
+		}
+		return nil, dberr.Error(err, "failed to get order")
 	}
 
 	return &orderv1.GetOrderResponse{
-		Order: modelToProto(&order),
+		Order: modelToProto(order),
 	}, nil
 }
 
+// UpdateOrderStatus moves an order to a new lifecycle status
+func (s *OrderServer) UpdateOrderStatus(ctx context.Context, req *orderv1.UpdateOrderStatusRequest) (*orderv1.UpdateOrderStatusResponse, error) {
+	if !models.IsValidStatus(req.Status) {
+		return nil, grpcerr.InvalidField("status", "unknown order status %q", req.Status)
+	}
+
+	order, err := s.transitionOrder(ctx, req.Id, req.Status, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderv1.UpdateOrderStatusResponse{
+		Order: modelToProto(order),
+	}, nil
+}
+
+// CancelOrder cancels an order that has not started preparation
+func (s *OrderServer) CancelOrder(ctx context.Context, req *orderv1.CancelOrderRequest) (*orderv1.CancelOrderResponse, error) {
+	order, err := s.transitionOrder(ctx, req.Id, models.StatusCancelled, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderv1.CancelOrderResponse{
+		Order: modelToProto(order),
+	}, nil
+}
+
+// DeleteOrder soft-deletes an order. An order that could still be cancelled
+// hands its stock back, as cancelling it would.
+func (s *OrderServer) DeleteOrder(ctx context.Context, req *orderv1.DeleteOrderRequest) (*orderv1.DeleteOrderResponse, error) {
+	order, err := s.Orders.Get(ctx, uint(req.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order not found")
+		}
+		return nil, dberr.Error(err, "failed to get order")
+	}
+
+	if err := s.Orders.Delete(ctx, order.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order not found")
+		}
+		return nil, dberr.Error(err, "failed to delete order")
+	}
+
+	if models.CanTransition(order.Status, models.StatusCancelled) {
+		s.releaseStock(ctx, order)
+	}
+
+	// End any WatchOrder streams for this order
+	s.watchers.notify(order.ID)
+
+	return &orderv1.DeleteOrderResponse{}, nil
+}
+
+// transitionOrder changes an order's status if the lifecycle allows it,
+// records the transition and notifies watchers, returning the reloaded order
+func (s *OrderServer) transitionOrder(ctx context.Context, id uint32, to, reason string) (*models.Order, error) {
+	order, err := s.Orders.Get(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order not found")
+		}
+		return nil, dberr.Error(err, "failed to get order")
+	}
+
+	if !models.CanTransition(order.Status, to) {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d cannot move from %s to %s", order.ID, order.Status, to)
+	}
+
+	// The repository only moves the order if it is still in the status read
+	// above, so concurrent updates cannot both succeed
+	if err := s.Orders.UpdateStatus(ctx, order.ID, order.Status, to, reason); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return nil, status.Errorf(codes.FailedPrecondition, "order %d status changed concurrently", order.ID)
+		}
+		return nil, dberr.Error(err, "failed to update order status")
+	}
+
+	// Wake up any WatchOrder streams for this order
+	s.watchers.notify(order.ID)
+
+	if order, err = s.Orders.Get(ctx, order.ID); err != nil {
+		return nil, dberr.Error(err, "failed to reload order")
+	}
+
+	// A cancelled order no longer holds its stock
+	if to == models.StatusCancelled {
+		s.releaseStock(ctx, order)
+	}
+	return order, nil
+}
+
 // modelToProto converts a GORM Order model to proto Order message
 func modelToProto(order *models.Order) *orderv1.Order {
 	protoItems := make([]*orderv1.OrderItem, len(order.OrderItems))
@@ -126,18 +386,40 @@ func modelToProto(order *models.Order) *orderv1.Order {
 			OrderId:    uint32(item.OrderID),
 			MenuItemId: uint32(item.MenuItemID),
 			Quantity:   int32(item.Quantity),
-			Price:      item.Price,
+			Price:      money(item.PriceMinor, item.Currency),
 			CreatedAt:  item.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:  item.UpdatedAt.Format(time.RFC3339),
 		}
 	}
 
+	protoHistory := make([]*orderv1.OrderStatusTransition, len(order.StatusHistory))
+	for i, transition := range order.StatusHistory {
+		protoHistory[i] = &orderv1.OrderStatusTransition{
+			FromStatus:     transition.FromStatus,
+			ToStatus:       transition.ToStatus,
+			Reason:         transition.Reason,
+			TransitionedAt: transition.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
 	return &orderv1.Order{
-		Id:         uint32(order.ID),
-		UserId:     uint32(order.UserID),
-		Status:     order.Status,
-		OrderItems: protoItems,
-		CreatedAt:  order.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  order.UpdatedAt.Format(time.RFC3339),
+		Id:            uint32(order.ID),
+		UserId:        uint32(order.UserID),
+		Status:        order.Status,
+		OrderItems:    protoItems,
+		CreatedAt:     order.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     order.UpdatedAt.Format(time.RFC3339),
+		StatusHistory: protoHistory,
+		Subtotal:      money(order.SubtotalMinor, order.Currency),
+		Tax:           money(order.TaxMinor, order.Currency),
+		Total:         money(order.TotalMinor, order.Currency),
+	}
+}
+
+// money builds a proto Money value from minor units and a currency code
+func money(amountMinor int64, currency string) *commonv1.Money {
+	return &commonv1.Money{
+		CurrencyCode: currency,
+		AmountMinor:  amountMinor,
 	}
 }
@@ -2,22 +2,29 @@ package grpc
 
 import (
 	"context"
-	"order-service/database"
+	"errors"
 	"order-service/models"
+	"order-service/repository"
 	"testing"
 	"time"
 
+	commonv1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
 )
 
 // MockUserServiceClient is a mock for UserServiceClient
@@ -41,6 +48,14 @@ func (m *MockUserServiceClient) GetUser(ctx context.Context, req *userv1.GetUser
 	return args.Get(0).(*userv1.GetUserResponse), args.Error(1)
 }
 
+func (m *MockUserServiceClient) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersRequest, opts ...grpc.CallOption) (*userv1.BatchGetUsersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userv1.BatchGetUsersResponse), args.Error(1)
+}
+
 func (m *MockUserServiceClient) GetUsers(ctx context.Context, req *userv1.GetUsersRequest, opts ...grpc.CallOption) (*userv1.GetUsersResponse, error) {
 	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
@@ -49,6 +64,38 @@ func (m *MockUserServiceClient) GetUsers(ctx context.Context, req *userv1.GetUse
 	return args.Get(0).(*userv1.GetUsersResponse), args.Error(1)
 }
 
+func (m *MockUserServiceClient) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest, opts ...grpc.CallOption) (*userv1.UpdateUserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userv1.UpdateUserResponse), args.Error(1)
+}
+
+func (m *MockUserServiceClient) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest, opts ...grpc.CallOption) (*userv1.DeleteUserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userv1.DeleteUserResponse), args.Error(1)
+}
+
+func (m *MockUserServiceClient) Login(ctx context.Context, req *userv1.LoginRequest, opts ...grpc.CallOption) (*userv1.LoginResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userv1.LoginResponse), args.Error(1)
+}
+
+func (m *MockUserServiceClient) RefreshToken(ctx context.Context, req *userv1.RefreshTokenRequest, opts ...grpc.CallOption) (*userv1.RefreshTokenResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userv1.RefreshTokenResponse), args.Error(1)
+}
+
 // MockMenuServiceClient is a mock for MenuServiceClient
 type MockMenuServiceClient struct {
 	mock.Mock
@@ -62,6 +109,14 @@ func (m *MockMenuServiceClient) GetMenuItem(ctx context.Context, req *menuv1.Get
 	return args.Get(0).(*menuv1.GetMenuItemResponse), args.Error(1)
 }
 
+func (m *MockMenuServiceClient) BatchGetMenuItems(ctx context.Context, req *menuv1.BatchGetMenuItemsRequest, opts ...grpc.CallOption) (*menuv1.BatchGetMenuItemsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.BatchGetMenuItemsResponse), args.Error(1)
+}
+
 func (m *MockMenuServiceClient) GetMenu(ctx context.Context, req *menuv1.GetMenuRequest, opts ...grpc.CallOption) (*menuv1.GetMenuResponse, error) {
 	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
@@ -78,13 +133,82 @@ func (m *MockMenuServiceClient) CreateMenuItem(ctx context.Context, req *menuv1.
 	return args.Get(0).(*menuv1.CreateMenuItemResponse), args.Error(1)
 }
 
+func (m *MockMenuServiceClient) UpdateMenuItem(ctx context.Context, req *menuv1.UpdateMenuItemRequest, opts ...grpc.CallOption) (*menuv1.UpdateMenuItemResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.UpdateMenuItemResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) DeleteMenuItem(ctx context.Context, req *menuv1.DeleteMenuItemRequest, opts ...grpc.CallOption) (*menuv1.DeleteMenuItemResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.DeleteMenuItemResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) CreateCategory(ctx context.Context, req *menuv1.CreateCategoryRequest, opts ...grpc.CallOption) (*menuv1.CreateCategoryResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.CreateCategoryResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) GetCategories(ctx context.Context, req *menuv1.GetCategoriesRequest, opts ...grpc.CallOption) (*menuv1.GetCategoriesResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.GetCategoriesResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) SetMenuItemAvailability(ctx context.Context, req *menuv1.SetMenuItemAvailabilityRequest, opts ...grpc.CallOption) (*menuv1.SetMenuItemAvailabilityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.SetMenuItemAvailabilityResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) SetMenuItemStock(ctx context.Context, req *menuv1.SetMenuItemStockRequest, opts ...grpc.CallOption) (*menuv1.SetMenuItemStockResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.SetMenuItemStockResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) ReserveStock(ctx context.Context, req *menuv1.ReserveStockRequest, opts ...grpc.CallOption) (*menuv1.ReserveStockResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.ReserveStockResponse), args.Error(1)
+}
+
+func (m *MockMenuServiceClient) ReleaseStock(ctx context.Context, req *menuv1.ReleaseStockRequest, opts ...grpc.CallOption) (*menuv1.ReleaseStockResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*menuv1.ReleaseStockResponse), args.Error(1)
+}
+
+// usd builds a USD Money value from cents
+func usd(cents int64) *commonv1.Money {
+	return &commonv1.Money{CurrencyCode: "USD", AmountMinor: cents}
+}
+
 // setupTestDB creates an in-memory SQLite database for testing
 func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
 	require.NoError(t, err, "Failed to open test database")
 
 	// Auto-migrate the Order and OrderItem models
-	err = db.AutoMigrate(&models.Order{}, &models.OrderItem{})
+	err = db.AutoMigrate(&models.Order{}, &models.OrderItem{}, &models.OrderStatusTransition{}, &models.IdempotencyKey{})
 	require.NoError(t, err, "Failed to migrate test database")
 
 	return db
@@ -101,12 +225,12 @@ func TestCreateOrder_Success(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
 
 	mockUserClient := new(MockUserServiceClient)
 	mockMenuClient := new(MockMenuServiceClient)
 
 	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
 		UserClient: mockUserClient,
 		MenuClient: mockMenuClient,
 	}
@@ -117,19 +241,25 @@ func TestCreateOrder_Success(t *testing.T) {
 			User: &userv1.User{Id: 1, Name: "Test User", Email: "test@example.com"},
 		}, nil)
 
-	// Mock menu item lookup
-	mockMenuClient.On("GetMenuItem", mock.Anything, &menuv1.GetMenuItemRequest{Id: 1}).
-		Return(&menuv1.GetMenuItemResponse{
-			MenuItem: &menuv1.MenuItem{Id: 1, Name: "Coffee", Price: 2.50},
+	// Mock menu item lookup: both items in one call
+	mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1, 2}}).
+		Return(&menuv1.BatchGetMenuItemsResponse{
+			MenuItems: []*menuv1.MenuItem{
+				{Id: 1, Available: true, Name: "Coffee", Price: usd(250)},
+				{Id: 2, Available: true, Name: "Tea", Price: usd(200)},
+			},
 		}, nil)
 
-	mockMenuClient.On("GetMenuItem", mock.Anything, &menuv1.GetMenuItemRequest{Id: 2}).
-		Return(&menuv1.GetMenuItemResponse{
-			MenuItem: &menuv1.MenuItem{Id: 2, Name: "Tea", Price: 2.00},
-		}, nil)
+	// Mock stock reservation for both items
+	mockMenuClient.On("ReserveStock", mock.Anything, mock.MatchedBy(func(req *menuv1.ReserveStockRequest) bool {
+		return len(req.Items) == 2 &&
+			req.Items[0].MenuItemId == 1 && req.Items[0].Quantity == 2 &&
+			req.Items[1].MenuItemId == 2 && req.Items[1].Quantity == 1
+	})).Return(&menuv1.ReserveStockResponse{}, nil)
 
 	// Test
 	ctx := context.Background()
+	created := testutil.ToFloat64(ordersCreated.WithLabelValues("USD"))
 	resp, err := server.CreateOrder(ctx, &orderv1.CreateOrderRequest{
 		UserId: 1,
 		Items: []*orderv1.OrderItemRequest{
@@ -145,16 +275,27 @@ func TestCreateOrder_Success(t *testing.T) {
 	assert.Equal(t, uint32(1), resp.Order.UserId)
 	assert.Equal(t, "pending", resp.Order.Status)
 	assert.Len(t, resp.Order.OrderItems, 2)
+	require.Len(t, resp.Order.StatusHistory, 1)
+	assert.Equal(t, "pending", resp.Order.StatusHistory[0].ToStatus)
 
 	// Verify first item
 	assert.Equal(t, uint32(1), resp.Order.OrderItems[0].MenuItemId)
 	assert.Equal(t, int32(2), resp.Order.OrderItems[0].Quantity)
-	assert.InDelta(t, 2.50, resp.Order.OrderItems[0].Price, 0.001)
+	assert.Equal(t, int64(250), resp.Order.OrderItems[0].Price.AmountMinor)
 
 	// Verify second item
 	assert.Equal(t, uint32(2), resp.Order.OrderItems[1].MenuItemId)
 	assert.Equal(t, int32(1), resp.Order.OrderItems[1].Quantity)
-	assert.InDelta(t, 2.00, resp.Order.OrderItems[1].Price, 0.001)
+	assert.Equal(t, int64(200), resp.Order.OrderItems[1].Price.AmountMinor)
+
+	// Verify totals are computed server-side
+	assert.Equal(t, int64(700), resp.Order.Subtotal.AmountMinor)
+	assert.Equal(t, int64(0), resp.Order.Tax.AmountMinor)
+	assert.Equal(t, int64(700), resp.Order.Total.AmountMinor)
+	assert.Equal(t, "USD", resp.Order.Total.CurrencyCode)
+
+	// Verify the order is counted
+	assert.Equal(t, created+1, testutil.ToFloat64(ordersCreated.WithLabelValues("USD")))
 
 	mockUserClient.AssertExpectations(t)
 	mockMenuClient.AssertExpectations(t)
@@ -164,12 +305,12 @@ func TestCreateOrder_InvalidUser(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
 
 	mockUserClient := new(MockUserServiceClient)
 	mockMenuClient := new(MockMenuServiceClient)
 
 	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
 		UserClient: mockUserClient,
 		MenuClient: mockMenuClient,
 	}
@@ -198,16 +339,56 @@ func TestCreateOrder_InvalidUser(t *testing.T) {
 	mockUserClient.AssertExpectations(t)
 }
 
+func TestCreateOrder_UserServiceUnavailable(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	mockUserClient := new(MockUserServiceClient)
+	mockMenuClient := new(MockMenuServiceClient)
+
+	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
+		UserClient: mockUserClient,
+		MenuClient: mockMenuClient,
+	}
+
+	// Mock the user service being down, e.g. behind an open circuit breaker
+	mockUserClient.On("GetUser", mock.Anything, &userv1.GetUserRequest{Id: 1}).
+		Return(nil, status.Errorf(codes.Unavailable, "user-service is unavailable: circuit breaker open"))
+
+	// Test
+	ctx := context.Background()
+	resp, err := server.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+		UserId: 1,
+		Items: []*orderv1.OrderItemRequest{
+			{MenuItemId: 1, Quantity: 1},
+		},
+	})
+
+	// Assert: the outage is not reported as a bad request
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	var count int64
+	db.Model(&models.Order{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	mockUserClient.AssertExpectations(t)
+	mockMenuClient.AssertNotCalled(t, "BatchGetMenuItems", mock.Anything, mock.Anything)
+}
+
 func TestCreateOrder_InvalidMenuItem(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
 
 	mockUserClient := new(MockUserServiceClient)
 	mockMenuClient := new(MockMenuServiceClient)
 
 	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
 		UserClient: mockUserClient,
 		MenuClient: mockMenuClient,
 	}
@@ -218,15 +399,19 @@ func TestCreateOrder_InvalidMenuItem(t *testing.T) {
 			User: &userv1.User{Id: 1, Name: "Test User"},
 		}, nil)
 
-	// Mock menu item lookup failure
-	mockMenuClient.On("GetMenuItem", mock.Anything, &menuv1.GetMenuItemRequest{Id: 999}).
-		Return(nil, status.Errorf(codes.NotFound, "menu item not found"))
+	// Mock menu item lookup: the first item exists, the second does not
+	mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1, 999}}).
+		Return(&menuv1.BatchGetMenuItemsResponse{
+			MenuItems:  []*menuv1.MenuItem{{Id: 1, Name: "Coffee", Price: usd(250), Available: true}},
+			MissingIds: []uint32{999},
+		}, nil)
 
 	// Test
 	ctx := context.Background()
 	resp, err := server.CreateOrder(ctx, &orderv1.CreateOrderRequest{
 		UserId: 1,
 		Items: []*orderv1.OrderItemRequest{
+			{MenuItemId: 1, Quantity: 1},
 			{MenuItemId: 999, Quantity: 1},
 		},
 	})
@@ -239,6 +424,17 @@ func TestCreateOrder_InvalidMenuItem(t *testing.T) {
 	assert.Equal(t, codes.InvalidArgument, st.Code())
 	assert.Contains(t, st.Message(), "menu item 999 not found")
 
+	// The error details name the failing item
+	require.Len(t, st.Details(), 2)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 1)
+	assert.Equal(t, "items[1].menu_item_id", badRequest.FieldViolations[0].Field)
+	info, ok := st.Details()[1].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "MENU_ITEM_NOT_FOUND", info.Reason)
+	assert.Equal(t, "999", info.Metadata["menu_item_id"])
+
 	mockUserClient.AssertExpectations(t)
 	mockMenuClient.AssertExpectations(t)
 }
@@ -247,12 +443,12 @@ func TestGetOrder(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
 
 	mockUserClient := new(MockUserServiceClient)
 	mockMenuClient := new(MockMenuServiceClient)
 
 	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
 		UserClient: mockUserClient,
 		MenuClient: mockMenuClient,
 	}
@@ -262,7 +458,7 @@ func TestGetOrder(t *testing.T) {
 		UserID: 1,
 		Status: "pending",
 		OrderItems: []models.OrderItem{
-			{MenuItemID: 1, Quantity: 2, Price: 2.50},
+			{MenuItemID: 1, Quantity: 2, PriceMinor: 250, Currency: "USD"},
 		},
 	}
 	err := db.Create(&testOrder).Error
@@ -309,18 +505,39 @@ func TestGetOrder(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("database failures are not reported as a missing order", func(t *testing.T) {
+		for err, want := range map[error]codes.Code{
+			errors.New("connection refused"): codes.Internal,
+			context.DeadlineExceeded:         codes.DeadlineExceeded,
+		} {
+			failing := &OrderServer{Orders: failingOrders{OrderRepository: server.Orders, err: err}}
+			_, gotErr := failing.GetOrder(context.Background(), &orderv1.GetOrderRequest{Id: uint32(testOrder.ID)})
+			assert.Equal(t, want, status.Code(gotErr), err.Error())
+		}
+	})
+}
+
+// failingOrders fails every Get with err
+type failingOrders struct {
+	repository.OrderRepository
+	err error
+}
+
+func (r failingOrders) Get(ctx context.Context, id uint) (*models.Order, error) {
+	return nil, r.err
 }
 
 func TestGetOrders(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
 
 	mockUserClient := new(MockUserServiceClient)
 	mockMenuClient := new(MockMenuServiceClient)
 
 	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
 		UserClient: mockUserClient,
 		MenuClient: mockMenuClient,
 	}
@@ -341,14 +558,14 @@ func TestGetOrders(t *testing.T) {
 			UserID: 1,
 			Status: "pending",
 			OrderItems: []models.OrderItem{
-				{MenuItemID: 1, Quantity: 2, Price: 2.50},
+				{MenuItemID: 1, Quantity: 2, PriceMinor: 250, Currency: "USD"},
 			},
 		},
 		{
 			UserID: 2,
 			Status: "completed",
 			OrderItems: []models.OrderItem{
-				{MenuItemID: 2, Quantity: 1, Price: 3.00},
+				{MenuItemID: 2, Quantity: 1, PriceMinor: 300, Currency: "USD"},
 			},
 		},
 	}
@@ -376,6 +593,97 @@ func TestGetOrders(t *testing.T) {
 	})
 }
 
+func TestGetOrders_PaginationAndFilters(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db)}
+	ctx := context.Background()
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	testOrders := []models.Order{
+		{UserID: 1, Status: models.StatusPending, TotalMinor: 500, Currency: "USD"},
+		{UserID: 2, Status: models.StatusReady, TotalMinor: 300, Currency: "USD"},
+		{UserID: 1, Status: models.StatusCollected, TotalMinor: 900, Currency: "USD"},
+		{UserID: 1, Status: models.StatusPending, TotalMinor: 300, Currency: "USD"},
+		{UserID: 3, Status: models.StatusCancelled, TotalMinor: 100, Currency: "USD"},
+	}
+	for i := range testOrders {
+		testOrders[i].CreatedAt = day.Add(time.Duration(i) * time.Hour)
+		testOrders[i].OrderItems = []models.OrderItem{{MenuItemID: 1, Quantity: 1, PriceMinor: testOrders[i].TotalMinor, Currency: "USD"}}
+		require.NoError(t, db.Create(&testOrders[i]).Error)
+	}
+
+	ids := func(orders []*orderv1.Order) []uint32 {
+		result := make([]uint32, len(orders))
+		for i, order := range orders {
+			result[i] = order.Id
+		}
+		return result
+	}
+	id := func(i int) uint32 { return uint32(testOrders[i].ID) }
+
+	t.Run("pages newest first", func(t *testing.T) {
+		var got []uint32
+		req := &orderv1.GetOrdersRequest{PageSize: 2, OrderBy: "created_at desc"}
+		for {
+			resp, err := server.GetOrders(ctx, req)
+			require.NoError(t, err)
+			for _, order := range resp.Orders {
+				assert.Len(t, order.OrderItems, 1, "items are preloaded")
+			}
+			got = append(got, ids(resp.Orders)...)
+			if resp.NextPageToken == "" {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+		assert.Equal(t, []uint32{id(4), id(3), id(2), id(1), id(0)}, got)
+	})
+
+	t.Run("pages by total with ties", func(t *testing.T) {
+		resp, err := server.GetOrders(ctx, &orderv1.GetOrdersRequest{PageSize: 2, OrderBy: "total"})
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{id(4), id(1)}, ids(resp.Orders))
+
+		resp, err = server.GetOrders(ctx, &orderv1.GetOrdersRequest{PageSize: 2, OrderBy: "total", PageToken: resp.NextPageToken})
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{id(3), id(0)}, ids(resp.Orders))
+	})
+
+	t.Run("filter by user and status", func(t *testing.T) {
+		resp, err := server.GetOrders(ctx, &orderv1.GetOrdersRequest{UserId: proto.Uint32(1), Status: models.StatusPending})
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{id(0), id(3)}, ids(resp.Orders))
+	})
+
+	t.Run("filter by date range", func(t *testing.T) {
+		resp, err := server.GetOrders(ctx, &orderv1.GetOrdersRequest{
+			CreatedAfter:  day.Add(time.Hour).Format(time.RFC3339),
+			CreatedBefore: day.Add(3 * time.Hour).Format(time.RFC3339),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{id(1), id(2)}, ids(resp.Orders))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, req := range []*orderv1.GetOrdersRequest{
+			{Status: "lost"},
+			{CreatedAfter: "yesterday"},
+			{CreatedBefore: "2026-03-01"},
+			{OrderBy: "user_id"},
+			{PageToken: "garbage"},
+		} {
+			_, err := server.GetOrders(ctx, req)
+			require.Error(t, err)
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code(), "request %v", req)
+		}
+	})
+}
+
 func TestModelToProto(t *testing.T) {
 	now := time.Now()
 	order := &models.Order{
@@ -396,7 +704,8 @@ func TestModelToProto(t *testing.T) {
 				OrderID:    1,
 				MenuItemID: 2,
 				Quantity:   3,
-				Price:      4.50,
+				PriceMinor: 450,
+				Currency:   "USD",
 			},
 		},
 	}
@@ -415,19 +724,20 @@ func TestModelToProto(t *testing.T) {
 	assert.Equal(t, uint32(1), protoOrder.OrderItems[0].OrderId)
 	assert.Equal(t, uint32(2), protoOrder.OrderItems[0].MenuItemId)
 	assert.Equal(t, int32(3), protoOrder.OrderItems[0].Quantity)
-	assert.InDelta(t, 4.50, protoOrder.OrderItems[0].Price, 0.001)
+	assert.Equal(t, int64(450), protoOrder.OrderItems[0].Price.AmountMinor)
+	assert.Equal(t, "USD", protoOrder.OrderItems[0].Price.CurrencyCode)
 }
 
 func TestCreateOrder_PriceSnapshot(t *testing.T) {
 	// This test verifies that prices are snapshotted at order creation time
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
 
 	mockUserClient := new(MockUserServiceClient)
 	mockMenuClient := new(MockMenuServiceClient)
 
 	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
 		UserClient: mockUserClient,
 		MenuClient: mockMenuClient,
 	}
@@ -439,11 +749,13 @@ func TestCreateOrder_PriceSnapshot(t *testing.T) {
 		}, nil)
 
 	// Mock menu item with specific price
-	originalPrice := 5.99
-	mockMenuClient.On("GetMenuItem", mock.Anything, &menuv1.GetMenuItemRequest{Id: 1}).
-		Return(&menuv1.GetMenuItemResponse{
-			MenuItem: &menuv1.MenuItem{Id: 1, Name: "Special", Price: originalPrice},
+	originalPrice := int64(599)
+	mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1}}).
+		Return(&menuv1.BatchGetMenuItemsResponse{
+			MenuItems: []*menuv1.MenuItem{{Id: 1, Available: true, Name: "Special", Price: usd(originalPrice)}},
 		}, nil)
+	mockMenuClient.On("ReserveStock", mock.Anything, mock.Anything).
+		Return(&menuv1.ReserveStockResponse{}, nil)
 
 	// Create order
 	ctx := context.Background()
@@ -455,14 +767,668 @@ func TestCreateOrder_PriceSnapshot(t *testing.T) {
 	})
 
 	require.NoError(t, err)
-	assert.InDelta(t, originalPrice, resp.Order.OrderItems[0].Price, 0.001)
+	assert.Equal(t, originalPrice, resp.Order.OrderItems[0].Price.AmountMinor)
 
 	// Verify price is stored in database
 	var dbOrder models.Order
 	err = db.Preload("OrderItems").First(&dbOrder, resp.Order.Id).Error
 	require.NoError(t, err)
-	assert.InDelta(t, originalPrice, dbOrder.OrderItems[0].Price, 0.001)
+	assert.Equal(t, originalPrice, dbOrder.OrderItems[0].PriceMinor)
 
 	mockUserClient.AssertExpectations(t)
 	mockMenuClient.AssertExpectations(t)
 }
+
+func TestUpdateOrderStatus_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db)}
+
+	testOrder := models.Order{UserID: 1, Status: models.StatusPending}
+	require.NoError(t, db.Create(&testOrder).Error)
+
+	ctx := context.Background()
+	lifecycle := []string{
+		models.StatusConfirmed,
+		models.StatusPreparing,
+		models.StatusReady,
+		models.StatusCollected,
+	}
+
+	for _, next := range lifecycle {
+		resp, err := server.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{
+			Id:     uint32(testOrder.ID),
+			Status: next,
+		})
+		require.NoError(t, err, "transition to %s", next)
+		assert.Equal(t, next, resp.Order.Status)
+	}
+
+	// Every transition is recorded with its previous status
+	resp, err := server.GetOrder(ctx, &orderv1.GetOrderRequest{Id: uint32(testOrder.ID)})
+	require.NoError(t, err)
+	require.Len(t, resp.Order.StatusHistory, len(lifecycle))
+
+	from := models.StatusPending
+	for i, transition := range resp.Order.StatusHistory {
+		assert.Equal(t, from, transition.FromStatus)
+		assert.Equal(t, lifecycle[i], transition.ToStatus)
+		assert.NotEmpty(t, transition.TransitionedAt)
+		from = transition.ToStatus
+	}
+}
+
+func TestUpdateOrderStatus_Errors(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db)}
+
+	pendingOrder := models.Order{UserID: 1, Status: models.StatusPending}
+	require.NoError(t, db.Create(&pendingOrder).Error)
+
+	collectedOrder := models.Order{UserID: 1, Status: models.StatusCollected}
+	require.NoError(t, db.Create(&collectedOrder).Error)
+
+	tests := []struct {
+		name         string
+		orderID      uint32
+		status       string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "skip ahead from pending to ready",
+			orderID:      uint32(pendingOrder.ID),
+			status:       models.StatusReady,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "move a collected order",
+			orderID:      uint32(collectedOrder.ID),
+			status:       models.StatusPending,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "unknown status",
+			orderID:      uint32(pendingOrder.ID),
+			status:       "eaten",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "non-existent order",
+			orderID:      9999,
+			status:       models.StatusConfirmed,
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.UpdateOrderStatus(context.Background(), &orderv1.UpdateOrderStatusRequest{
+				Id:     tt.orderID,
+				Status: tt.status,
+			})
+
+			require.Error(t, err)
+			assert.Nil(t, resp)
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedCode, st.Code())
+		})
+	}
+
+	// A rejected transition leaves the order untouched
+	var dbOrder models.Order
+	require.NoError(t, db.First(&dbOrder, pendingOrder.ID).Error)
+	assert.Equal(t, models.StatusPending, dbOrder.Status)
+}
+
+func TestCancelOrder(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db)}
+
+	confirmedOrder := models.Order{UserID: 1, Status: models.StatusConfirmed}
+	require.NoError(t, db.Create(&confirmedOrder).Error)
+
+	preparingOrder := models.Order{UserID: 1, Status: models.StatusPreparing}
+	require.NoError(t, db.Create(&preparingOrder).Error)
+
+	ctx := context.Background()
+
+	t.Run("cancel before preparation", func(t *testing.T) {
+		resp, err := server.CancelOrder(ctx, &orderv1.CancelOrderRequest{
+			Id:     uint32(confirmedOrder.ID),
+			Reason: "changed my mind",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusCancelled, resp.Order.Status)
+		require.Len(t, resp.Order.StatusHistory, 1)
+		assert.Equal(t, "changed my mind", resp.Order.StatusHistory[0].Reason)
+	})
+
+	t.Run("cancel during preparation", func(t *testing.T) {
+		_, err := server.CancelOrder(ctx, &orderv1.CancelOrderRequest{
+			Id: uint32(preparingOrder.ID),
+		})
+
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.FailedPrecondition, st.Code())
+	})
+}
+
+func TestCreateOrder_StockChecks(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	tests := []struct {
+		name       string
+		menuItem   *menuv1.MenuItem
+		reserveErr error
+	}{
+		{
+			name:     "unavailable item",
+			menuItem: &menuv1.MenuItem{Id: 1, Available: false, Price: usd(250)},
+		},
+		{
+			name:     "not enough stock",
+			menuItem: &menuv1.MenuItem{Id: 1, Available: true, Stock: proto.Int32(1), Price: usd(250)},
+		},
+		{
+			name:       "stock taken by a concurrent order",
+			menuItem:   &menuv1.MenuItem{Id: 1, Available: true, Stock: proto.Int32(5), Price: usd(250)},
+			reserveErr: status.Error(codes.FailedPrecondition, "menu item 1 is out of stock"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserClient := new(MockUserServiceClient)
+			mockMenuClient := new(MockMenuServiceClient)
+			server := &OrderServer{
+				Orders:     repository.NewGormOrderRepository(db),
+				UserClient: mockUserClient,
+				MenuClient: mockMenuClient,
+			}
+
+			mockUserClient.On("GetUser", mock.Anything, &userv1.GetUserRequest{Id: 1}).
+				Return(&userv1.GetUserResponse{User: &userv1.User{Id: 1}}, nil)
+			mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1}}).
+				Return(&menuv1.BatchGetMenuItemsResponse{MenuItems: []*menuv1.MenuItem{tt.menuItem}}, nil)
+			if tt.reserveErr != nil {
+				mockMenuClient.On("ReserveStock", mock.Anything, mock.Anything).Return(nil, tt.reserveErr)
+			}
+
+			_, err := server.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+				UserId: 1,
+				Items:  []*orderv1.OrderItemRequest{{MenuItemId: 1, Quantity: 2}},
+			})
+
+			require.Error(t, err)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			mockMenuClient.AssertExpectations(t)
+
+			var count int64
+			require.NoError(t, db.Model(&models.Order{}).Count(&count).Error)
+			assert.Zero(t, count, "no order is saved")
+		})
+	}
+}
+
+func TestCancelOrder_ReleasesStock(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	mockMenuClient := new(MockMenuServiceClient)
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db), MenuClient: mockMenuClient}
+
+	order := models.Order{
+		UserID: 1,
+		Status: models.StatusPending,
+		OrderItems: []models.OrderItem{
+			{MenuItemID: 3, Quantity: 2, PriceMinor: 250, Currency: "USD"},
+		},
+	}
+	require.NoError(t, db.Create(&order).Error)
+
+	mockMenuClient.On("ReleaseStock", mock.Anything, &menuv1.ReleaseStockRequest{
+		Items: []*menuv1.StockReservation{{MenuItemId: 3, Quantity: 2}},
+	}).Return(&menuv1.ReleaseStockResponse{}, nil)
+
+	_, err := server.CancelOrder(context.Background(), &orderv1.CancelOrderRequest{Id: uint32(order.ID)})
+	require.NoError(t, err)
+	mockMenuClient.AssertExpectations(t)
+}
+
+func TestDeleteOrder(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	mockMenuClient := new(MockMenuServiceClient)
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db), MenuClient: mockMenuClient}
+	ctx := context.Background()
+
+	items := func() []models.OrderItem {
+		return []models.OrderItem{{MenuItemID: 3, Quantity: 2, PriceMinor: 250, Currency: "USD"}}
+	}
+	pendingOrder := models.Order{UserID: 1, Status: models.StatusPending, OrderItems: items()}
+	require.NoError(t, db.Create(&pendingOrder).Error)
+	collectedOrder := models.Order{UserID: 1, Status: models.StatusCollected, OrderItems: items()}
+	require.NoError(t, db.Create(&collectedOrder).Error)
+
+	t.Run("pending order releases its stock", func(t *testing.T) {
+		mockMenuClient.On("ReleaseStock", mock.Anything, &menuv1.ReleaseStockRequest{
+			Items: []*menuv1.StockReservation{{MenuItemId: 3, Quantity: 2}},
+		}).Return(&menuv1.ReleaseStockResponse{}, nil).Once()
+
+		_, err := server.DeleteOrder(ctx, &orderv1.DeleteOrderRequest{Id: uint32(pendingOrder.ID)})
+		require.NoError(t, err)
+		mockMenuClient.AssertExpectations(t)
+
+		_, err = server.GetOrder(ctx, &orderv1.GetOrderRequest{Id: uint32(pendingOrder.ID)})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		var deleted models.Order
+		require.NoError(t, db.Unscoped().First(&deleted, pendingOrder.ID).Error)
+		assert.True(t, deleted.DeletedAt.Valid)
+	})
+
+	t.Run("collected order keeps its stock consumed", func(t *testing.T) {
+		_, err := server.DeleteOrder(ctx, &orderv1.DeleteOrderRequest{Id: uint32(collectedOrder.ID)})
+		require.NoError(t, err)
+		mockMenuClient.AssertNumberOfCalls(t, "ReleaseStock", 1)
+
+		list, err := server.GetOrders(ctx, &orderv1.GetOrdersRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, list.Orders)
+	})
+
+	t.Run("missing order", func(t *testing.T) {
+		_, err := server.DeleteOrder(ctx, &orderv1.DeleteOrderRequest{Id: uint32(pendingOrder.ID)})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestCreateOrder_TotalsWithTax(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	mockUserClient := new(MockUserServiceClient)
+	mockMenuClient := new(MockMenuServiceClient)
+
+	server := &OrderServer{
+		Orders:             repository.NewGormOrderRepository(db),
+		UserClient:         mockUserClient,
+		MenuClient:         mockMenuClient,
+		TaxRateBasisPoints: 1500, // 15%
+	}
+
+	mockUserClient.On("GetUser", mock.Anything, &userv1.GetUserRequest{Id: 1}).
+		Return(&userv1.GetUserResponse{User: &userv1.User{Id: 1}}, nil)
+	mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1}}).
+		Return(&menuv1.BatchGetMenuItemsResponse{
+			MenuItems: []*menuv1.MenuItem{{Id: 1, Available: true, Name: "Coffee", Price: usd(333)}},
+		}, nil)
+	mockMenuClient.On("ReserveStock", mock.Anything, mock.Anything).
+		Return(&menuv1.ReserveStockResponse{}, nil)
+
+	resp, err := server.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		UserId: 1,
+		Items: []*orderv1.OrderItemRequest{
+			{MenuItemId: 1, Quantity: 3},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(999), resp.Order.Subtotal.AmountMinor)
+	assert.Equal(t, int64(150), resp.Order.Tax.AmountMinor) // 149.85 rounds half up
+	assert.Equal(t, int64(1149), resp.Order.Total.AmountMinor)
+
+	// Totals are persisted on the order
+	var dbOrder models.Order
+	require.NoError(t, db.First(&dbOrder, resp.Order.Id).Error)
+	assert.Equal(t, int64(999), dbOrder.SubtotalMinor)
+	assert.Equal(t, int64(150), dbOrder.TaxMinor)
+	assert.Equal(t, int64(1149), dbOrder.TotalMinor)
+	assert.Equal(t, "USD", dbOrder.Currency)
+}
+
+func TestCreateOrder_MixedCurrencies(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	mockUserClient := new(MockUserServiceClient)
+	mockMenuClient := new(MockMenuServiceClient)
+
+	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
+		UserClient: mockUserClient,
+		MenuClient: mockMenuClient,
+	}
+
+	mockUserClient.On("GetUser", mock.Anything, &userv1.GetUserRequest{Id: 1}).
+		Return(&userv1.GetUserResponse{User: &userv1.User{Id: 1}}, nil)
+	mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1, 2}}).
+		Return(&menuv1.BatchGetMenuItemsResponse{
+			MenuItems: []*menuv1.MenuItem{
+				{Id: 1, Available: true, Price: usd(250)},
+				{Id: 2, Available: true, Price: &commonv1.Money{CurrencyCode: "EUR", AmountMinor: 300}},
+			},
+		}, nil)
+
+	_, err := server.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		UserId: 1,
+		Items: []*orderv1.OrderItemRequest{
+			{MenuItemId: 1, Quantity: 1},
+			{MenuItemId: 2, Quantity: 1},
+		},
+	})
+
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "menu item 2 is priced in EUR")
+}
+
+func TestCreateOrder_IdempotencyKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	mockUserClient := new(MockUserServiceClient)
+	mockMenuClient := new(MockMenuServiceClient)
+
+	server := &OrderServer{
+		Orders:     repository.NewGormOrderRepository(db),
+		UserClient: mockUserClient,
+		MenuClient: mockMenuClient,
+	}
+
+	mockUserClient.On("GetUser", mock.Anything, &userv1.GetUserRequest{Id: 1}).
+		Return(&userv1.GetUserResponse{User: &userv1.User{Id: 1}}, nil)
+	mockMenuClient.On("BatchGetMenuItems", mock.Anything, &menuv1.BatchGetMenuItemsRequest{Ids: []uint32{1}}).
+		Return(&menuv1.BatchGetMenuItemsResponse{
+			MenuItems: []*menuv1.MenuItem{{Id: 1, Available: true, Name: "Coffee", Price: usd(250)}},
+		}, nil)
+	mockMenuClient.On("ReserveStock", mock.Anything, mock.Anything).
+		Return(&menuv1.ReserveStockResponse{}, nil)
+
+	req := &orderv1.CreateOrderRequest{
+		UserId:         1,
+		Items:          []*orderv1.OrderItemRequest{{MenuItemId: 1, Quantity: 1}},
+		IdempotencyKey: "retry-me",
+	}
+	ctx := context.Background()
+
+	created := testutil.ToFloat64(ordersCreated.WithLabelValues("USD"))
+	first, err := server.CreateOrder(ctx, req)
+	require.NoError(t, err)
+
+	t.Run("retry returns the original order", func(t *testing.T) {
+		retry, err := server.CreateOrder(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, first.Order.Id, retry.Order.Id)
+
+		// Stock is only reserved, and the order counted, once
+		mockMenuClient.AssertNumberOfCalls(t, "ReserveStock", 1)
+		assert.Equal(t, created+1, testutil.ToFloat64(ordersCreated.WithLabelValues("USD")))
+
+		var count int64
+		require.NoError(t, db.Model(&models.Order{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("same key with a different request is rejected", func(t *testing.T) {
+		_, err := server.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+			UserId:         1,
+			Items:          []*orderv1.OrderItemRequest{{MenuItemId: 1, Quantity: 2}},
+			IdempotencyKey: "retry-me",
+		})
+
+		require.Error(t, err)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+	})
+
+	t.Run("same key from another user creates a new order", func(t *testing.T) {
+		mockUserClient.On("GetUser", mock.Anything, &userv1.GetUserRequest{Id: 2}).
+			Return(&userv1.GetUserResponse{User: &userv1.User{Id: 2}}, nil)
+
+		other, err := server.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+			UserId:         2,
+			Items:          []*orderv1.OrderItemRequest{{MenuItemId: 1, Quantity: 1}},
+			IdempotencyKey: "retry-me",
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Order.Id, other.Order.Id)
+	})
+
+	t.Run("expired key creates a new order", func(t *testing.T) {
+		require.NoError(t, db.Model(&models.IdempotencyKey{}).
+			Where("user_id = ?", 1).
+			Update("created_at", time.Now().Add(-2*DefaultIdempotencyKeyRetention)).Error)
+
+		fresh, err := server.CreateOrder(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Order.Id, fresh.Order.Id)
+	})
+}
+
+func TestPurgeExpiredIdempotencyKeys(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db), IdempotencyKeyRetention: time.Hour}
+
+	keys := []models.IdempotencyKey{
+		{UserID: 1, Key: "fresh", OrderID: 1, CreatedAt: time.Now()},
+		{UserID: 1, Key: "stale", OrderID: 2, CreatedAt: time.Now().Add(-2 * time.Hour)},
+	}
+	require.NoError(t, db.Create(&keys).Error)
+
+	purged, err := server.PurgeExpiredIdempotencyKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var remaining []models.IdempotencyKey
+	require.NoError(t, db.Find(&remaining).Error)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "fresh", remaining[0].Key)
+}
+
+// fakeWatchStream captures the messages WatchOrder sends
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *orderv1.WatchOrderResponse
+}
+
+func (f *fakeWatchStream) Context() context.Context { return f.ctx }
+
+func (f *fakeWatchStream) Send(resp *orderv1.WatchOrderResponse) error {
+	f.sent <- resp
+	return nil
+}
+
+// receive waits for the next message sent on the stream
+func (f *fakeWatchStream) receive(t *testing.T) *orderv1.WatchOrderResponse {
+	t.Helper()
+	select {
+	case resp := <-f.sent:
+		return resp
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for order update")
+		return nil
+	}
+}
+
+func TestWatchOrder(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db)}
+
+	testOrder := models.Order{
+		UserID:        1,
+		Status:        models.StatusPending,
+		StatusHistory: []models.OrderStatusTransition{{ToStatus: models.StatusPending}},
+	}
+	require.NoError(t, db.Create(&testOrder).Error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeWatchStream{ctx: ctx, sent: make(chan *orderv1.WatchOrderResponse, 10)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchOrder(&orderv1.WatchOrderRequest{Id: uint32(testOrder.ID)}, stream)
+	}()
+
+	// The current order is sent first
+	initial := stream.receive(t)
+	assert.Equal(t, models.StatusPending, initial.Order.Status)
+	assert.NotZero(t, initial.EventId)
+
+	// Each status change is pushed with a later event id
+	_, err := server.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{
+		Id:     uint32(testOrder.ID),
+		Status: models.StatusConfirmed,
+	})
+	require.NoError(t, err)
+
+	confirmed := stream.receive(t)
+	assert.Equal(t, models.StatusConfirmed, confirmed.Order.Status)
+	assert.Greater(t, confirmed.EventId, initial.EventId)
+
+	// Reaching a terminal status ends the stream
+	_, err = server.CancelOrder(ctx, &orderv1.CancelOrderRequest{Id: uint32(testOrder.ID)})
+	require.NoError(t, err)
+
+	cancelled := stream.receive(t)
+	assert.Equal(t, models.StatusCancelled, cancelled.Order.Status)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("WatchOrder did not finish after a terminal status")
+	}
+
+	t.Run("resume after the last event sends nothing new", func(t *testing.T) {
+		resumed := &fakeWatchStream{ctx: context.Background(), sent: make(chan *orderv1.WatchOrderResponse, 10)}
+		err := server.WatchOrder(&orderv1.WatchOrderRequest{
+			Id:           uint32(testOrder.ID),
+			AfterEventId: cancelled.EventId,
+		}, resumed)
+
+		require.NoError(t, err)
+		assert.Empty(t, resumed.sent)
+	})
+
+	t.Run("resume from an earlier event sends the current order", func(t *testing.T) {
+		resumed := &fakeWatchStream{ctx: context.Background(), sent: make(chan *orderv1.WatchOrderResponse, 10)}
+		err := server.WatchOrder(&orderv1.WatchOrderRequest{
+			Id:           uint32(testOrder.ID),
+			AfterEventId: initial.EventId,
+		}, resumed)
+
+		require.NoError(t, err)
+		require.Len(t, resumed.sent, 1)
+		latest := <-resumed.sent
+		assert.Equal(t, cancelled.EventId, latest.EventId)
+		assert.Equal(t, models.StatusCancelled, latest.Order.Status)
+	})
+}
+
+func TestWatchOrder_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	server := &OrderServer{Orders: repository.NewGormOrderRepository(db)}
+	stream := &fakeWatchStream{ctx: context.Background(), sent: make(chan *orderv1.WatchOrderResponse, 1)}
+
+	err := server.WatchOrder(&orderv1.WatchOrderRequest{Id: 9999}, stream)
+
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+
+	t.Run("database failures are not reported as a missing order", func(t *testing.T) {
+		for err, want := range map[error]codes.Code{
+			errors.New("connection refused"): codes.Internal,
+			context.Canceled:                 codes.Canceled,
+		} {
+			failing := &OrderServer{Orders: failingOrders{OrderRepository: server.Orders, err: err}}
+			gotErr := failing.WatchOrder(&orderv1.WatchOrderRequest{Id: 1}, stream)
+			assert.Equal(t, want, status.Code(gotErr), err.Error())
+		}
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+
+	studentOrder := models.Order{UserID: 2, Status: models.StatusPending}
+	require.NoError(t, db.Create(&studentOrder).Error)
+	otherOrder := models.Order{UserID: 3, Status: models.StatusPending}
+	require.NoError(t, db.Create(&otherOrder).Error)
+
+	interceptor := authz.UnaryServerInterceptor(Policy(repository.NewGormOrderRepository(db)))
+	call := func(ctx context.Context, method string, req any) codes.Code {
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return status.Code(err)
+	}
+
+	owner := auth.NewContext(context.Background(), auth.Identity{UserID: 1, Role: auth.RoleCafeOwner})
+	student := auth.NewContext(context.Background(), auth.Identity{UserID: 2, Role: auth.RoleStudent})
+
+	t.Run("students order for themselves only", func(t *testing.T) {
+		assert.Equal(t, codes.OK, call(student, orderv1.OrderService_CreateOrder_FullMethodName, &orderv1.CreateOrderRequest{UserId: 2}))
+		assert.Equal(t, codes.PermissionDenied, call(student, orderv1.OrderService_CreateOrder_FullMethodName, &orderv1.CreateOrderRequest{UserId: 3}))
+		assert.Equal(t, codes.OK, call(owner, orderv1.OrderService_CreateOrder_FullMethodName, &orderv1.CreateOrderRequest{UserId: 3}))
+	})
+
+	t.Run("students read and cancel their own orders", func(t *testing.T) {
+		own := &orderv1.GetOrderRequest{Id: uint32(studentOrder.ID)}
+		other := &orderv1.GetOrderRequest{Id: uint32(otherOrder.ID)}
+		assert.Equal(t, codes.OK, call(student, orderv1.OrderService_GetOrder_FullMethodName, own))
+		assert.Equal(t, codes.PermissionDenied, call(student, orderv1.OrderService_GetOrder_FullMethodName, other))
+		assert.Equal(t, codes.OK, call(owner, orderv1.OrderService_GetOrder_FullMethodName, other))
+		assert.Equal(t, codes.NotFound, call(student, orderv1.OrderService_GetOrder_FullMethodName, &orderv1.GetOrderRequest{Id: 999}))
+
+		assert.Equal(t, codes.OK, call(student, orderv1.OrderService_CancelOrder_FullMethodName, &orderv1.CancelOrderRequest{Id: uint32(studentOrder.ID)}))
+		assert.Equal(t, codes.PermissionDenied, call(student, orderv1.OrderService_CancelOrder_FullMethodName, &orderv1.CancelOrderRequest{Id: uint32(otherOrder.ID)}))
+	})
+
+	t.Run("student order lists are scoped to the caller", func(t *testing.T) {
+		req := &orderv1.GetOrdersRequest{}
+		assert.Equal(t, codes.OK, call(student, orderv1.OrderService_GetOrders_FullMethodName, req))
+		assert.Equal(t, uint32(2), req.GetUserId())
+
+		other := uint32(3)
+		assert.Equal(t, codes.PermissionDenied, call(student, orderv1.OrderService_GetOrders_FullMethodName, &orderv1.GetOrdersRequest{UserId: &other}))
+
+		req = &orderv1.GetOrdersRequest{}
+		assert.Equal(t, codes.OK, call(owner, orderv1.OrderService_GetOrders_FullMethodName, req))
+		assert.Nil(t, req.UserId, "owners see every order")
+	})
+
+	t.Run("only cafe owners advance and delete orders", func(t *testing.T) {
+		update := &orderv1.UpdateOrderStatusRequest{Id: uint32(studentOrder.ID), Status: "confirmed"}
+		assert.Equal(t, codes.PermissionDenied, call(student, orderv1.OrderService_UpdateOrderStatus_FullMethodName, update))
+		assert.Equal(t, codes.OK, call(owner, orderv1.OrderService_UpdateOrderStatus_FullMethodName, update))
+
+		del := &orderv1.DeleteOrderRequest{Id: uint32(studentOrder.ID)}
+		assert.Equal(t, codes.PermissionDenied, call(student, orderv1.OrderService_DeleteOrder_FullMethodName, del))
+		assert.Equal(t, codes.Unauthenticated, call(context.Background(), orderv1.OrderService_DeleteOrder_FullMethodName, del))
+	})
+}
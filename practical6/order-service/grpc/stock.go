@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
+	"order-service/models"
+)
+
+// stockReservations lists the menu stock held by an order's items
+func stockReservations(order *models.Order) []*menuv1.StockReservation {
+	reservations := make([]*menuv1.StockReservation, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		reservations[i] = &menuv1.StockReservation{
+			MenuItemId: uint32(item.MenuItemID),
+			Quantity:   int32(item.Quantity),
+		}
+	}
+	return reservations
+}
+
+// releaseStock returns the stock held by an order to the menu. Failures are
+// logged rather than returned because the order change they follow has
+// already been committed.
+func (s *OrderServer) releaseStock(ctx context.Context, order *models.Order) {
+	if len(order.OrderItems) == 0 {
+		return
+	}
+
+	if _, err := s.MenuClient.ReleaseStock(ctx, &menuv1.ReleaseStockRequest{
+		Items: stockReservations(order),
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to release stock", "order_id", order.ID, "error", err)
+	}
+}
@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"order-service/models"
+	"student-cafe-shared/dberr"
+)
+
+// DefaultWatchPollInterval is how often WatchOrder re-checks the database
+// when OrderServer.WatchPollInterval is not set
+const DefaultWatchPollInterval = 5 * time.Second
+
+// orderWatchers fans out status-change notifications to WatchOrder streams
+// in this process. The zero value is ready to use.
+type orderWatchers struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan struct{}]struct{}
+}
+
+// subscribe registers interest in an order and returns a channel that
+// receives a value after each change, plus a function to unsubscribe
+func (w *orderWatchers) subscribe(orderID uint) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	w.mu.Lock()
+	if w.subs == nil {
+		w.subs = make(map[uint]map[chan struct{}]struct{})
+	}
+	if w.subs[orderID] == nil {
+		w.subs[orderID] = make(map[chan struct{}]struct{})
+	}
+	w.subs[orderID][ch] = struct{}{}
+	w.mu.Unlock()
+
+	return ch, func() {
+		w.mu.Lock()
+		delete(w.subs[orderID], ch)
+		if len(w.subs[orderID]) == 0 {
+			delete(w.subs, orderID)
+		}
+		w.mu.Unlock()
+	}
+}
+
+// notify wakes every subscriber of an order without blocking
+func (w *orderWatchers) notify(orderID uint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs[orderID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A wake-up is already pending
+		}
+	}
+}
+
+// WatchOrder streams the order every time its status changes. A new stream
+// starts with the current order; a stream resumed with after_event_id only
+// receives the order if it changed since that event. The stream ends once the
+// order reaches a terminal status and the client has seen it.
+func (s *OrderServer) WatchOrder(req *orderv1.WatchOrderRequest, stream grpc.ServerStreamingServer[orderv1.WatchOrderResponse]) error {
+	// Subscribe before the first read so no change can slip in between
+	updates, unsubscribe := s.watchers.subscribe(uint(req.Id))
+	defer unsubscribe()
+
+	pollInterval := s.WatchPollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultWatchPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastEventID := req.AfterEventId
+	sent := false
+	for {
+		order, err := s.Orders.Get(stream.Context(), uint(req.Id))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return status.Errorf(codes.NotFound, "order not found")
+			}
+			return dberr.Error(err, "failed to get order")
+		}
+
+		eventID := latestEventID(order)
+		if eventID > lastEventID || (!sent && req.AfterEventId == 0) {
+			if err := stream.Send(&orderv1.WatchOrderResponse{
+				EventId: eventID,
+				Order:   modelToProto(order),
+			}); err != nil {
+				return err
+			}
+			lastEventID = eventID
+			sent = true
+		}
+
+		if models.IsTerminalStatus(order.Status) {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-updates:
+		case <-ticker.C:
+		}
+	}
+}
+
+// latestEventID returns the id of the order's most recent status transition,
+// which doubles as the WatchOrder event id
+func latestEventID(order *models.Order) uint64 {
+	var latest uint
+	for _, transition := range order.StatusHistory {
+		if transition.ID > latest {
+			latest = transition.ID
+		}
+	}
+	return uint64(latest)
+}
@@ -1,26 +1,87 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 	"order-service/database"
 	grpcserver "order-service/grpc"
+	"order-service/repository"
 
 	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
 	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+	"student-cafe-shared/health"
+	"student-cafe-shared/logging"
+	"student-cafe-shared/metrics"
+	"student-cafe-shared/migrate"
+	"student-cafe-shared/mtls"
+	"student-cafe-shared/resilience"
+	"student-cafe-shared/shutdown"
+	"student-cafe-shared/tracing"
+	"student-cafe-shared/validation"
 )
 
 func main() {
+	// Log JSON lines to stdout
+	if err := logging.Setup("order-service"); err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
 	// Connect to dedicated order database
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
 		dsn = "host=localhost user=postgres password=postgres dbname=order_db port=5432 sslmode=disable"
 	}
 
-	if err := database.Connect(dsn); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	// "order-service migrate <command>" manages the schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		cmd := migrate.Command{
+			Migrations: database.Migrations(),
+			Dir:        database.MigrationsDir,
+			Open:       func() (*gorm.DB, error) { return database.Open(dsn) },
+			Out:        os.Stdout,
+		}
+		if err := cmd.Run(context.Background(), os.Args[2:]); err != nil {
+			logging.Fatal("Migration failed", "error", err)
+		}
+		return
+	}
+
+	// SIGTERM (or Ctrl-C) starts a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// How long in-flight RPCs may run after shutdown starts (e.g. "30s")
+	drainTimeout, err := shutdown.Timeout()
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
+	// Export spans to the backend named by OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(ctx, "order-service")
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", "error", err)
+	}
+
+	db, err := database.Connect(dsn)
+	if err != nil {
+		logging.Fatal("Failed to connect to database", "error", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		logging.Fatal("Failed to get database pool", "error", err)
+	}
+	if err := metrics.DBStats(sqlDB, "order_db"); err != nil {
+		logging.Fatal("Failed to register database metrics", "error", err)
 	}
 
 	// Get gRPC port from environment
@@ -29,10 +90,16 @@ func main() {
 		grpcPort = "9093"
 	}
 
+	// Get metrics port from environment
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9103"
+	}
+
 	// Start listening on TCP port
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
 	if err != nil {
-		log.Fatalf("Failed to listen on gRPC port %s: %v", grpcPort, err)
+		logging.Fatal("Failed to listen on gRPC port", "port", grpcPort, "error", err)
 	}
 
 	// Get service addresses for gRPC clients (order service calls user and menu)
@@ -46,18 +113,113 @@ func main() {
 		menuServiceAddr = "menu-service:9092"
 	}
 
+	// Deadline of every call to the user and menu services (e.g. "5s")
+	callTimeout, err := resilience.CallTimeout()
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
 	// Create order gRPC server with clients to other services
-	orderServer, err := grpcserver.NewOrderServer(userServiceAddr, menuServiceAddr)
+	orders := repository.NewGormOrderRepository(db)
+	orderServer, err := grpcserver.NewOrderServer(orders, userServiceAddr, menuServiceAddr, callTimeout)
+	if err != nil {
+		logging.Fatal("Failed to create gRPC order server", "error", err)
+	}
+
+	// Tax rate in basis points applied to order subtotals (e.g. 1500 = 15%)
+	if taxRate := os.Getenv("ORDER_TAX_RATE_BPS"); taxRate != "" {
+		bps, err := strconv.ParseInt(taxRate, 10, 64)
+		if err != nil {
+			logging.Fatal("Invalid ORDER_TAX_RATE_BPS", "value", taxRate, "error", err)
+		}
+		orderServer.TaxRateBasisPoints = bps
+	}
+
+	// How long CreateOrder idempotency keys are honoured (e.g. "24h")
+	if retention := os.Getenv("IDEMPOTENCY_KEY_RETENTION"); retention != "" {
+		d, err := time.ParseDuration(retention)
+		if err != nil {
+			logging.Fatal("Invalid IDEMPOTENCY_KEY_RETENTION", "value", retention, "error", err)
+		}
+		orderServer.IdempotencyKeyRetention = d
+	}
+
+	// Garbage-collect expired idempotency keys in the background
+	go orderServer.RunIdempotencyKeyGC(ctx, time.Hour)
+
+	// Serve TLS, or mutual TLS when a CA is set, with the files named by
+	// TLS_CERT_FILE, TLS_KEY_FILE and TLS_CA_FILE
+	creds, err := mtls.ServerOption()
 	if err != nil {
-		log.Fatalf("Failed to create gRPC order server: %v", err)
+		logging.Fatal("Invalid TLS configuration", "error", err)
 	}
 
-	// Create and register gRPC server
-	s := grpc.NewServer()
+	// Create and register gRPC server; every RPC is traced and measured,
+	// and the interceptors read the caller identity forwarded by the
+	// api-gateway, validate requests and enforce the order policy
+	policy := grpcserver.Policy(orders)
+	s := grpc.NewServer(
+		creds,
+		tracing.ServerOption(),
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(),
+			validation.UnaryServerInterceptor(),
+			authz.UnaryServerInterceptor(policy),
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(),
+			metrics.StreamServerInterceptor(),
+			auth.StreamServerInterceptor(),
+			validation.StreamServerInterceptor(),
+			authz.StreamServerInterceptor(policy),
+		),
+	)
 	orderv1.RegisterOrderServiceServer(s, orderServer)
 
-	log.Printf("Order service (gRPC only) starting on :%s", grpcPort)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("gRPC server failed: %v", err)
+	// Publish grpc.health.v1 status; the service is only SERVING while its
+	// dependencies are reachable
+	checker := health.NewChecker(orderv1.OrderService_ServiceDesc.ServiceName)
+	checker.Add("database", health.Database(sqlDB))
+	orderServer.AddHealthChecks(checker)
+	checker.Register(s)
+	go checker.Run(ctx)
+
+	// Serve Prometheus metrics on their own port until shutdown
+	go func() {
+		if err := metrics.Serve(ctx, metrics.NewServer(":"+metricsPort)); err != nil {
+			logging.Fatal("Metrics server failed", "error", err)
+		}
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.Serve(lis) }()
+	slog.Info("Order service (gRPC only) starting", "grpc_port", grpcPort, "metrics_port", metricsPort)
+
+	select {
+	case err := <-serveErr:
+		logging.Fatal("gRPC server failed", "error", err)
+	case <-ctx.Done():
+		stop() // a second signal kills the process immediately
+	}
+
+	// Stop advertising the service, then let in-flight RPCs finish
+	slog.Info("Shutting down, draining in-flight RPCs", "timeout", drainTimeout.String())
+	checker.Shutdown()
+	if !shutdown.GracefulStop(s, drainTimeout) {
+		slog.Warn("Drain timeout exceeded, cancelled the remaining RPCs")
+	}
+	if err := orderServer.Close(); err != nil {
+		slog.Error("Failed to close service connections", "error", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		slog.Error("Failed to close database", "error", err)
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
+	slog.Info("Order service stopped")
 }
@@ -1,18 +1,106 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Order lifecycle statuses
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusPreparing = "preparing"
+	StatusReady     = "ready"
+	StatusCollected = "collected"
+	StatusCancelled = "cancelled"
+)
+
+// DefaultCurrency is assumed for orders placed before prices carried a currency
+const DefaultCurrency = "USD"
+
+// orderTransitions lists the statuses each status may move to
+var orderTransitions = map[string][]string{
+	StatusPending:   {StatusConfirmed, StatusCancelled},
+	StatusConfirmed: {StatusPreparing, StatusCancelled},
+	StatusPreparing: {StatusReady},
+	StatusReady:     {StatusCollected},
+	StatusCollected: {},
+	StatusCancelled: {},
+}
+
+// IsValidStatus reports whether status is a known order status
+func IsValidStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// IsTerminalStatus reports whether an order in status can no longer change
+func IsTerminalStatus(status string) bool {
+	return IsValidStatus(status) && len(orderTransitions[status]) == 0
+}
+
+// CanTransition reports whether an order may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
 
 type Order struct {
 	gorm.Model
-	UserID     uint        `json:"user_id"`
-	Status     string      `json:"status"` // "pending", "completed"
-	OrderItems []OrderItem `json:"order_items" gorm:"foreignKey:OrderID"`
+	UserID        uint                    `json:"user_id"`
+	Status        string                  `json:"status"` // see Status* constants
+	Currency      string                  `json:"currency" gorm:"size:3"`
+	SubtotalMinor int64                   `json:"subtotal_minor"`
+	TaxMinor      int64                   `json:"tax_minor"`
+	TotalMinor    int64                   `json:"total_minor"`
+	OrderItems    []OrderItem             `json:"order_items" gorm:"foreignKey:OrderID"`
+	StatusHistory []OrderStatusTransition `json:"status_history" gorm:"foreignKey:OrderID"`
+}
+
+// ComputeTotals sets the subtotal, tax and total from the order items.
+// Tax is charged in basis points (1/100 of a percent) and rounded half up.
+func (o *Order) ComputeTotals(taxRateBasisPoints int64) {
+	var subtotal int64
+	for _, item := range o.OrderItems {
+		subtotal += item.PriceMinor * int64(item.Quantity)
+	}
+
+	o.SubtotalMinor = subtotal
+	o.TaxMinor = (subtotal*taxRateBasisPoints + 5000) / 10000
+	o.TotalMinor = o.SubtotalMinor + o.TaxMinor
 }
 
 type OrderItem struct {
 	gorm.Model
-	OrderID    uint    `json:"order_id"`
-	MenuItemID uint    `json:"menu_item_id"`
-	Quantity   int     `json:"quantity"`
-	Price      float64 `json:"price"` // Snapshot price at order time
+	OrderID    uint   `json:"order_id"`
+	MenuItemID uint   `json:"menu_item_id"`
+	Quantity   int    `json:"quantity"`
+	PriceMinor int64  `json:"price_minor"` // Snapshot unit price at order time, in minor units
+	Currency   string `json:"currency" gorm:"size:3"`
+}
+
+// OrderStatusTransition records when an order entered a status.
+// CreatedAt is the transition timestamp.
+type OrderStatusTransition struct {
+	gorm.Model
+	OrderID    uint   `json:"order_id" gorm:"index"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+}
+
+// IdempotencyKey remembers which order a client-supplied idempotency key
+// created, so retried CreateOrder requests return the original order.
+// Keys are scoped per user and expire after a retention window.
+type IdempotencyKey struct {
+	UserID      uint      `gorm:"primaryKey;autoIncrement:false"`
+	Key         string    `gorm:"primaryKey;column:idempotency_key;size:255"`
+	RequestHash string    `gorm:"size:64"`
+	OrderID     uint      `gorm:"index"`
+	CreatedAt   time.Time `gorm:"index"`
 }
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"order-service/models"
+	"student-cafe-shared/pagination"
+)
+
+// GormOrderRepository stores orders in a SQL database through GORM
+type GormOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOrderRepository returns a repository backed by db, which must
+// already be migrated
+func NewGormOrderRepository(db *gorm.DB) *GormOrderRepository {
+	return &GormOrderRepository{db: db}
+}
+
+// preloadOrder loads an order's items and its status history in transition order
+func preloadOrder(db *gorm.DB) *gorm.DB {
+	return db.Preload("OrderItems").Preload("StatusHistory", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	})
+}
+
+// Create saves a new order and its idempotency key atomically
+func (r *GormOrderRepository) Create(ctx context.Context, order *models.Order, key *models.IdempotencyKey, keyExpiry time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if key == nil {
+			return nil
+		}
+
+		if err := tx.Where("user_id = ? AND idempotency_key = ? AND created_at <= ?", key.UserID, key.Key, keyExpiry).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		key.OrderID = order.ID
+		return tx.Create(key).Error
+	})
+}
+
+// Get returns the order with the given ID
+func (r *GormOrderRepository) Get(ctx context.Context, id uint) (*models.Order, error) {
+	var order models.Order
+	if err := preloadOrder(r.db.WithContext(ctx)).First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// List returns a page of orders matching filter
+func (r *GormOrderRepository) List(ctx context.Context, filter OrderFilter, page pagination.Request) ([]models.Order, string, error) {
+	query := preloadOrder(r.db.WithContext(ctx)).Model(&models.Order{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	return pagination.Find(query, page, sortKey(page.Sort))
+}
+
+// UpdateStatus moves an order between statuses and records the transition
+func (r *GormOrderRepository) UpdateStatus(ctx context.Context, id uint, from, to, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Guard on the current status so concurrent updates cannot both succeed
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status = ?", id, from).
+			Update("status", to)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+
+		return tx.Create(&models.OrderStatusTransition{
+			OrderID:    id,
+			FromStatus: from,
+			ToStatus:   to,
+			Reason:     reason,
+		}).Error
+	})
+}
+
+// Delete soft-deletes the order with the given ID
+func (r *GormOrderRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Order{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindIdempotencyKey returns a user's idempotency key created after createdAfter
+func (r *GormOrderRepository) FindIdempotencyKey(ctx context.Context, userID uint, key string, createdAfter time.Time) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ? AND created_at > ?", userID, key, createdAfter).
+		First(&idempotencyKey).Error; err != nil {
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
+
+// DeleteIdempotencyKeys deletes the keys created at or before createdBefore
+func (r *GormOrderRepository) DeleteIdempotencyKeys(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at <= ?", createdBefore).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"order-service/models"
+	"student-cafe-shared/pagination"
+)
+
+// idempotencyKeyID is the primary key of an idempotency key
+type idempotencyKeyID struct {
+	userID uint
+	key    string
+}
+
+// MemoryOrderRepository stores orders in memory. It is safe for concurrent
+// use and the zero value is not usable; create one with
+// NewMemoryOrderRepository.
+type MemoryOrderRepository struct {
+	mu               sync.RWMutex
+	orders           map[uint]models.Order
+	idempotencyKeys  map[idempotencyKeyID]models.IdempotencyKey
+	nextOrderID      uint
+	nextItemID       uint
+	nextTransitionID uint
+}
+
+// NewMemoryOrderRepository returns an empty in-memory repository
+func NewMemoryOrderRepository() *MemoryOrderRepository {
+	return &MemoryOrderRepository{
+		orders:          make(map[uint]models.Order),
+		idempotencyKeys: make(map[idempotencyKeyID]models.IdempotencyKey),
+	}
+}
+
+// cloneOrder copies an order so callers never share its items or history
+// with the store
+func cloneOrder(order models.Order) models.Order {
+	order.OrderItems = slices.Clone(order.OrderItems)
+	order.StatusHistory = slices.Clone(order.StatusHistory)
+	return order
+}
+
+// order returns a stored order that has not been deleted
+func (r *MemoryOrderRepository) order(id uint) (models.Order, bool) {
+	order, ok := r.orders[id]
+	if !ok || order.DeletedAt.Valid {
+		return models.Order{}, false
+	}
+	return order, true
+}
+
+// Create saves a new order and its idempotency key atomically
+func (r *MemoryOrderRepository) Create(ctx context.Context, order *models.Order, key *models.IdempotencyKey, keyExpiry time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key != nil {
+		existing, ok := r.idempotencyKeys[idempotencyKeyID{key.UserID, key.Key}]
+		if ok && existing.CreatedAt.After(keyExpiry) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.nextOrderID++
+	order.ID = r.nextOrderID
+	setTimestamps(&order.Model)
+	for i := range order.OrderItems {
+		r.nextItemID++
+		item := &order.OrderItems[i]
+		item.ID, item.OrderID = r.nextItemID, order.ID
+		setTimestamps(&item.Model)
+	}
+	for i := range order.StatusHistory {
+		r.nextTransitionID++
+		transition := &order.StatusHistory[i]
+		transition.ID, transition.OrderID = r.nextTransitionID, order.ID
+		setTimestamps(&transition.Model)
+	}
+	r.orders[order.ID] = cloneOrder(*order)
+
+	if key != nil {
+		key.OrderID = order.ID
+		if key.CreatedAt.IsZero() {
+			key.CreatedAt = time.Now()
+		}
+		r.idempotencyKeys[idempotencyKeyID{key.UserID, key.Key}] = *key
+	}
+	return nil
+}
+
+// Get returns the order with the given ID
+func (r *MemoryOrderRepository) Get(ctx context.Context, id uint) (*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.order(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	order = cloneOrder(order)
+	return &order, nil
+}
+
+// List returns a page of orders matching filter
+func (r *MemoryOrderRepository) List(ctx context.Context, filter OrderFilter, page pagination.Request) ([]models.Order, string, error) {
+	r.mu.RLock()
+	orders := make([]models.Order, 0, len(r.orders))
+	for _, order := range r.orders {
+		switch {
+		case order.DeletedAt.Valid,
+			filter.UserID != nil && order.UserID != *filter.UserID,
+			filter.Status != "" && order.Status != filter.Status,
+			!filter.CreatedAfter.IsZero() && order.CreatedAt.Before(filter.CreatedAfter),
+			!filter.CreatedBefore.IsZero() && !order.CreatedAt.Before(filter.CreatedBefore):
+			continue
+		}
+		orders = append(orders, cloneOrder(order))
+	}
+	r.mu.RUnlock()
+
+	return pagination.Slice(orders, page, sortKey(page.Sort))
+}
+
+// UpdateStatus moves an order between statuses and records the transition
+func (r *MemoryOrderRepository) UpdateStatus(ctx context.Context, id uint, from, to, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.order(id)
+	if !ok || order.Status != from {
+		return ErrStatusChanged
+	}
+
+	now := time.Now()
+	r.nextTransitionID++
+	order = cloneOrder(order)
+	order.Status = to
+	order.UpdatedAt = now
+	order.StatusHistory = append(order.StatusHistory, models.OrderStatusTransition{
+		Model:      gorm.Model{ID: r.nextTransitionID, CreatedAt: now, UpdatedAt: now},
+		OrderID:    id,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+	})
+	r.orders[id] = order
+	return nil
+}
+
+// Delete soft-deletes the order with the given ID
+func (r *MemoryOrderRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.order(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	order.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.orders[id] = order
+	return nil
+}
+
+// FindIdempotencyKey returns a user's idempotency key created after createdAfter
+func (r *MemoryOrderRepository) FindIdempotencyKey(ctx context.Context, userID uint, key string, createdAfter time.Time) (*models.IdempotencyKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	idempotencyKey, ok := r.idempotencyKeys[idempotencyKeyID{userID, key}]
+	if !ok || !idempotencyKey.CreatedAt.After(createdAfter) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &idempotencyKey, nil
+}
+
+// DeleteIdempotencyKeys deletes the keys created at or before createdBefore
+func (r *MemoryOrderRepository) DeleteIdempotencyKeys(ctx context.Context, createdBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, key := range r.idempotencyKeys {
+		if !key.CreatedAt.After(createdBefore) {
+			delete(r.idempotencyKeys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// setTimestamps fills in unset creation and update times, as GORM does
+func setTimestamps(model *gorm.Model) {
+	now := time.Now()
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = now
+	}
+}
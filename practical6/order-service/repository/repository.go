@@ -0,0 +1,82 @@
+// Package repository stores the order service's orders and idempotency keys.
+//
+// OrderServer depends on the OrderRepository interface rather than on a
+// database handle, so each server owns its storage: GormOrderRepository
+// backs the service in production and MemoryOrderRepository keeps orders in
+// memory for tests. Both report missing rows with gorm.ErrRecordNotFound and
+// reused idempotency keys with a unique violation, so callers can classify
+// errors with dberr.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order-service/models"
+	"student-cafe-shared/pagination"
+)
+
+// ErrStatusChanged is returned by UpdateStatus when the order is no longer
+// in the status it was expected to move from
+var ErrStatusChanged = errors.New("order status changed concurrently")
+
+// OrderRepository stores orders, their items and status history, and the
+// idempotency keys they were created with
+type OrderRepository interface {
+	// Create saves a new order with its items and status history, setting
+	// their IDs and timestamps. A non-nil key is saved in the same
+	// transaction and pointed at the order; it replaces a key with the same
+	// value created at or before keyExpiry, and fails with a unique
+	// violation if a newer one exists.
+	Create(ctx context.Context, order *models.Order, key *models.IdempotencyKey, keyExpiry time.Time) error
+	// Get returns the order with the given ID, its items and its status
+	// history in transition order
+	Get(ctx context.Context, id uint) (*models.Order, error)
+	// List returns a page of orders matching filter and the next page token
+	List(ctx context.Context, filter OrderFilter, page pagination.Request) ([]models.Order, string, error)
+	// UpdateStatus moves an order from one status to another and records
+	// the transition. It fails with ErrStatusChanged if the order is not in
+	// status from.
+	UpdateStatus(ctx context.Context, id uint, from, to, reason string) error
+	// Delete soft-deletes the order with the given ID
+	Delete(ctx context.Context, id uint) error
+
+	// FindIdempotencyKey returns a user's idempotency key if it was created
+	// after createdAfter
+	FindIdempotencyKey(ctx context.Context, userID uint, key string, createdAfter time.Time) (*models.IdempotencyKey, error)
+	// DeleteIdempotencyKeys deletes the keys created at or before
+	// createdBefore and returns how many were removed
+	DeleteIdempotencyKeys(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+// OrderFilter narrows the orders returned by List
+type OrderFilter struct {
+	UserID *uint
+	Status string
+	// CreatedAfter and CreatedBefore bound the creation time, inclusive and
+	// exclusive respectively; zero values leave it unbounded
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// SortFields maps the order_by fields orders can be listed by to columns
+var SortFields = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+	"total":      "total_minor",
+}
+
+// sortKey returns the pagination key for a sort order
+func sortKey(sort pagination.Sort) pagination.Key[models.Order] {
+	return func(order *models.Order) (any, uint) {
+		switch sort.Field {
+		case "created_at":
+			return order.CreatedAt, order.ID
+		case "total":
+			return order.TotalMinor, order.ID
+		default:
+			return order.ID, order.ID
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"order-service/models"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/pagination"
+)
+
+// implementations returns a fresh instance of every OrderRepository
+func implementations(t *testing.T) map[string]OrderRepository {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Order{}, &models.OrderItem{}, &models.OrderStatusTransition{}, &models.IdempotencyKey{}))
+
+	return map[string]OrderRepository{
+		"gorm":   NewGormOrderRepository(db),
+		"memory": NewMemoryOrderRepository(),
+	}
+}
+
+// newOrder returns a pending order for userID with one item
+func newOrder(userID uint, totalMinor int64) *models.Order {
+	return &models.Order{
+		UserID:     userID,
+		Status:     models.StatusPending,
+		Currency:   "USD",
+		TotalMinor: totalMinor,
+		OrderItems: []models.OrderItem{
+			{MenuItemID: 1, Quantity: 1, PriceMinor: totalMinor, Currency: "USD"},
+		},
+		StatusHistory: []models.OrderStatusTransition{
+			{ToStatus: models.StatusPending},
+		},
+	}
+}
+
+func TestOrderRepository(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repo := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			expiry := time.Now().Add(-time.Hour)
+
+			first := newOrder(1, 500)
+			key := &models.IdempotencyKey{UserID: 1, Key: "retry-1", RequestHash: "abc"}
+			require.NoError(t, repo.Create(ctx, first, key, expiry))
+			require.NotZero(t, first.ID)
+			assert.Equal(t, first.ID, key.OrderID)
+			assert.Equal(t, first.ID, first.OrderItems[0].OrderID)
+
+			second := newOrder(2, 300)
+			require.NoError(t, repo.Create(ctx, second, nil, expiry))
+
+			t.Run("get loads items and history", func(t *testing.T) {
+				got, err := repo.Get(ctx, first.ID)
+				require.NoError(t, err)
+				assert.Equal(t, uint(1), got.UserID)
+				require.Len(t, got.OrderItems, 1)
+				assert.Equal(t, int64(500), got.OrderItems[0].PriceMinor)
+				require.Len(t, got.StatusHistory, 1)
+				assert.Equal(t, models.StatusPending, got.StatusHistory[0].ToStatus)
+
+				_, err = repo.Get(ctx, 9999)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("idempotency keys", func(t *testing.T) {
+				got, err := repo.FindIdempotencyKey(ctx, 1, "retry-1", expiry)
+				require.NoError(t, err)
+				assert.Equal(t, first.ID, got.OrderID)
+				assert.Equal(t, "abc", got.RequestHash)
+
+				_, err = repo.FindIdempotencyKey(ctx, 2, "retry-1", expiry)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				_, err = repo.FindIdempotencyKey(ctx, 1, "retry-1", time.Now().Add(time.Minute))
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "expired keys are not found")
+
+				// A live key cannot be reused, and the order is not saved
+				err = repo.Create(ctx, newOrder(1, 500), &models.IdempotencyKey{UserID: 1, Key: "retry-1"}, expiry)
+				assert.True(t, dberr.IsUniqueViolation(err), "got %v", err)
+				orders, _, err := repo.List(ctx, OrderFilter{}, pagination.Request{Sort: pagination.Sort{Field: "id", Column: "id"}})
+				require.NoError(t, err)
+				assert.Len(t, orders, 2)
+
+				// An expired key is replaced
+				replacement := newOrder(1, 500)
+				require.NoError(t, repo.Create(ctx, replacement, &models.IdempotencyKey{UserID: 1, Key: "retry-1"}, time.Now().Add(time.Minute)))
+				got, err = repo.FindIdempotencyKey(ctx, 1, "retry-1", expiry)
+				require.NoError(t, err)
+				assert.Equal(t, replacement.ID, got.OrderID)
+				require.NoError(t, repo.Delete(ctx, replacement.ID))
+
+				purged, err := repo.DeleteIdempotencyKeys(ctx, time.Now().Add(time.Minute))
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), purged)
+			})
+
+			t.Run("list", func(t *testing.T) {
+				byTotal := pagination.Request{PageSize: 1, Sort: pagination.Sort{Field: "total", Column: "total_minor"}}
+				orders, next, err := repo.List(ctx, OrderFilter{}, byTotal)
+				require.NoError(t, err)
+				require.Len(t, orders, 1)
+				assert.Equal(t, second.ID, orders[0].ID)
+				require.Len(t, orders[0].OrderItems, 1, "items are loaded")
+
+				byTotal.PageToken = next
+				orders, next, err = repo.List(ctx, OrderFilter{}, byTotal)
+				require.NoError(t, err)
+				require.Len(t, orders, 1)
+				assert.Equal(t, first.ID, orders[0].ID)
+				assert.Empty(t, next)
+
+				userID := uint(2)
+				all := pagination.Request{Sort: pagination.Sort{Field: "id", Column: "id"}}
+				orders, _, err = repo.List(ctx, OrderFilter{UserID: &userID}, all)
+				require.NoError(t, err)
+				require.Len(t, orders, 1)
+				assert.Equal(t, second.ID, orders[0].ID)
+
+				orders, _, err = repo.List(ctx, OrderFilter{CreatedBefore: time.Now().Add(-time.Minute)}, all)
+				require.NoError(t, err)
+				assert.Empty(t, orders)
+				orders, _, err = repo.List(ctx, OrderFilter{CreatedAfter: time.Now().Add(-time.Minute)}, all)
+				require.NoError(t, err)
+				assert.Len(t, orders, 2)
+			})
+
+			t.Run("update status", func(t *testing.T) {
+				require.NoError(t, repo.UpdateStatus(ctx, first.ID, models.StatusPending, models.StatusConfirmed, ""))
+				err := repo.UpdateStatus(ctx, first.ID, models.StatusPending, models.StatusCancelled, "too slow")
+				assert.ErrorIs(t, err, ErrStatusChanged)
+				require.NoError(t, repo.UpdateStatus(ctx, first.ID, models.StatusConfirmed, models.StatusCancelled, "changed my mind"))
+
+				got, err := repo.Get(ctx, first.ID)
+				require.NoError(t, err)
+				assert.Equal(t, models.StatusCancelled, got.Status)
+				require.Len(t, got.StatusHistory, 3)
+				assert.Equal(t, models.StatusConfirmed, got.StatusHistory[2].FromStatus)
+				assert.Equal(t, "changed my mind", got.StatusHistory[2].Reason)
+
+				cancelled := pagination.Request{Sort: pagination.Sort{Field: "id", Column: "id"}}
+				orders, _, err := repo.List(ctx, OrderFilter{Status: models.StatusCancelled}, cancelled)
+				require.NoError(t, err)
+				require.Len(t, orders, 1)
+				assert.Equal(t, first.ID, orders[0].ID)
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				require.NoError(t, repo.Delete(ctx, second.ID))
+				_, err := repo.Get(ctx, second.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, repo.Delete(ctx, second.ID), gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, repo.UpdateStatus(ctx, second.ID, models.StatusPending, models.StatusConfirmed, ""), ErrStatusChanged)
+			})
+		})
+	}
+}
@@ -7,8 +7,12 @@
 package menuv1
 
 import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	v1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,13 +27,19 @@ const (
 
 // MenuItem message definition
 type MenuItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt   string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Price       *v1.Money              `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	// 0 when the item is not in a category
+	CategoryId uint32 `protobuf:"varint,8,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// False while the item is temporarily off the menu
+	Available bool `protobuf:"varint,9,opt,name=available,proto3" json:"available,omitempty"`
+	// Units left; unset when stock is not tracked
+	Stock         *int32 `protobuf:"varint,10,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -85,21 +95,119 @@ func (x *MenuItem) GetDescription() string {
 	return ""
 }
 
-func (x *MenuItem) GetPrice() float64 {
+func (x *MenuItem) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *MenuItem) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *MenuItem) GetPrice() *v1.Money {
 	if x != nil {
 		return x.Price
 	}
+	return nil
+}
+
+func (x *MenuItem) GetCategoryId() uint32 {
+	if x != nil {
+		return x.CategoryId
+	}
 	return 0
 }
 
-func (x *MenuItem) GetCreatedAt() string {
+func (x *MenuItem) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *MenuItem) GetStock() int32 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
+	return 0
+}
+
+// Category message definition
+type Category struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_menu_v1_menu_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Category) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Category) GetCreatedAt() string {
 	if x != nil {
 		return x.CreatedAt
 	}
 	return ""
 }
 
-func (x *MenuItem) GetUpdatedAt() string {
+func (x *Category) GetUpdatedAt() string {
 	if x != nil {
 		return x.UpdatedAt
 	}
@@ -116,7 +224,7 @@ type GetMenuItemRequest struct {
 
 func (x *GetMenuItemRequest) Reset() {
 	*x = GetMenuItemRequest{}
-	mi := &file_menu_v1_menu_proto_msgTypes[1]
+	mi := &file_menu_v1_menu_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -128,7 +236,7 @@ func (x *GetMenuItemRequest) String() string {
 func (*GetMenuItemRequest) ProtoMessage() {}
 
 func (x *GetMenuItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_menu_v1_menu_proto_msgTypes[1]
+	mi := &file_menu_v1_menu_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -141,7 +249,7 @@ func (x *GetMenuItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMenuItemRequest.ProtoReflect.Descriptor instead.
 func (*GetMenuItemRequest) Descriptor() ([]byte, []int) {
-	return file_menu_v1_menu_proto_rawDescGZIP(), []int{1}
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetMenuItemRequest) GetId() uint32 {
@@ -161,7 +269,7 @@ type GetMenuItemResponse struct {
 
 func (x *GetMenuItemResponse) Reset() {
 	*x = GetMenuItemResponse{}
-	mi := &file_menu_v1_menu_proto_msgTypes[2]
+	mi := &file_menu_v1_menu_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -173,7 +281,7 @@ func (x *GetMenuItemResponse) String() string {
 func (*GetMenuItemResponse) ProtoMessage() {}
 
 func (x *GetMenuItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_menu_v1_menu_proto_msgTypes[2]
+	mi := &file_menu_v1_menu_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -186,7 +294,7 @@ func (x *GetMenuItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMenuItemResponse.ProtoReflect.Descriptor instead.
 func (*GetMenuItemResponse) Descriptor() ([]byte, []int) {
-	return file_menu_v1_menu_proto_rawDescGZIP(), []int{2}
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetMenuItemResponse) GetMenuItem() *MenuItem {
@@ -196,16 +304,133 @@ func (x *GetMenuItemResponse) GetMenuItem() *MenuItem {
 	return nil
 }
 
-// Get menu request (empty for now, can add filtering later)
+// Batch get menu items request
+type BatchGetMenuItemsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IDs to look up; duplicates are ignored
+	Ids           []uint32 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetMenuItemsRequest) Reset() {
+	*x = BatchGetMenuItemsRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetMenuItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetMenuItemsRequest) ProtoMessage() {}
+
+func (x *BatchGetMenuItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetMenuItemsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetMenuItemsRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchGetMenuItemsRequest) GetIds() []uint32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// Batch get menu items response
+type BatchGetMenuItemsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Items found, in the order their IDs were first requested
+	MenuItems []*MenuItem `protobuf:"bytes,1,rep,name=menu_items,json=menuItems,proto3" json:"menu_items,omitempty"`
+	// Requested IDs with no menu item, in request order
+	MissingIds    []uint32 `protobuf:"varint,2,rep,packed,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetMenuItemsResponse) Reset() {
+	*x = BatchGetMenuItemsResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetMenuItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetMenuItemsResponse) ProtoMessage() {}
+
+func (x *BatchGetMenuItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetMenuItemsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetMenuItemsResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchGetMenuItemsResponse) GetMenuItems() []*MenuItem {
+	if x != nil {
+		return x.MenuItems
+	}
+	return nil
+}
+
+func (x *BatchGetMenuItemsResponse) GetMissingIds() []uint32 {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+// Get menu request
 type GetMenuRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of items to return; defaults to 50, capped at 200
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous response
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Inclusive price bounds in minor units of the item's currency
+	MinPriceMinor *int64 `protobuf:"varint,3,opt,name=min_price_minor,json=minPriceMinor,proto3,oneof" json:"min_price_minor,omitempty"`
+	MaxPriceMinor *int64 `protobuf:"varint,4,opt,name=max_price_minor,json=maxPriceMinor,proto3,oneof" json:"max_price_minor,omitempty"`
+	// Case-insensitive substring of the item name
+	NameContains string `protobuf:"bytes,5,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	// Sort order, e.g. "price desc". Sortable fields: id, name, price,
+	// created_at. Defaults to "id".
+	OrderBy string `protobuf:"bytes,6,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Only return items in this category when set
+	CategoryId *uint32 `protobuf:"varint,7,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	// Only return items that are available and in stock
+	AvailableOnly bool `protobuf:"varint,8,opt,name=available_only,json=availableOnly,proto3" json:"available_only,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetMenuRequest) Reset() {
 	*x = GetMenuRequest{}
-	mi := &file_menu_v1_menu_proto_msgTypes[3]
+	mi := &file_menu_v1_menu_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -217,7 +442,7 @@ func (x *GetMenuRequest) String() string {
 func (*GetMenuRequest) ProtoMessage() {}
 
 func (x *GetMenuRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_menu_v1_menu_proto_msgTypes[3]
+	mi := &file_menu_v1_menu_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -230,20 +455,78 @@ func (x *GetMenuRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMenuRequest.ProtoReflect.Descriptor instead.
 func (*GetMenuRequest) Descriptor() ([]byte, []int) {
-	return file_menu_v1_menu_proto_rawDescGZIP(), []int{3}
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMenuRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetMenuRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetMenuRequest) GetMinPriceMinor() int64 {
+	if x != nil && x.MinPriceMinor != nil {
+		return *x.MinPriceMinor
+	}
+	return 0
+}
+
+func (x *GetMenuRequest) GetMaxPriceMinor() int64 {
+	if x != nil && x.MaxPriceMinor != nil {
+		return *x.MaxPriceMinor
+	}
+	return 0
+}
+
+func (x *GetMenuRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *GetMenuRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *GetMenuRequest) GetCategoryId() uint32 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
+func (x *GetMenuRequest) GetAvailableOnly() bool {
+	if x != nil {
+		return x.AvailableOnly
+	}
+	return false
 }
 
 // Get menu response
 type GetMenuResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MenuItems     []*MenuItem            `protobuf:"bytes,1,rep,name=menu_items,json=menuItems,proto3" json:"menu_items,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MenuItems []*MenuItem            `protobuf:"bytes,1,rep,name=menu_items,json=menuItems,proto3" json:"menu_items,omitempty"`
+	// Token for the next page; empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetMenuResponse) Reset() {
 	*x = GetMenuResponse{}
-	mi := &file_menu_v1_menu_proto_msgTypes[4]
+	mi := &file_menu_v1_menu_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -255,7 +538,7 @@ func (x *GetMenuResponse) String() string {
 func (*GetMenuResponse) ProtoMessage() {}
 
 func (x *GetMenuResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_menu_v1_menu_proto_msgTypes[4]
+	mi := &file_menu_v1_menu_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -268,7 +551,7 @@ func (x *GetMenuResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMenuResponse.ProtoReflect.Descriptor instead.
 func (*GetMenuResponse) Descriptor() ([]byte, []int) {
-	return file_menu_v1_menu_proto_rawDescGZIP(), []int{4}
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetMenuResponse) GetMenuItems() []*MenuItem {
@@ -278,19 +561,33 @@ func (x *GetMenuResponse) GetMenuItems() []*MenuItem {
 	return nil
 }
 
+func (x *GetMenuResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // Create menu item request
 type CreateMenuItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Currency defaults to USD when empty
+	Price *v1.Money `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	// Category to file the item under; 0 for none
+	CategoryId uint32 `protobuf:"varint,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Defaults to true when unset
+	Available *bool `protobuf:"varint,6,opt,name=available,proto3,oneof" json:"available,omitempty"`
+	// Initial stock; unset to not track stock
+	Stock         *int32 `protobuf:"varint,7,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateMenuItemRequest) Reset() {
 	*x = CreateMenuItemRequest{}
-	mi := &file_menu_v1_menu_proto_msgTypes[5]
+	mi := &file_menu_v1_menu_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +599,7 @@ func (x *CreateMenuItemRequest) String() string {
 func (*CreateMenuItemRequest) ProtoMessage() {}
 
 func (x *CreateMenuItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_menu_v1_menu_proto_msgTypes[5]
+	mi := &file_menu_v1_menu_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +612,7 @@ func (x *CreateMenuItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateMenuItemRequest.ProtoReflect.Descriptor instead.
 func (*CreateMenuItemRequest) Descriptor() ([]byte, []int) {
-	return file_menu_v1_menu_proto_rawDescGZIP(), []int{5}
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CreateMenuItemRequest) GetName() string {
@@ -332,10 +629,31 @@ func (x *CreateMenuItemRequest) GetDescription() string {
 	return ""
 }
 
-func (x *CreateMenuItemRequest) GetPrice() float64 {
+func (x *CreateMenuItemRequest) GetPrice() *v1.Money {
 	if x != nil {
 		return x.Price
 	}
+	return nil
+}
+
+func (x *CreateMenuItemRequest) GetCategoryId() uint32 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *CreateMenuItemRequest) GetAvailable() bool {
+	if x != nil && x.Available != nil {
+		return *x.Available
+	}
+	return false
+}
+
+func (x *CreateMenuItemRequest) GetStock() int32 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
 	return 0
 }
 
@@ -349,7 +667,7 @@ type CreateMenuItemResponse struct {
 
 func (x *CreateMenuItemResponse) Reset() {
 	*x = CreateMenuItemResponse{}
-	mi := &file_menu_v1_menu_proto_msgTypes[6]
+	mi := &file_menu_v1_menu_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +679,7 @@ func (x *CreateMenuItemResponse) String() string {
 func (*CreateMenuItemResponse) ProtoMessage() {}
 
 func (x *CreateMenuItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_menu_v1_menu_proto_msgTypes[6]
+	mi := &file_menu_v1_menu_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,7 +692,7 @@ func (x *CreateMenuItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateMenuItemResponse.ProtoReflect.Descriptor instead.
 func (*CreateMenuItemResponse) Descriptor() ([]byte, []int) {
-	return file_menu_v1_menu_proto_rawDescGZIP(), []int{6}
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CreateMenuItemResponse) GetMenuItem() *MenuItem {
@@ -384,38 +702,911 @@ func (x *CreateMenuItemResponse) GetMenuItem() *MenuItem {
 	return nil
 }
 
-var File_menu_v1_menu_proto protoreflect.FileDescriptor
+// Update menu item request
+type UpdateMenuItemRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The menu item to update, identified by id. Only the fields in the
+	// update mask are validated, as the others are ignored.
+	MenuItem *MenuItem `protobuf:"bytes,1,opt,name=menu_item,json=menuItem,proto3" json:"menu_item,omitempty"`
+	// Fields to update: name, description, price, category_id, available,
+	// stock. An empty mask updates all of them.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_menu_v1_menu_proto_rawDesc = "" +
-	"\n" +
-	"\x12menu/v1/menu.proto\x12\amenu.v1\"\xa4\x01\n" +
-	"\bMenuItem\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"$\n" +
-	"\x12GetMenuItemRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"E\n" +
-	"\x13GetMenuItemResponse\x12.\n" +
-	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem\"\x10\n" +
-	"\x0eGetMenuRequest\"C\n" +
-	"\x0fGetMenuResponse\x120\n" +
-	"\n" +
-	"menu_items\x18\x01 \x03(\v2\x11.menu.v1.MenuItemR\tmenuItems\"c\n" +
-	"\x15CreateMenuItemRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x01R\x05price\"H\n" +
-	"\x16CreateMenuItemResponse\x12.\n" +
-	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem2\xe8\x01\n" +
-	"\vMenuService\x12H\n" +
-	"\vGetMenuItem\x12\x1b.menu.v1.GetMenuItemRequest\x1a\x1c.menu.v1.GetMenuItemResponse\x12<\n" +
-	"\aGetMenu\x12\x17.menu.v1.GetMenuRequest\x1a\x18.menu.v1.GetMenuResponse\x12Q\n" +
-	"\x0eCreateMenuItem\x12\x1e.menu.v1.CreateMenuItemRequest\x1a\x1f.menu.v1.CreateMenuItemResponseBAZ?github.com/douglasswm/student-cafe-protos/gen/go/menu/v1;menuv1b\x06proto3"
+func (x *UpdateMenuItemRequest) Reset() {
+	*x = UpdateMenuItemRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMenuItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMenuItemRequest) ProtoMessage() {}
+
+func (x *UpdateMenuItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMenuItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMenuItemRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateMenuItemRequest) GetMenuItem() *MenuItem {
+	if x != nil {
+		return x.MenuItem
+	}
+	return nil
+}
+
+func (x *UpdateMenuItemRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// Update menu item response
+type UpdateMenuItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MenuItem      *MenuItem              `protobuf:"bytes,1,opt,name=menu_item,json=menuItem,proto3" json:"menu_item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMenuItemResponse) Reset() {
+	*x = UpdateMenuItemResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMenuItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMenuItemResponse) ProtoMessage() {}
+
+func (x *UpdateMenuItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMenuItemResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMenuItemResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateMenuItemResponse) GetMenuItem() *MenuItem {
+	if x != nil {
+		return x.MenuItem
+	}
+	return nil
+}
+
+// Delete menu item request
+type DeleteMenuItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMenuItemRequest) Reset() {
+	*x = DeleteMenuItemRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMenuItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMenuItemRequest) ProtoMessage() {}
+
+func (x *DeleteMenuItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMenuItemRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMenuItemRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteMenuItemRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Delete menu item response
+type DeleteMenuItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMenuItemResponse) Reset() {
+	*x = DeleteMenuItemResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMenuItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMenuItemResponse) ProtoMessage() {}
+
+func (x *DeleteMenuItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMenuItemResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMenuItemResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{13}
+}
+
+// Set menu item availability request
+type SetMenuItemAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Available     bool                   `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMenuItemAvailabilityRequest) Reset() {
+	*x = SetMenuItemAvailabilityRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMenuItemAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMenuItemAvailabilityRequest) ProtoMessage() {}
+
+func (x *SetMenuItemAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMenuItemAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*SetMenuItemAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SetMenuItemAvailabilityRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SetMenuItemAvailabilityRequest) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+// Set menu item availability response
+type SetMenuItemAvailabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MenuItem      *MenuItem              `protobuf:"bytes,1,opt,name=menu_item,json=menuItem,proto3" json:"menu_item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMenuItemAvailabilityResponse) Reset() {
+	*x = SetMenuItemAvailabilityResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMenuItemAvailabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMenuItemAvailabilityResponse) ProtoMessage() {}
+
+func (x *SetMenuItemAvailabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMenuItemAvailabilityResponse.ProtoReflect.Descriptor instead.
+func (*SetMenuItemAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetMenuItemAvailabilityResponse) GetMenuItem() *MenuItem {
+	if x != nil {
+		return x.MenuItem
+	}
+	return nil
+}
+
+// Set menu item stock request
+type SetMenuItemStockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// New stock level; unset to stop tracking stock
+	Stock         *int32 `protobuf:"varint,2,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMenuItemStockRequest) Reset() {
+	*x = SetMenuItemStockRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMenuItemStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMenuItemStockRequest) ProtoMessage() {}
+
+func (x *SetMenuItemStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMenuItemStockRequest.ProtoReflect.Descriptor instead.
+func (*SetMenuItemStockRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetMenuItemStockRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SetMenuItemStockRequest) GetStock() int32 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
+	return 0
+}
+
+// Set menu item stock response
+type SetMenuItemStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MenuItem      *MenuItem              `protobuf:"bytes,1,opt,name=menu_item,json=menuItem,proto3" json:"menu_item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMenuItemStockResponse) Reset() {
+	*x = SetMenuItemStockResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMenuItemStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMenuItemStockResponse) ProtoMessage() {}
+
+func (x *SetMenuItemStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMenuItemStockResponse.ProtoReflect.Descriptor instead.
+func (*SetMenuItemStockResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetMenuItemStockResponse) GetMenuItem() *MenuItem {
+	if x != nil {
+		return x.MenuItem
+	}
+	return nil
+}
+
+// StockReservation is a quantity of one menu item
+type StockReservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MenuItemId    uint32                 `protobuf:"varint,1,opt,name=menu_item_id,json=menuItemId,proto3" json:"menu_item_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockReservation) Reset() {
+	*x = StockReservation{}
+	mi := &file_menu_v1_menu_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockReservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockReservation) ProtoMessage() {}
+
+func (x *StockReservation) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockReservation.ProtoReflect.Descriptor instead.
+func (*StockReservation) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StockReservation) GetMenuItemId() uint32 {
+	if x != nil {
+		return x.MenuItemId
+	}
+	return 0
+}
+
+func (x *StockReservation) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Reserve stock request. Either every item is reserved or none is.
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockReservation    `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockReservation {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Reserve stock response
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{20}
+}
+
+// Release stock request
+type ReleaseStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockReservation    `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseStockRequest) Reset() {
+	*x = ReleaseStockRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseStockRequest) ProtoMessage() {}
+
+func (x *ReleaseStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseStockRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseStockRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReleaseStockRequest) GetItems() []*StockReservation {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Release stock response
+type ReleaseStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseStockResponse) Reset() {
+	*x = ReleaseStockResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseStockResponse) ProtoMessage() {}
+
+func (x *ReleaseStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseStockResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseStockResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{22}
+}
+
+// Create category request
+type CreateCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoryRequest) Reset() {
+	*x = CreateCategoryRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoryRequest) ProtoMessage() {}
+
+func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCategoryRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// Create category response
+type CreateCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoryResponse) Reset() {
+	*x = CreateCategoryResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoryResponse) ProtoMessage() {}
+
+func (x *CreateCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoryResponse.ProtoReflect.Descriptor instead.
+func (*CreateCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateCategoryResponse) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+// Get categories request
+type GetCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoriesRequest) Reset() {
+	*x = GetCategoriesRequest{}
+	mi := &file_menu_v1_menu_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesRequest) ProtoMessage() {}
+
+func (x *GetCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{25}
+}
+
+// Get categories response
+type GetCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoriesResponse) Reset() {
+	*x = GetCategoriesResponse{}
+	mi := &file_menu_v1_menu_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesResponse) ProtoMessage() {}
+
+func (x *GetCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_v1_menu_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*GetCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_menu_v1_menu_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+var File_menu_v1_menu_proto protoreflect.FileDescriptor
+
+const file_menu_v1_menu_proto_rawDesc = "" +
+	"\n" +
+	"\x12menu/v1/menu.proto\x12\amenu.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15common/v1/money.proto\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\"\xa0\x02\n" +
+	"\bMenuItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12&\n" +
+	"\x05price\x18\a \x01(\v2\x10.common.v1.MoneyR\x05price\x12\x1f\n" +
+	"\vcategory_id\x18\b \x01(\rR\n" +
+	"categoryId\x12\x1c\n" +
+	"\tavailable\x18\t \x01(\bR\tavailable\x12\x19\n" +
+	"\x05stock\x18\n" +
+	" \x01(\x05H\x00R\x05stock\x88\x01\x01B\b\n" +
+	"\x06_stockJ\x04\b\x04\x10\x05\"\x8e\x01\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"-\n" +
+	"\x12GetMenuItemRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\rB\a\xbaH\x04*\x02 \x00R\x02id\"E\n" +
+	"\x13GetMenuItemResponse\x12.\n" +
+	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem\"?\n" +
+	"\x18BatchGetMenuItemsRequest\x12#\n" +
+	"\x03ids\x18\x01 \x03(\rB\x11\xbaH\x0e\x92\x01\v\b\x01\x10\xc8\x01\"\x04*\x02 \x00R\x03ids\"n\n" +
+	"\x19BatchGetMenuItemsResponse\x120\n" +
+	"\n" +
+	"menu_items\x18\x01 \x03(\v2\x11.menu.v1.MenuItemR\tmenuItems\x12\x1f\n" +
+	"\vmissing_ids\x18\x02 \x03(\rR\n" +
+	"missingIds\"\x86\x03\n" +
+	"\x0eGetMenuRequest\x12$\n" +
+	"\tpage_size\x18\x01 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x124\n" +
+	"\x0fmin_price_minor\x18\x03 \x01(\x03B\a\xbaH\x04\"\x02(\x00H\x00R\rminPriceMinor\x88\x01\x01\x124\n" +
+	"\x0fmax_price_minor\x18\x04 \x01(\x03B\a\xbaH\x04\"\x02(\x00H\x01R\rmaxPriceMinor\x88\x01\x01\x12#\n" +
+	"\rname_contains\x18\x05 \x01(\tR\fnameContains\x12\x19\n" +
+	"\border_by\x18\x06 \x01(\tR\aorderBy\x12$\n" +
+	"\vcategory_id\x18\a \x01(\rH\x02R\n" +
+	"categoryId\x88\x01\x01\x12%\n" +
+	"\x0eavailable_only\x18\b \x01(\bR\ravailableOnlyB\x12\n" +
+	"\x10_min_price_minorB\x12\n" +
+	"\x10_max_price_minorB\x0e\n" +
+	"\f_category_id\"k\n" +
+	"\x0fGetMenuResponse\x120\n" +
+	"\n" +
+	"menu_items\x18\x01 \x03(\v2\x11.menu.v1.MenuItemR\tmenuItems\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x98\x02\n" +
+	"\x15CreateMenuItemRequest\x12\x1d\n" +
+	"\x04name\x18\x01 \x01(\tB\t\xbaH\x06r\x04\x10\x01\x18dR\x04name\x12*\n" +
+	"\vdescription\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xf4\x03R\vdescription\x12.\n" +
+	"\x05price\x18\x04 \x01(\v2\x10.common.v1.MoneyB\x06\xbaH\x03\xc8\x01\x01R\x05price\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\rR\n" +
+	"categoryId\x12!\n" +
+	"\tavailable\x18\x06 \x01(\bH\x00R\tavailable\x88\x01\x01\x12\"\n" +
+	"\x05stock\x18\a \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\x01R\x05stock\x88\x01\x01B\f\n" +
+	"\n" +
+	"_availableB\b\n" +
+	"\x06_stockJ\x04\b\x03\x10\x04\"H\n" +
+	"\x16CreateMenuItemResponse\x12.\n" +
+	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem\"\xab\x02\n" +
+	"\x15UpdateMenuItemRequest\x12\xd4\x01\n" +
+	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemB\xa3\x01\xbaH\x9f\x01\xba\x016\n" +
+	"\fmenu_item.id\x12\x19id must be greater than 0\x1a\vthis.id > 0\xba\x01`\n" +
+	"\x0fmenu_item.stock\x12(stock must be greater than or equal to 0\x1a#!has(this.stock) || this.stock >= 0\xc8\x01\x01R\bmenuItem\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"H\n" +
+	"\x16UpdateMenuItemResponse\x12.\n" +
+	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem\"0\n" +
+	"\x15DeleteMenuItemRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\rB\a\xbaH\x04*\x02 \x00R\x02id\"\x18\n" +
+	"\x16DeleteMenuItemResponse\"W\n" +
+	"\x1eSetMenuItemAvailabilityRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\rB\a\xbaH\x04*\x02 \x00R\x02id\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\bR\tavailable\"Q\n" +
+	"\x1fSetMenuItemAvailabilityResponse\x12.\n" +
+	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem\"`\n" +
+	"\x17SetMenuItemStockRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\rB\a\xbaH\x04*\x02 \x00R\x02id\x12\"\n" +
+	"\x05stock\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\x00R\x05stock\x88\x01\x01B\b\n" +
+	"\x06_stock\"J\n" +
+	"\x18SetMenuItemStockResponse\x12.\n" +
+	"\tmenu_item\x18\x01 \x01(\v2\x11.menu.v1.MenuItemR\bmenuItem\"b\n" +
+	"\x10StockReservation\x12)\n" +
+	"\fmenu_item_id\x18\x01 \x01(\rB\a\xbaH\x04*\x02 \x00R\n" +
+	"menuItemId\x12#\n" +
+	"\bquantity\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02 \x00R\bquantity\"P\n" +
+	"\x13ReserveStockRequest\x129\n" +
+	"\x05items\x18\x01 \x03(\v2\x19.menu.v1.StockReservationB\b\xbaH\x05\x92\x01\x02\b\x01R\x05items\"\x16\n" +
+	"\x14ReserveStockResponse\"P\n" +
+	"\x13ReleaseStockRequest\x129\n" +
+	"\x05items\x18\x01 \x03(\v2\x19.menu.v1.StockReservationB\b\xbaH\x05\x92\x01\x02\b\x01R\x05items\"\x16\n" +
+	"\x14ReleaseStockResponse\"b\n" +
+	"\x15CreateCategoryRequest\x12\x1d\n" +
+	"\x04name\x18\x01 \x01(\tB\t\xbaH\x06r\x04\x10\x01\x18dR\x04name\x12*\n" +
+	"\vdescription\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xf4\x03R\vdescription\"G\n" +
+	"\x16CreateCategoryResponse\x12-\n" +
+	"\bcategory\x18\x01 \x01(\v2\x11.menu.v1.CategoryR\bcategory\"\x16\n" +
+	"\x14GetCategoriesRequest\"J\n" +
+	"\x15GetCategoriesResponse\x121\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x11.menu.v1.CategoryR\n" +
+	"categories2\x85\n" +
+	"\n" +
+	"\vMenuService\x12_\n" +
+	"\vGetMenuItem\x12\x1b.menu.v1.GetMenuItemRequest\x1a\x1c.menu.v1.GetMenuItemResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/menu/{id}\x12u\n" +
+	"\x11BatchGetMenuItems\x12!.menu.v1.BatchGetMenuItemsRequest\x1a\".menu.v1.BatchGetMenuItemsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/menu:batchGet\x12N\n" +
+	"\aGetMenu\x12\x17.menu.v1.GetMenuRequest\x1a\x18.menu.v1.GetMenuResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/v1/menu\x12f\n" +
+	"\x0eCreateMenuItem\x12\x1e.menu.v1.CreateMenuItemRequest\x1a\x1f.menu.v1.CreateMenuItemResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/v1/menu\x12}\n" +
+	"\x0eUpdateMenuItem\x12\x1e.menu.v1.UpdateMenuItemRequest\x1a\x1f.menu.v1.UpdateMenuItemResponse\"*\x82\xd3\xe4\x93\x02$:\tmenu_item2\x17/v1/menu/{menu_item.id}\x12h\n" +
+	"\x0eDeleteMenuItem\x12\x1e.menu.v1.DeleteMenuItemRequest\x1a\x1f.menu.v1.DeleteMenuItemResponse\"\x15\x82\xd3\xe4\x93\x02\x0f*\r/v1/menu/{id}\x12\x93\x01\n" +
+	"\x17SetMenuItemAvailability\x12'.menu.v1.SetMenuItemAvailabilityRequest\x1a(.menu.v1.SetMenuItemAvailabilityResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\x1a\x1a/v1/menu/{id}/availability\x12w\n" +
+	"\x10SetMenuItemStock\x12 .menu.v1.SetMenuItemStockRequest\x1a!.menu.v1.SetMenuItemStockResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\x1a\x13/v1/menu/{id}/stock\x12K\n" +
+	"\fReserveStock\x12\x1c.menu.v1.ReserveStockRequest\x1a\x1d.menu.v1.ReserveStockResponse\x12K\n" +
+	"\fReleaseStock\x12\x1c.menu.v1.ReleaseStockRequest\x1a\x1d.menu.v1.ReleaseStockResponse\x12l\n" +
+	"\x0eCreateCategory\x12\x1e.menu.v1.CreateCategoryRequest\x1a\x1f.menu.v1.CreateCategoryResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/categories\x12f\n" +
+	"\rGetCategories\x12\x1d.menu.v1.GetCategoriesRequest\x1a\x1e.menu.v1.GetCategoriesResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/categoriesBAZ?github.com/douglasswm/student-cafe-protos/gen/go/menu/v1;menuv1b\x06proto3"
 
 var (
 	file_menu_v1_menu_proto_rawDescOnce sync.Once
@@ -429,31 +1620,83 @@ func file_menu_v1_menu_proto_rawDescGZIP() []byte {
 	return file_menu_v1_menu_proto_rawDescData
 }
 
-var file_menu_v1_menu_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_menu_v1_menu_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_menu_v1_menu_proto_goTypes = []any{
-	(*MenuItem)(nil),               // 0: menu.v1.MenuItem
-	(*GetMenuItemRequest)(nil),     // 1: menu.v1.GetMenuItemRequest
-	(*GetMenuItemResponse)(nil),    // 2: menu.v1.GetMenuItemResponse
-	(*GetMenuRequest)(nil),         // 3: menu.v1.GetMenuRequest
-	(*GetMenuResponse)(nil),        // 4: menu.v1.GetMenuResponse
-	(*CreateMenuItemRequest)(nil),  // 5: menu.v1.CreateMenuItemRequest
-	(*CreateMenuItemResponse)(nil), // 6: menu.v1.CreateMenuItemResponse
+	(*MenuItem)(nil),                        // 0: menu.v1.MenuItem
+	(*Category)(nil),                        // 1: menu.v1.Category
+	(*GetMenuItemRequest)(nil),              // 2: menu.v1.GetMenuItemRequest
+	(*GetMenuItemResponse)(nil),             // 3: menu.v1.GetMenuItemResponse
+	(*BatchGetMenuItemsRequest)(nil),        // 4: menu.v1.BatchGetMenuItemsRequest
+	(*BatchGetMenuItemsResponse)(nil),       // 5: menu.v1.BatchGetMenuItemsResponse
+	(*GetMenuRequest)(nil),                  // 6: menu.v1.GetMenuRequest
+	(*GetMenuResponse)(nil),                 // 7: menu.v1.GetMenuResponse
+	(*CreateMenuItemRequest)(nil),           // 8: menu.v1.CreateMenuItemRequest
+	(*CreateMenuItemResponse)(nil),          // 9: menu.v1.CreateMenuItemResponse
+	(*UpdateMenuItemRequest)(nil),           // 10: menu.v1.UpdateMenuItemRequest
+	(*UpdateMenuItemResponse)(nil),          // 11: menu.v1.UpdateMenuItemResponse
+	(*DeleteMenuItemRequest)(nil),           // 12: menu.v1.DeleteMenuItemRequest
+	(*DeleteMenuItemResponse)(nil),          // 13: menu.v1.DeleteMenuItemResponse
+	(*SetMenuItemAvailabilityRequest)(nil),  // 14: menu.v1.SetMenuItemAvailabilityRequest
+	(*SetMenuItemAvailabilityResponse)(nil), // 15: menu.v1.SetMenuItemAvailabilityResponse
+	(*SetMenuItemStockRequest)(nil),         // 16: menu.v1.SetMenuItemStockRequest
+	(*SetMenuItemStockResponse)(nil),        // 17: menu.v1.SetMenuItemStockResponse
+	(*StockReservation)(nil),                // 18: menu.v1.StockReservation
+	(*ReserveStockRequest)(nil),             // 19: menu.v1.ReserveStockRequest
+	(*ReserveStockResponse)(nil),            // 20: menu.v1.ReserveStockResponse
+	(*ReleaseStockRequest)(nil),             // 21: menu.v1.ReleaseStockRequest
+	(*ReleaseStockResponse)(nil),            // 22: menu.v1.ReleaseStockResponse
+	(*CreateCategoryRequest)(nil),           // 23: menu.v1.CreateCategoryRequest
+	(*CreateCategoryResponse)(nil),          // 24: menu.v1.CreateCategoryResponse
+	(*GetCategoriesRequest)(nil),            // 25: menu.v1.GetCategoriesRequest
+	(*GetCategoriesResponse)(nil),           // 26: menu.v1.GetCategoriesResponse
+	(*v1.Money)(nil),                        // 27: common.v1.Money
+	(*fieldmaskpb.FieldMask)(nil),           // 28: google.protobuf.FieldMask
 }
 var file_menu_v1_menu_proto_depIdxs = []int32{
-	0, // 0: menu.v1.GetMenuItemResponse.menu_item:type_name -> menu.v1.MenuItem
-	0, // 1: menu.v1.GetMenuResponse.menu_items:type_name -> menu.v1.MenuItem
-	0, // 2: menu.v1.CreateMenuItemResponse.menu_item:type_name -> menu.v1.MenuItem
-	1, // 3: menu.v1.MenuService.GetMenuItem:input_type -> menu.v1.GetMenuItemRequest
-	3, // 4: menu.v1.MenuService.GetMenu:input_type -> menu.v1.GetMenuRequest
-	5, // 5: menu.v1.MenuService.CreateMenuItem:input_type -> menu.v1.CreateMenuItemRequest
-	2, // 6: menu.v1.MenuService.GetMenuItem:output_type -> menu.v1.GetMenuItemResponse
-	4, // 7: menu.v1.MenuService.GetMenu:output_type -> menu.v1.GetMenuResponse
-	6, // 8: menu.v1.MenuService.CreateMenuItem:output_type -> menu.v1.CreateMenuItemResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	27, // 0: menu.v1.MenuItem.price:type_name -> common.v1.Money
+	0,  // 1: menu.v1.GetMenuItemResponse.menu_item:type_name -> menu.v1.MenuItem
+	0,  // 2: menu.v1.BatchGetMenuItemsResponse.menu_items:type_name -> menu.v1.MenuItem
+	0,  // 3: menu.v1.GetMenuResponse.menu_items:type_name -> menu.v1.MenuItem
+	27, // 4: menu.v1.CreateMenuItemRequest.price:type_name -> common.v1.Money
+	0,  // 5: menu.v1.CreateMenuItemResponse.menu_item:type_name -> menu.v1.MenuItem
+	0,  // 6: menu.v1.UpdateMenuItemRequest.menu_item:type_name -> menu.v1.MenuItem
+	28, // 7: menu.v1.UpdateMenuItemRequest.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 8: menu.v1.UpdateMenuItemResponse.menu_item:type_name -> menu.v1.MenuItem
+	0,  // 9: menu.v1.SetMenuItemAvailabilityResponse.menu_item:type_name -> menu.v1.MenuItem
+	0,  // 10: menu.v1.SetMenuItemStockResponse.menu_item:type_name -> menu.v1.MenuItem
+	18, // 11: menu.v1.ReserveStockRequest.items:type_name -> menu.v1.StockReservation
+	18, // 12: menu.v1.ReleaseStockRequest.items:type_name -> menu.v1.StockReservation
+	1,  // 13: menu.v1.CreateCategoryResponse.category:type_name -> menu.v1.Category
+	1,  // 14: menu.v1.GetCategoriesResponse.categories:type_name -> menu.v1.Category
+	2,  // 15: menu.v1.MenuService.GetMenuItem:input_type -> menu.v1.GetMenuItemRequest
+	4,  // 16: menu.v1.MenuService.BatchGetMenuItems:input_type -> menu.v1.BatchGetMenuItemsRequest
+	6,  // 17: menu.v1.MenuService.GetMenu:input_type -> menu.v1.GetMenuRequest
+	8,  // 18: menu.v1.MenuService.CreateMenuItem:input_type -> menu.v1.CreateMenuItemRequest
+	10, // 19: menu.v1.MenuService.UpdateMenuItem:input_type -> menu.v1.UpdateMenuItemRequest
+	12, // 20: menu.v1.MenuService.DeleteMenuItem:input_type -> menu.v1.DeleteMenuItemRequest
+	14, // 21: menu.v1.MenuService.SetMenuItemAvailability:input_type -> menu.v1.SetMenuItemAvailabilityRequest
+	16, // 22: menu.v1.MenuService.SetMenuItemStock:input_type -> menu.v1.SetMenuItemStockRequest
+	19, // 23: menu.v1.MenuService.ReserveStock:input_type -> menu.v1.ReserveStockRequest
+	21, // 24: menu.v1.MenuService.ReleaseStock:input_type -> menu.v1.ReleaseStockRequest
+	23, // 25: menu.v1.MenuService.CreateCategory:input_type -> menu.v1.CreateCategoryRequest
+	25, // 26: menu.v1.MenuService.GetCategories:input_type -> menu.v1.GetCategoriesRequest
+	3,  // 27: menu.v1.MenuService.GetMenuItem:output_type -> menu.v1.GetMenuItemResponse
+	5,  // 28: menu.v1.MenuService.BatchGetMenuItems:output_type -> menu.v1.BatchGetMenuItemsResponse
+	7,  // 29: menu.v1.MenuService.GetMenu:output_type -> menu.v1.GetMenuResponse
+	9,  // 30: menu.v1.MenuService.CreateMenuItem:output_type -> menu.v1.CreateMenuItemResponse
+	11, // 31: menu.v1.MenuService.UpdateMenuItem:output_type -> menu.v1.UpdateMenuItemResponse
+	13, // 32: menu.v1.MenuService.DeleteMenuItem:output_type -> menu.v1.DeleteMenuItemResponse
+	15, // 33: menu.v1.MenuService.SetMenuItemAvailability:output_type -> menu.v1.SetMenuItemAvailabilityResponse
+	17, // 34: menu.v1.MenuService.SetMenuItemStock:output_type -> menu.v1.SetMenuItemStockResponse
+	20, // 35: menu.v1.MenuService.ReserveStock:output_type -> menu.v1.ReserveStockResponse
+	22, // 36: menu.v1.MenuService.ReleaseStock:output_type -> menu.v1.ReleaseStockResponse
+	24, // 37: menu.v1.MenuService.CreateCategory:output_type -> menu.v1.CreateCategoryResponse
+	26, // 38: menu.v1.MenuService.GetCategories:output_type -> menu.v1.GetCategoriesResponse
+	27, // [27:39] is the sub-list for method output_type
+	15, // [15:27] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_menu_v1_menu_proto_init() }
@@ -461,13 +1704,17 @@ func file_menu_v1_menu_proto_init() {
 	if File_menu_v1_menu_proto != nil {
 		return
 	}
+	file_menu_v1_menu_proto_msgTypes[0].OneofWrappers = []any{}
+	file_menu_v1_menu_proto_msgTypes[6].OneofWrappers = []any{}
+	file_menu_v1_menu_proto_msgTypes[8].OneofWrappers = []any{}
+	file_menu_v1_menu_proto_msgTypes[16].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_menu_v1_menu_proto_rawDesc), len(file_menu_v1_menu_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,9 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MenuService_GetMenuItem_FullMethodName    = "/menu.v1.MenuService/GetMenuItem"
-	MenuService_GetMenu_FullMethodName        = "/menu.v1.MenuService/GetMenu"
-	MenuService_CreateMenuItem_FullMethodName = "/menu.v1.MenuService/CreateMenuItem"
+	MenuService_GetMenuItem_FullMethodName             = "/menu.v1.MenuService/GetMenuItem"
+	MenuService_BatchGetMenuItems_FullMethodName       = "/menu.v1.MenuService/BatchGetMenuItems"
+	MenuService_GetMenu_FullMethodName                 = "/menu.v1.MenuService/GetMenu"
+	MenuService_CreateMenuItem_FullMethodName          = "/menu.v1.MenuService/CreateMenuItem"
+	MenuService_UpdateMenuItem_FullMethodName          = "/menu.v1.MenuService/UpdateMenuItem"
+	MenuService_DeleteMenuItem_FullMethodName          = "/menu.v1.MenuService/DeleteMenuItem"
+	MenuService_SetMenuItemAvailability_FullMethodName = "/menu.v1.MenuService/SetMenuItemAvailability"
+	MenuService_SetMenuItemStock_FullMethodName        = "/menu.v1.MenuService/SetMenuItemStock"
+	MenuService_ReserveStock_FullMethodName            = "/menu.v1.MenuService/ReserveStock"
+	MenuService_ReleaseStock_FullMethodName            = "/menu.v1.MenuService/ReleaseStock"
+	MenuService_CreateCategory_FullMethodName          = "/menu.v1.MenuService/CreateCategory"
+	MenuService_GetCategories_FullMethodName           = "/menu.v1.MenuService/GetCategories"
 )
 
 // MenuServiceClient is the client API for MenuService service.
@@ -32,10 +41,30 @@ const (
 type MenuServiceClient interface {
 	// Get a menu item by ID
 	GetMenuItem(ctx context.Context, in *GetMenuItemRequest, opts ...grpc.CallOption) (*GetMenuItemResponse, error)
+	// Get several menu items by ID in one call
+	BatchGetMenuItems(ctx context.Context, in *BatchGetMenuItemsRequest, opts ...grpc.CallOption) (*BatchGetMenuItemsResponse, error)
 	// Get all menu items
 	GetMenu(ctx context.Context, in *GetMenuRequest, opts ...grpc.CallOption) (*GetMenuResponse, error)
 	// Create a new menu item
 	CreateMenuItem(ctx context.Context, in *CreateMenuItemRequest, opts ...grpc.CallOption) (*CreateMenuItemResponse, error)
+	// Update the fields of a menu item named in the update mask
+	UpdateMenuItem(ctx context.Context, in *UpdateMenuItemRequest, opts ...grpc.CallOption) (*UpdateMenuItemResponse, error)
+	// Soft-delete a menu item
+	DeleteMenuItem(ctx context.Context, in *DeleteMenuItemRequest, opts ...grpc.CallOption) (*DeleteMenuItemResponse, error)
+	// Take a menu item off the menu or put it back
+	SetMenuItemAvailability(ctx context.Context, in *SetMenuItemAvailabilityRequest, opts ...grpc.CallOption) (*SetMenuItemAvailabilityResponse, error)
+	// Set or stop tracking a menu item's stock
+	SetMenuItemStock(ctx context.Context, in *SetMenuItemStockRequest, opts ...grpc.CallOption) (*SetMenuItemStockResponse, error)
+	// Atomically check and decrement stock for the items of an order. Only
+	// order-service calls this, so it has no HTTP mapping.
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error)
+	// Return stock reserved by ReserveStock, e.g. when an order is cancelled.
+	// Like ReserveStock it has no HTTP mapping.
+	ReleaseStock(ctx context.Context, in *ReleaseStockRequest, opts ...grpc.CallOption) (*ReleaseStockResponse, error)
+	// Create a new category
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error)
+	// Get all categories
+	GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error)
 }
 
 type menuServiceClient struct {
@@ -56,6 +85,16 @@ func (c *menuServiceClient) GetMenuItem(ctx context.Context, in *GetMenuItemRequ
 	return out, nil
 }
 
+func (c *menuServiceClient) BatchGetMenuItems(ctx context.Context, in *BatchGetMenuItemsRequest, opts ...grpc.CallOption) (*BatchGetMenuItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetMenuItemsResponse)
+	err := c.cc.Invoke(ctx, MenuService_BatchGetMenuItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *menuServiceClient) GetMenu(ctx context.Context, in *GetMenuRequest, opts ...grpc.CallOption) (*GetMenuResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetMenuResponse)
@@ -76,6 +115,86 @@ func (c *menuServiceClient) CreateMenuItem(ctx context.Context, in *CreateMenuIt
 	return out, nil
 }
 
+func (c *menuServiceClient) UpdateMenuItem(ctx context.Context, in *UpdateMenuItemRequest, opts ...grpc.CallOption) (*UpdateMenuItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateMenuItemResponse)
+	err := c.cc.Invoke(ctx, MenuService_UpdateMenuItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) DeleteMenuItem(ctx context.Context, in *DeleteMenuItemRequest, opts ...grpc.CallOption) (*DeleteMenuItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMenuItemResponse)
+	err := c.cc.Invoke(ctx, MenuService_DeleteMenuItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) SetMenuItemAvailability(ctx context.Context, in *SetMenuItemAvailabilityRequest, opts ...grpc.CallOption) (*SetMenuItemAvailabilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMenuItemAvailabilityResponse)
+	err := c.cc.Invoke(ctx, MenuService_SetMenuItemAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) SetMenuItemStock(ctx context.Context, in *SetMenuItemStockRequest, opts ...grpc.CallOption) (*SetMenuItemStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMenuItemStockResponse)
+	err := c.cc.Invoke(ctx, MenuService_SetMenuItemStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveStockResponse)
+	err := c.cc.Invoke(ctx, MenuService_ReserveStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) ReleaseStock(ctx context.Context, in *ReleaseStockRequest, opts ...grpc.CallOption) (*ReleaseStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseStockResponse)
+	err := c.cc.Invoke(ctx, MenuService_ReleaseStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCategoryResponse)
+	err := c.cc.Invoke(ctx, MenuService_CreateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCategoriesResponse)
+	err := c.cc.Invoke(ctx, MenuService_GetCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MenuServiceServer is the server API for MenuService service.
 // All implementations must embed UnimplementedMenuServiceServer
 // for forward compatibility.
@@ -84,10 +203,30 @@ func (c *menuServiceClient) CreateMenuItem(ctx context.Context, in *CreateMenuIt
 type MenuServiceServer interface {
 	// Get a menu item by ID
 	GetMenuItem(context.Context, *GetMenuItemRequest) (*GetMenuItemResponse, error)
+	// Get several menu items by ID in one call
+	BatchGetMenuItems(context.Context, *BatchGetMenuItemsRequest) (*BatchGetMenuItemsResponse, error)
 	// Get all menu items
 	GetMenu(context.Context, *GetMenuRequest) (*GetMenuResponse, error)
 	// Create a new menu item
 	CreateMenuItem(context.Context, *CreateMenuItemRequest) (*CreateMenuItemResponse, error)
+	// Update the fields of a menu item named in the update mask
+	UpdateMenuItem(context.Context, *UpdateMenuItemRequest) (*UpdateMenuItemResponse, error)
+	// Soft-delete a menu item
+	DeleteMenuItem(context.Context, *DeleteMenuItemRequest) (*DeleteMenuItemResponse, error)
+	// Take a menu item off the menu or put it back
+	SetMenuItemAvailability(context.Context, *SetMenuItemAvailabilityRequest) (*SetMenuItemAvailabilityResponse, error)
+	// Set or stop tracking a menu item's stock
+	SetMenuItemStock(context.Context, *SetMenuItemStockRequest) (*SetMenuItemStockResponse, error)
+	// Atomically check and decrement stock for the items of an order. Only
+	// order-service calls this, so it has no HTTP mapping.
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error)
+	// Return stock reserved by ReserveStock, e.g. when an order is cancelled.
+	// Like ReserveStock it has no HTTP mapping.
+	ReleaseStock(context.Context, *ReleaseStockRequest) (*ReleaseStockResponse, error)
+	// Create a new category
+	CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error)
+	// Get all categories
+	GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error)
 	mustEmbedUnimplementedMenuServiceServer()
 }
 
@@ -101,12 +240,39 @@ type UnimplementedMenuServiceServer struct{}
 func (UnimplementedMenuServiceServer) GetMenuItem(context.Context, *GetMenuItemRequest) (*GetMenuItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMenuItem not implemented")
 }
+func (UnimplementedMenuServiceServer) BatchGetMenuItems(context.Context, *BatchGetMenuItemsRequest) (*BatchGetMenuItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetMenuItems not implemented")
+}
 func (UnimplementedMenuServiceServer) GetMenu(context.Context, *GetMenuRequest) (*GetMenuResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMenu not implemented")
 }
 func (UnimplementedMenuServiceServer) CreateMenuItem(context.Context, *CreateMenuItemRequest) (*CreateMenuItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateMenuItem not implemented")
 }
+func (UnimplementedMenuServiceServer) UpdateMenuItem(context.Context, *UpdateMenuItemRequest) (*UpdateMenuItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMenuItem not implemented")
+}
+func (UnimplementedMenuServiceServer) DeleteMenuItem(context.Context, *DeleteMenuItemRequest) (*DeleteMenuItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMenuItem not implemented")
+}
+func (UnimplementedMenuServiceServer) SetMenuItemAvailability(context.Context, *SetMenuItemAvailabilityRequest) (*SetMenuItemAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMenuItemAvailability not implemented")
+}
+func (UnimplementedMenuServiceServer) SetMenuItemStock(context.Context, *SetMenuItemStockRequest) (*SetMenuItemStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMenuItemStock not implemented")
+}
+func (UnimplementedMenuServiceServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedMenuServiceServer) ReleaseStock(context.Context, *ReleaseStockRequest) (*ReleaseStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseStock not implemented")
+}
+func (UnimplementedMenuServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedMenuServiceServer) GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategories not implemented")
+}
 func (UnimplementedMenuServiceServer) mustEmbedUnimplementedMenuServiceServer() {}
 func (UnimplementedMenuServiceServer) testEmbeddedByValue()                     {}
 
@@ -146,6 +312,24 @@ func _MenuService_GetMenuItem_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MenuService_BatchGetMenuItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetMenuItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).BatchGetMenuItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_BatchGetMenuItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).BatchGetMenuItems(ctx, req.(*BatchGetMenuItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MenuService_GetMenu_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetMenuRequest)
 	if err := dec(in); err != nil {
@@ -182,6 +366,150 @@ func _MenuService_CreateMenuItem_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MenuService_UpdateMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMenuItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).UpdateMenuItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_UpdateMenuItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).UpdateMenuItem(ctx, req.(*UpdateMenuItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_DeleteMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMenuItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).DeleteMenuItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_DeleteMenuItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).DeleteMenuItem(ctx, req.(*DeleteMenuItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_SetMenuItemAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMenuItemAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).SetMenuItemAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_SetMenuItemAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).SetMenuItemAvailability(ctx, req.(*SetMenuItemAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_SetMenuItemStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMenuItemStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).SetMenuItemStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_SetMenuItemStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).SetMenuItemStock(ctx, req.(*SetMenuItemStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_ReleaseStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).ReleaseStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_ReleaseStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).ReleaseStock(ctx, req.(*ReleaseStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_GetCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).GetCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_GetCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).GetCategories(ctx, req.(*GetCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MenuService_ServiceDesc is the grpc.ServiceDesc for MenuService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -193,6 +521,10 @@ var MenuService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMenuItem",
 			Handler:    _MenuService_GetMenuItem_Handler,
 		},
+		{
+			MethodName: "BatchGetMenuItems",
+			Handler:    _MenuService_BatchGetMenuItems_Handler,
+		},
 		{
 			MethodName: "GetMenu",
 			Handler:    _MenuService_GetMenu_Handler,
@@ -201,6 +533,38 @@ var MenuService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateMenuItem",
 			Handler:    _MenuService_CreateMenuItem_Handler,
 		},
+		{
+			MethodName: "UpdateMenuItem",
+			Handler:    _MenuService_UpdateMenuItem_Handler,
+		},
+		{
+			MethodName: "DeleteMenuItem",
+			Handler:    _MenuService_DeleteMenuItem_Handler,
+		},
+		{
+			MethodName: "SetMenuItemAvailability",
+			Handler:    _MenuService_SetMenuItemAvailability_Handler,
+		},
+		{
+			MethodName: "SetMenuItemStock",
+			Handler:    _MenuService_SetMenuItemStock_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _MenuService_ReserveStock_Handler,
+		},
+		{
+			MethodName: "ReleaseStock",
+			Handler:    _MenuService_ReleaseStock_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _MenuService_CreateCategory_Handler,
+		},
+		{
+			MethodName: "GetCategories",
+			Handler:    _MenuService_GetCategories_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "menu/v1/menu.proto",
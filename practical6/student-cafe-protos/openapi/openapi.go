@@ -0,0 +1,26 @@
+// Package openapi holds the OpenAPI 3 description of the services' HTTP
+// mappings, generated into openapi.yaml from their google.api.http
+// annotations by `make generate`.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML is the OpenAPI 3 document as generated
+//
+//go:embed openapi.yaml
+var YAML []byte
+
+// JSON returns the OpenAPI 3 document encoded as JSON
+func JSON() ([]byte, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(YAML, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	return json.Marshal(doc)
+}
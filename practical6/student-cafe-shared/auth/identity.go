@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Roles carried in tokens and identities
+const (
+	RoleStudent   = "student"
+	RoleCafeOwner = "cafe_owner"
+)
+
+// RoleFor returns the role of a user
+func RoleFor(isCafeOwner bool) string {
+	if isCafeOwner {
+		return RoleCafeOwner
+	}
+	return RoleStudent
+}
+
+// gRPC metadata keys carrying the caller's identity between services
+const (
+	UserIDMetadataKey = "x-user-id"
+	RoleMetadataKey   = "x-user-role"
+)
+
+// Identity is an authenticated caller
+type Identity struct {
+	UserID uint32
+	Role   string
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of ctx carrying identity
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller's identity; ok is false for anonymous
+// callers
+func FromContext(ctx context.Context) (identity Identity, ok bool) {
+	identity, ok = ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// UnaryServerInterceptor reads the identity forwarded by the api-gateway from
+// the request metadata into the handler's context. Requests without identity
+// metadata are passed through anonymously; malformed identities are rejected.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := incomingIdentity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := incomingIdentity(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor forwards the identity in the call's context as
+// outgoing metadata, so downstream services see the original caller
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingIdentity(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor is UnaryClientInterceptor for streaming RPCs
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingIdentity(ctx), desc, cc, method, opts...)
+	}
+}
+
+func incomingIdentity(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	userIDs := md.Get(UserIDMetadataKey)
+	if len(userIDs) == 0 {
+		return ctx, nil
+	}
+
+	userID, err := strconv.ParseUint(userIDs[0], 10, 32)
+	if err != nil || userID == 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid %s metadata", UserIDMetadataKey)
+	}
+
+	identity := Identity{UserID: uint32(userID)}
+	if roles := md.Get(RoleMetadataKey); len(roles) > 0 {
+		identity.Role = roles[0]
+	}
+	return NewContext(ctx, identity), nil
+}
+
+func outgoingIdentity(ctx context.Context) context.Context {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		UserIDMetadataKey, strconv.FormatUint(uint64(identity.UserID), 10),
+		RoleMetadataKey, identity.Role,
+	)
+}
+
+// identityStream overrides a server stream's context
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
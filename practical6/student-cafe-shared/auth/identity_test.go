@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.TestService/Call"}
+
+	call := func(md metadata.MD) (Identity, bool, error) {
+		var identity Identity
+		var ok bool
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			identity, ok = FromContext(ctx)
+			return nil, nil
+		})
+		return identity, ok, err
+	}
+
+	t.Run("identity metadata is read into the context", func(t *testing.T) {
+		identity, ok, err := call(metadata.Pairs(UserIDMetadataKey, "7", RoleMetadataKey, RoleStudent))
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, Identity{UserID: 7, Role: RoleStudent}, identity)
+	})
+
+	t.Run("requests without identity are anonymous", func(t *testing.T) {
+		_, ok, err := call(metadata.MD{})
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("malformed user IDs are rejected", func(t *testing.T) {
+		_, _, err := call(metadata.Pairs(UserIDMetadataKey, "abc"))
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, _, err = call(metadata.Pairs(UserIDMetadataKey, "0"))
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+
+	outgoing := func(ctx context.Context) metadata.MD {
+		var md metadata.MD
+		err := interceptor(ctx, "/test.v1.TestService/Call", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			})
+		require.NoError(t, err)
+		return md
+	}
+
+	md := outgoing(NewContext(context.Background(), Identity{UserID: 7, Role: RoleCafeOwner}))
+	assert.Equal(t, []string{"7"}, md.Get(UserIDMetadataKey))
+	assert.Equal(t, []string{RoleCafeOwner}, md.Get(RoleMetadataKey))
+
+	md = outgoing(context.Background())
+	assert.Empty(t, md.Get(UserIDMetadataKey))
+}
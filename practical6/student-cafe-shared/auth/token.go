@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// Issuer is the iss claim of every Student Cafe token
+	Issuer = "student-cafe"
+
+	// DefaultAccessTokenTTL is how long access tokens are valid by default
+	DefaultAccessTokenTTL = 15 * time.Minute
+	// DefaultRefreshTokenTTL is how long refresh tokens are valid by default
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// TokenType distinguishes access tokens, sent with every API request, from
+// refresh tokens, which are only accepted by the RefreshToken RPC
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, expired, signed
+// with another key or of the wrong type
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the JWT claims of a Student Cafe token. The subject is the user
+// ID.
+type Claims struct {
+	Role      string    `json:"role"`
+	TokenType TokenType `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is the result of a successful login or refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresIn is the lifetime of the access token
+	ExpiresIn time.Duration
+}
+
+// TokenIssuer signs tokens with a private key
+type TokenIssuer struct {
+	key ed25519.PrivateKey
+
+	// AccessTokenTTL and RefreshTokenTTL default to DefaultAccessTokenTTL and
+	// DefaultRefreshTokenTTL when zero
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// NewTokenIssuer creates an issuer signing with key
+func NewTokenIssuer(key ed25519.PrivateKey) *TokenIssuer {
+	return &TokenIssuer{key: key}
+}
+
+// Issue signs a new access and refresh token for identity
+func (i *TokenIssuer) Issue(identity Identity) (TokenPair, error) {
+	accessTTL := i.AccessTokenTTL
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTokenTTL
+	}
+	refreshTTL := i.RefreshTokenTTL
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTokenTTL
+	}
+
+	now := time.Now()
+	access, err := i.sign(identity, AccessToken, now, accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := i.sign(identity, RefreshToken, now, refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresIn: accessTTL}, nil
+}
+
+func (i *TokenIssuer) sign(identity Identity, tokenType TokenType, now time.Time, ttl time.Duration) (string, error) {
+	claims := Claims{
+		Role:      identity.Role,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   strconv.FormatUint(uint64(identity.UserID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(i.key)
+}
+
+// Verifier returns a verifier for the tokens this issuer signs
+func (i *TokenIssuer) Verifier() *TokenVerifier {
+	return NewTokenVerifier(i.key.Public().(ed25519.PublicKey))
+}
+
+// TokenVerifier checks tokens against a public key
+type TokenVerifier struct {
+	key ed25519.PublicKey
+}
+
+// NewTokenVerifier creates a verifier for tokens signed with key's private
+// half
+func NewTokenVerifier(key ed25519.PublicKey) *TokenVerifier {
+	return &TokenVerifier{key: key}
+}
+
+// Verify checks a token's signature, issuer, expiry and type and returns the
+// identity it was issued for
+func (v *TokenVerifier) Verify(token string, tokenType TokenType) (Identity, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.TokenType != tokenType {
+		return Identity{}, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || userID == 0 {
+		return Identity{}, ErrInvalidToken
+	}
+
+	return Identity{UserID: uint32(userID), Role: claims.Role}, nil
+}
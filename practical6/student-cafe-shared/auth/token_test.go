@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIssuer writes a fresh key pair to a temporary directory and loads
+// it back, the way the services read their keys
+func newTestIssuer(t *testing.T) (*TokenIssuer, *TokenVerifier) {
+	t.Helper()
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "jwt-private.pem")
+	publicPath := filepath.Join(dir, "jwt-public.pem")
+	require.NoError(t, WriteKeyPair(privatePath, publicPath))
+
+	privateKey, err := LoadPrivateKey(privatePath)
+	require.NoError(t, err)
+	publicKey, err := LoadPublicKey(publicPath)
+	require.NoError(t, err)
+
+	return NewTokenIssuer(privateKey), NewTokenVerifier(publicKey)
+}
+
+func TestLoadKeys(t *testing.T) {
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "jwt-private.pem")
+	publicPath := filepath.Join(dir, "jwt-public.pem")
+	require.NoError(t, WriteKeyPair(privatePath, publicPath))
+
+	_, err := LoadPrivateKey(publicPath)
+	assert.Error(t, err, "a public key is not a private key")
+
+	_, err = LoadPublicKey(filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	issuer, verifier := newTestIssuer(t)
+	identity := Identity{UserID: 42, Role: RoleCafeOwner}
+
+	tokens, err := issuer.Issue(identity)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAccessTokenTTL, tokens.ExpiresIn)
+
+	t.Run("tokens verify as their own type", func(t *testing.T) {
+		got, err := verifier.Verify(tokens.AccessToken, AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, identity, got)
+
+		got, err = verifier.Verify(tokens.RefreshToken, RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, identity, got)
+
+		// The issuer's own verifier accepts them too
+		_, err = issuer.Verifier().Verify(tokens.AccessToken, AccessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("token types are not interchangeable", func(t *testing.T) {
+		_, err := verifier.Verify(tokens.RefreshToken, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		_, err = verifier.Verify(tokens.AccessToken, RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("tokens from another key are rejected", func(t *testing.T) {
+		_, otherVerifier := newTestIssuer(t)
+		_, err := otherVerifier.Verify(tokens.AccessToken, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed and expired tokens are rejected", func(t *testing.T) {
+		_, err := verifier.Verify("not-a-jwt", AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		expired, err := issuer.sign(identity, AccessToken, time.Now(), -time.Minute)
+		require.NoError(t, err)
+		_, err = verifier.Verify(expired, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
@@ -0,0 +1,117 @@
+// Package authz enforces role-based access to gRPC methods.
+//
+// Each service declares a Policy mapping full method names to Rules and
+// installs it with UnaryServerInterceptor (and StreamServerInterceptor for
+// streaming RPCs), after auth.UnaryServerInterceptor has read the caller's
+// identity. Methods missing from the policy are open to every caller, so
+// public reads and service-to-service calls need no entry.
+package authz
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/auth"
+)
+
+// Rule decides whether the caller may make a request. It returns nil to
+// allow the call and a gRPC status error, usually PermissionDenied, to
+// reject it. Rules may narrow req, e.g. to scope a list to the caller.
+type Rule func(ctx context.Context, caller auth.Identity, req any) error
+
+// Policy maps full gRPC method names, e.g. "/menu.v1.MenuService/CreateMenuItem",
+// to the rule guarding them
+type Policy map[string]Rule
+
+// OwnerFunc returns the ID of the user who owns the resource a request
+// refers to
+type OwnerFunc func(ctx context.Context, req any) (uint32, error)
+
+// RequireRole allows callers with one of roles
+func RequireRole(roles ...string) Rule {
+	return func(ctx context.Context, caller auth.Identity, req any) error {
+		if !slices.Contains(roles, caller.Role) {
+			return status.Errorf(codes.PermissionDenied, "requires the %s role", strings.Join(roles, " or "))
+		}
+		return nil
+	}
+}
+
+// RoleOrOwner allows callers with role, and any other caller who owns the
+// resource the request refers to
+func RoleOrOwner(role string, owner OwnerFunc) Rule {
+	return func(ctx context.Context, caller auth.Identity, req any) error {
+		if caller.Role == role {
+			return nil
+		}
+		ownerID, err := owner(ctx, req)
+		if err != nil {
+			return err
+		}
+		if ownerID != caller.UserID {
+			return status.Errorf(codes.PermissionDenied, "not allowed to access another user's resources")
+		}
+		return nil
+	}
+}
+
+// authorize applies the policy's rule for method, if any
+func (p Policy) authorize(ctx context.Context, method string, req any) error {
+	rule, ok := p[method]
+	if !ok {
+		return nil
+	}
+
+	caller, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "authentication required")
+	}
+	return rule(ctx, caller, req)
+}
+
+// UnaryServerInterceptor rejects unary calls the policy does not allow
+func UnaryServerInterceptor(policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := policy.authorize(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streaming calls the policy does not allow.
+// The rule sees the first request message, so it suits server-streaming
+// RPCs.
+func StreamServerInterceptor(policy Policy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := policy[info.FullMethod]; !ok {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, policy: policy, method: info.FullMethod})
+	}
+}
+
+// authorizedStream checks the first message received on a stream
+type authorizedStream struct {
+	grpc.ServerStream
+	policy     Policy
+	method     string
+	authorized bool
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		if err := s.policy.authorize(s.Context(), s.method, m); err != nil {
+			return err
+		}
+		s.authorized = true
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/auth"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	const (
+		ownerOnly = "/test.v1.TestService/OwnerOnly"
+		ownOrder  = "/test.v1.TestService/OwnOrder"
+		open      = "/test.v1.TestService/Open"
+	)
+
+	// Requests are user IDs, so each request owns itself
+	policy := Policy{
+		ownerOnly: RequireRole(auth.RoleCafeOwner),
+		ownOrder: RoleOrOwner(auth.RoleCafeOwner, func(ctx context.Context, req any) (uint32, error) {
+			return req.(uint32), nil
+		}),
+	}
+	interceptor := UnaryServerInterceptor(policy)
+
+	call := func(ctx context.Context, method string, req any) codes.Code {
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return status.Code(err)
+	}
+
+	owner := auth.NewContext(context.Background(), auth.Identity{UserID: 1, Role: auth.RoleCafeOwner})
+	student := auth.NewContext(context.Background(), auth.Identity{UserID: 2, Role: auth.RoleStudent})
+	anonymous := context.Background()
+
+	assert.Equal(t, codes.OK, call(owner, ownerOnly, nil))
+	assert.Equal(t, codes.PermissionDenied, call(student, ownerOnly, nil))
+	assert.Equal(t, codes.Unauthenticated, call(anonymous, ownerOnly, nil))
+
+	assert.Equal(t, codes.OK, call(owner, ownOrder, uint32(2)), "owners may access any resource")
+	assert.Equal(t, codes.OK, call(student, ownOrder, uint32(2)))
+	assert.Equal(t, codes.PermissionDenied, call(student, ownOrder, uint32(3)))
+
+	assert.Equal(t, codes.OK, call(anonymous, open, nil), "methods outside the policy are open")
+}
@@ -0,0 +1,55 @@
+// Command devcerts writes a local CA and a certificate for each Student Cafe
+// service for running the services with mutual TLS in development:
+// ca.pem, and <service>.pem with <service>-key.pem for every service. An
+// existing CA in the directory is reused, so running it again renews the
+// service certificates, which running services pick up without a restart.
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"student-cafe-shared/mtls"
+)
+
+func main() {
+	dir := flag.String("dir", "certs", "directory to write the certificates to")
+	services := flag.String("services", "api-gateway,user-service,menu-service,order-service",
+		"comma-separated names of the services to issue certificates for")
+	flag.Parse()
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatalf("Failed to create %s: %v", *dir, err)
+	}
+
+	caCertPath := filepath.Join(*dir, "ca.pem")
+	caKeyPath := filepath.Join(*dir, "ca-key.pem")
+	ca, err := mtls.LoadDevCA(caCertPath, caKeyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if ca, err = mtls.NewDevCA(); err == nil {
+			err = ca.Write(caCertPath, caKeyPath)
+		}
+		if err != nil {
+			log.Fatalf("Failed to create CA: %v", err)
+		}
+		log.Printf("Wrote %s", caCertPath)
+	} else if err != nil {
+		log.Fatalf("Failed to load CA: %v", err)
+	}
+
+	for _, service := range strings.Split(*services, ",") {
+		certPath := filepath.Join(*dir, service+".pem")
+		keyPath := filepath.Join(*dir, service+"-key.pem")
+		// Services reach each other by their Compose names, and developers
+		// reach them on localhost
+		if err := ca.Issue(certPath, keyPath, service, "localhost"); err != nil {
+			log.Fatalf("Failed to issue certificate for %s: %v", service, err)
+		}
+		log.Printf("Wrote %s and %s", certPath, keyPath)
+	}
+}
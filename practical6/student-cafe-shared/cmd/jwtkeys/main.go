@@ -0,0 +1,29 @@
+// Command jwtkeys writes a new Ed25519 key pair for signing Student Cafe
+// access tokens: jwt-private.pem for user-service and jwt-public.pem for the
+// api-gateway.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"student-cafe-shared/auth"
+)
+
+func main() {
+	dir := flag.String("dir", "keys", "directory to write the key pair to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatalf("Failed to create %s: %v", *dir, err)
+	}
+
+	privateKeyPath := filepath.Join(*dir, "jwt-private.pem")
+	publicKeyPath := filepath.Join(*dir, "jwt-public.pem")
+	if err := auth.WriteKeyPair(privateKeyPath, publicKeyPath); err != nil {
+		log.Fatalf("Failed to write key pair: %v", err)
+	}
+	log.Printf("Wrote %s and %s", privateKeyPath, publicKeyPath)
+}
@@ -0,0 +1,116 @@
+// Package dberr translates database errors into gRPC status errors.
+//
+// Services run on Postgres and their tests on SQLite, so errors are
+// classified from both drivers' error codes as well as GORM's own sentinel
+// errors. Anything unrecognised is an Internal error. The SQLite driver needs
+// cgo, which the service images are built without, so its codes are only
+// classified in cgo builds.
+package dberr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Postgres SQLSTATE codes, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgLockNotAvailable     = "55P03"
+	pgQueryCanceled        = "57014"
+)
+
+// sqlState is implemented by the Postgres drivers' error types
+type sqlState interface {
+	SQLState() string
+}
+
+// Code returns the gRPC code that best describes a database error:
+//
+//   - NotFound for gorm.ErrRecordNotFound
+//   - AlreadyExists for unique constraint violations
+//   - FailedPrecondition for foreign key violations
+//   - Aborted for serialization failures, deadlocks and locked databases,
+//     which are safe to retry
+//   - DeadlineExceeded and Canceled for timeouts and cancelled contexts
+//   - Internal for everything else
+func Code(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return codes.NotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return codes.AlreadyExists
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return codes.FailedPrecondition
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+
+	var pgErr sqlState
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case pgUniqueViolation:
+			return codes.AlreadyExists
+		case pgForeignKeyViolation:
+			return codes.FailedPrecondition
+		case pgSerializationFailure, pgDeadlockDetected, pgLockNotAvailable:
+			return codes.Aborted
+		case pgQueryCanceled:
+			// Raised when statement_timeout expires
+			return codes.DeadlineExceeded
+		}
+	}
+
+	if code, ok := sqliteCode(err); ok {
+		return code
+	}
+
+	return codes.Internal
+}
+
+// descriptions replace the driver's error text in the messages of
+// recognised errors, so clients never see SQL or constraint names
+var descriptions = map[codes.Code]string{
+	codes.NotFound:           "record not found",
+	codes.AlreadyExists:      "record already exists",
+	codes.FailedPrecondition: "referenced record does not exist",
+	codes.Aborted:            "conflicting concurrent update, retry the request",
+	codes.DeadlineExceeded:   "database query timed out",
+	codes.Canceled:           "request cancelled",
+}
+
+// Error converts a database error into a status error with the code
+// returned by Code. The formatted message says what failed, e.g. "failed to
+// create user", and is followed by a description of recognised causes.
+// Unrecognised causes may hold SQL or driver details, so they are logged
+// here and the message is all the client sees.
+func Error(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	code := Code(err)
+	msg := fmt.Sprintf(format, args...)
+	if desc, ok := descriptions[code]; ok {
+		return status.Errorf(code, "%s: %s", msg, desc)
+	}
+	slog.Error("Database error", "message", msg, "error", err)
+	return status.Error(code, msg)
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation
+func IsUniqueViolation(err error) bool {
+	return Code(err) == codes.AlreadyExists
+}
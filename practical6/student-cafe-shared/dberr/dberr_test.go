@@ -0,0 +1,123 @@
+package dberr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type account struct {
+	ID    uint
+	Email string `gorm:"unique"`
+}
+
+type session struct {
+	ID        uint
+	AccountID uint
+	Account   account
+}
+
+// exercise runs the same statements against any dialect and returns the
+// errors they produce
+func exercise(t *testing.T, db *gorm.DB) (duplicate, missingParent, notFound error) {
+	t.Helper()
+	require.NoError(t, db.AutoMigrate(&account{}, &session{}))
+
+	require.NoError(t, db.Create(&account{Email: "alice@example.com"}).Error)
+	duplicate = db.Create(&account{Email: "alice@example.com"}).Error
+	missingParent = db.Create(&session{AccountID: 9999}).Error
+	notFound = db.First(&account{}, 9999).Error
+	return duplicate, missingParent, notFound
+}
+
+func TestCode_SQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(path+"?_foreign_keys=on"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	duplicate, missingParent, notFound := exercise(t, db)
+	assert.Equal(t, codes.AlreadyExists, Code(duplicate))
+	assert.Equal(t, codes.FailedPrecondition, Code(missingParent))
+	assert.Equal(t, codes.NotFound, Code(notFound))
+
+	t.Run("locked database", func(t *testing.T) {
+		other, err := gorm.Open(sqlite.Open(path+"?_busy_timeout=0"), &gorm.Config{Logger: logger.Discard})
+		require.NoError(t, err)
+
+		tx := db.Begin()
+		require.NoError(t, tx.Exec("INSERT INTO accounts (email) VALUES (?)", "bob@example.com").Error)
+		defer tx.Rollback()
+
+		err = other.Create(&account{Email: "carol@example.com"}).Error
+		assert.Equal(t, codes.Aborted, Code(err))
+	})
+}
+
+func TestCode_Postgres(t *testing.T) {
+	// Errors as returned by the pgx driver
+	cases := map[string]codes.Code{
+		"23505": codes.AlreadyExists,
+		"23503": codes.FailedPrecondition,
+		"40001": codes.Aborted,
+		"40P01": codes.Aborted,
+		"55P03": codes.Aborted,
+		"57014": codes.DeadlineExceeded,
+		"42P01": codes.Internal,
+	}
+	for sqlState, want := range cases {
+		err := fmt.Errorf("query failed: %w", &pgconn.PgError{Code: sqlState})
+		assert.Equal(t, want, Code(err), sqlState)
+	}
+
+	t.Run("live database", func(t *testing.T) {
+		dsn := os.Getenv("POSTGRES_TEST_DSN")
+		if dsn == "" {
+			t.Skip("POSTGRES_TEST_DSN not set")
+		}
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Migrator().DropTable(&session{}, &account{}) })
+
+		duplicate, missingParent, notFound := exercise(t, db)
+		assert.Equal(t, codes.AlreadyExists, Code(duplicate))
+		assert.Equal(t, codes.FailedPrecondition, Code(missingParent))
+		assert.Equal(t, codes.NotFound, Code(notFound))
+	})
+}
+
+func TestCode_GORMAndContext(t *testing.T) {
+	assert.Equal(t, codes.OK, Code(nil))
+	assert.Equal(t, codes.AlreadyExists, Code(gorm.ErrDuplicatedKey))
+	assert.Equal(t, codes.FailedPrecondition, Code(gorm.ErrForeignKeyViolated))
+	assert.Equal(t, codes.DeadlineExceeded, Code(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+	assert.Equal(t, codes.Canceled, Code(context.Canceled))
+	assert.Equal(t, codes.Internal, Code(fmt.Errorf("connection refused")))
+}
+
+func TestError(t *testing.T) {
+	assert.NoError(t, Error(nil, "failed to create user"))
+
+	err := Error(&pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "idx_users_email"`},
+		"failed to create user")
+	st := status.Convert(err)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	assert.Equal(t, "failed to create user: record already exists", st.Message())
+	assert.NotContains(t, st.Message(), "idx_users_email")
+
+	err = Error(fmt.Errorf("connection refused"), "failed to get user %d", 7)
+	st = status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "failed to get user 7", st.Message())
+}
@@ -0,0 +1,29 @@
+//go:build cgo
+
+package dberr
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+)
+
+// sqliteCode returns the gRPC code for a SQLite error, and false if err is
+// not one or its code is not recognised
+func sqliteCode(err error) (codes.Code, bool) {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return codes.Unknown, false
+	}
+	switch {
+	case sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique,
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey:
+		return codes.AlreadyExists, true
+	case sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey:
+		return codes.FailedPrecondition, true
+	case sqliteErr.Code == sqlite3.ErrBusy, sqliteErr.Code == sqlite3.ErrLocked:
+		return codes.Aborted, true
+	}
+	return codes.Unknown, false
+}
@@ -0,0 +1,11 @@
+//go:build !cgo
+
+package dberr
+
+import "google.golang.org/grpc/codes"
+
+// sqliteCode recognises no errors without cgo, as the SQLite driver cannot
+// be built
+func sqliteCode(err error) (codes.Code, bool) {
+	return codes.Unknown, false
+}
@@ -0,0 +1,34 @@
+// Package fieldmask interprets google.protobuf.FieldMask values on update RPCs.
+package fieldmask
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Paths returns the field paths an update should change. An empty or missing
+// mask selects every updatable field, so a request without a mask replaces
+// the whole resource. Paths outside updatable and duplicates are rejected.
+func Paths(mask *fieldmaskpb.FieldMask, updatable []string) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return updatable, nil
+	}
+
+	allowed := make(map[string]bool, len(updatable))
+	for _, path := range updatable {
+		allowed[path] = true
+	}
+
+	seen := make(map[string]bool, len(mask.Paths))
+	for _, path := range mask.Paths {
+		if !allowed[path] {
+			return nil, fmt.Errorf("field %q cannot be updated", path)
+		}
+		if seen[path] {
+			return nil, fmt.Errorf("field %q appears more than once in update_mask", path)
+		}
+		seen[path] = true
+	}
+	return mask.Paths, nil
+}
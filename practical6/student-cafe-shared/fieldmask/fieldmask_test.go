@@ -0,0 +1,37 @@
+package fieldmask
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestPaths(t *testing.T) {
+	updatable := []string{"name", "email", "is_cafe_owner"}
+
+	t.Run("empty mask selects every field", func(t *testing.T) {
+		paths, err := Paths(nil, updatable)
+		require.NoError(t, err)
+		assert.Equal(t, updatable, paths)
+
+		paths, err = Paths(&fieldmaskpb.FieldMask{}, updatable)
+		require.NoError(t, err)
+		assert.Equal(t, updatable, paths)
+	})
+
+	t.Run("listed paths are returned in order", func(t *testing.T) {
+		paths, err := Paths(&fieldmaskpb.FieldMask{Paths: []string{"email", "name"}}, updatable)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"email", "name"}, paths)
+	})
+
+	t.Run("unknown and duplicate paths are rejected", func(t *testing.T) {
+		_, err := Paths(&fieldmaskpb.FieldMask{Paths: []string{"id"}}, updatable)
+		assert.Error(t, err)
+
+		_, err = Paths(&fieldmaskpb.FieldMask{Paths: []string{"name", "name"}}, updatable)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+// Package grpcerr builds gRPC status errors that carry google.rpc error
+// details, so clients such as the api-gateway can tell callers exactly which
+// field or resource a request failed on.
+package grpcerr
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is the ErrorInfo domain of every Student Cafe error
+const Domain = "student-cafe"
+
+// New returns a status error with code, message and details
+func New(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if len(details) == 0 {
+		return st.Err()
+	}
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		// Details only fail to marshal on programming errors; the bare
+		// status is still the right answer for the caller
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// InvalidField returns an InvalidArgument error naming one bad request
+// field. The formatted message is both the status message and the field
+// violation's description.
+func InvalidField(field, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	return New(codes.InvalidArgument, msg, BadRequest(Violation(field, msg)))
+}
+
+// BadRequest returns a BadRequest detail listing field violations
+func BadRequest(violations ...*errdetails.BadRequest_FieldViolation) *errdetails.BadRequest {
+	return &errdetails.BadRequest{FieldViolations: violations}
+}
+
+// Violation describes why one request field is invalid. Nested and repeated
+// fields use paths such as "items[1].menu_item_id".
+func Violation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
+
+// Info returns an ErrorInfo detail with a machine-readable reason, e.g.
+// "INSUFFICIENT_STOCK", and metadata identifying the resource involved
+func Info(reason string, metadata map[string]string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: Domain, Metadata: metadata}
+}
+
+// Wrap prefixes the message of a status error from another service while
+// keeping its code and details
+func Wrap(err error, prefix string) error {
+	st := status.Convert(err)
+	p := st.Proto()
+	p.Message = prefix + ": " + p.Message
+	return status.FromProto(p).Err()
+}
@@ -0,0 +1,40 @@
+package grpcerr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInvalidField(t *testing.T) {
+	st := status.Convert(InvalidField("items[1].quantity", "quantity must be positive, got %d", 0))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "quantity must be positive, got 0", st.Message())
+
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 1)
+	assert.Equal(t, "items[1].quantity", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, st.Message(), badRequest.FieldViolations[0].Description)
+}
+
+func TestWrap(t *testing.T) {
+	err := New(codes.FailedPrecondition, "menu item 4 is out of stock",
+		Info("INSUFFICIENT_STOCK", map[string]string{"menu_item_id": "4"}))
+
+	st := status.Convert(Wrap(err, "failed to reserve stock"))
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, "failed to reserve stock: menu item 4 is out of stock", st.Message())
+
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "INSUFFICIENT_STOCK", info.Reason)
+	assert.Equal(t, Domain, info.Domain)
+	assert.Equal(t, "4", info.Metadata["menu_item_id"])
+}
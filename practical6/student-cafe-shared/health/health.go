@@ -0,0 +1,175 @@
+// Package health publishes a service's readiness through the standard
+// grpc.health.v1 service.
+//
+// A Checker runs a set of named checks, such as a database ping or a call
+// to a dependency's own health service, on an interval. The service is
+// SERVING while every check passes and NOT_SERVING otherwise, and stays
+// NOT_SERVING once Shutdown is called so clients stop routing to it while
+// in-flight RPCs drain.
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// DefaultInterval is how often checks run when Interval is zero
+	DefaultInterval = 5 * time.Second
+	// DefaultTimeout bounds each check when Timeout is zero
+	DefaultTimeout = 2 * time.Second
+)
+
+// Check returns an error if a dependency is unusable
+type Check func(ctx context.Context) error
+
+// Pinger is implemented by *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Database checks that db accepts connections
+func Database(db Pinger) Check {
+	return db.PingContext
+}
+
+// Remote checks that the service at the other end of conn reports SERVING
+// through its own health service. An empty service name asks for the
+// server's overall health.
+func Remote(conn grpc.ClientConnInterface, service string) Check {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("status %s", resp.GetStatus())
+		}
+		return nil
+	}
+}
+
+// namedCheck is a Check with the name used in logs
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker keeps the health status of a gRPC server up to date
+type Checker struct {
+	// Interval is how often the checks run; DefaultInterval is used when zero
+	Interval time.Duration
+	// Timeout bounds each check; DefaultTimeout is used when zero
+	Timeout time.Duration
+
+	server   *grpchealth.Server
+	services []string
+	checks   []namedCheck
+
+	mu       sync.Mutex
+	failing  string // name of the first failing check, empty when healthy
+	shutdown bool
+}
+
+// NewChecker returns a Checker reporting the health of the named gRPC
+// services, e.g. "user.v1.UserService", and of the server as a whole. They
+// are NOT_SERVING until the checks first pass.
+func NewChecker(services ...string) *Checker {
+	c := &Checker{
+		server:   grpchealth.NewServer(),
+		services: append([]string{""}, services...),
+		failing:  "startup",
+	}
+	c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+// Add registers a check. It must be called before Run.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Register adds the grpc.health.v1 service to s
+func (c *Checker) Register(s grpc.ServiceRegistrar) {
+	healthpb.RegisterHealthServer(s, c.server)
+}
+
+// Run checks health every Interval until ctx is done
+func (c *Checker) Run(ctx context.Context) {
+	interval := c.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.CheckNow(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow runs every check once and updates the published status. It
+// returns the first error.
+func (c *Checker) CheckNow(ctx context.Context) error {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	var failing string
+	var failure error
+	for _, nc := range c.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := nc.check(checkCtx)
+		cancel()
+		if err != nil {
+			failing = nc.name
+			failure = fmt.Errorf("%s: %w", nc.name, err)
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdown {
+		return failure
+	}
+	if failing != c.failing {
+		if failure != nil {
+			slog.Warn("Health check failed, not serving", "error", failure)
+			c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		} else {
+			slog.Info("Health checks passing, serving")
+			c.setStatus(healthpb.HealthCheckResponse_SERVING)
+		}
+		c.failing = failing
+	}
+	return failure
+}
+
+// Shutdown marks every service NOT_SERVING for good
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shutdown = true
+	c.server.Shutdown()
+}
+
+func (c *Checker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+}
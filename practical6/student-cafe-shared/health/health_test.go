@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const service = "user.v1.UserService"
+
+// serve registers c on a bufconn server and returns a client connection
+func serve(t *testing.T, c *Checker) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	c.Register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func servingStatus(t *testing.T, conn *grpc.ClientConn, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.GetStatus()
+}
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+	var dbUp atomic.Bool
+
+	c := NewChecker(service)
+	c.Add("database", func(context.Context) error {
+		if !dbUp.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	conn := serve(t, c)
+
+	// Nothing is served before the checks have passed
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, conn, ""))
+
+	err := c.CheckNow(ctx)
+	assert.ErrorContains(t, err, "database: connection refused")
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, conn, service))
+
+	dbUp.Store(true)
+	require.NoError(t, c.CheckNow(ctx))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, conn, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, conn, service))
+
+	// Shutdown wins over passing checks
+	c.Shutdown()
+	c.CheckNow(ctx)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, conn, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, conn, service))
+}
+
+func TestChecker_Run(t *testing.T) {
+	c := NewChecker(service)
+	c.Interval = 10 * time.Millisecond
+	var calls atomic.Int32
+	c.Add("counter", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+	conn := serve(t, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go c.Run(ctx)
+	defer cancel()
+
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, conn, service))
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	c := NewChecker()
+	c.Timeout = 10 * time.Millisecond
+	c.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, c.CheckNow(context.Background()), context.DeadlineExceeded)
+}
+
+func TestDatabase(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	check := Database(sqlDB)
+	assert.NoError(t, check(context.Background()))
+
+	require.NoError(t, sqlDB.Close())
+	assert.Error(t, check(context.Background()))
+}
+
+func TestRemote(t *testing.T) {
+	c := NewChecker(service)
+	conn := serve(t, c)
+	check := Remote(conn, service)
+
+	assert.ErrorContains(t, check(context.Background()), "NOT_SERVING")
+	require.NoError(t, c.CheckNow(context.Background()))
+	assert.NoError(t, check(context.Background()))
+	assert.Error(t, Remote(conn, "unknown.Service")(context.Background()))
+}
@@ -0,0 +1,117 @@
+// Package logging writes the Student Cafe services' logs as JSON lines with
+// log/slog and ties every record to the request that caused it.
+//
+// The api-gateway gives each HTTP request an ID, or keeps the one the
+// caller sent in X-Request-ID, and the client interceptors forward it to
+// the backend services in gRPC metadata. Records logged with a context that
+// carries the ID, e.g. through slog.InfoContext, include it as request_id,
+// so a gateway log line and the order-service lines it caused can be found
+// together. Records also carry the trace_id of the active span.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RequestIDHeader is the HTTP header carrying a request ID
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDMetadataKey is the gRPC metadata key carrying a request ID
+	RequestIDMetadataKey = "x-request-id"
+)
+
+// maxRequestIDLength bounds the request IDs accepted from callers
+const maxRequestIDLength = 128
+
+// Setup makes a JSON logger writing to stdout the default for both slog and
+// the standard log package. Every record names service, and records below
+// the LOG_LEVEL environment variable ("debug", "info", "warn" or "error",
+// default "info") are dropped.
+func Setup(service string) error {
+	var level slog.Level
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL %q", value)
+		}
+	}
+	slog.SetDefault(slog.New(NewHandler(os.Stdout, level)).With("service", service))
+	return nil
+}
+
+// NewHandler returns a handler writing JSON records at or above level to w,
+// adding the request and trace IDs found in each record's context
+func NewHandler(w io.Writer, level slog.Leveler) slog.Handler {
+	return contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})}
+}
+
+// contextHandler adds the IDs stored in a record's context to the record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Fatal logs msg at error level and exits, for failures a service cannot
+// start or keep running without
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random request ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether a request ID received from a caller is
+// safe to log and forward: at most 128 letters, digits, '-', '_', '.' or
+// ':'
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			r == '-' || r == '_' || r == '.' || r == ':')
+	}) == -1
+}
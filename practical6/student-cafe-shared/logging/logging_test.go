@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// captureLogs makes the default logger write JSON into the returned buffer
+// for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(NewHandler(&buf, slog.LevelDebug)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// records decodes the JSON records in buf
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		out = append(out, record)
+	}
+	return out
+}
+
+func TestHandler(t *testing.T) {
+	buf := captureLogs(t)
+
+	slog.InfoContext(WithRequestID(context.Background(), "req-1"), "placed order", "order_id", 7)
+	slog.Info("no request")
+
+	logs := records(t, buf)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "placed order", logs[0]["msg"])
+	assert.Equal(t, "req-1", logs[0]["request_id"])
+	assert.Equal(t, 7.0, logs[0]["order_id"])
+	assert.NotContains(t, logs[1], "request_id")
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	buf := captureLogs(t)
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.TestService/Call"}
+
+	call := func(md metadata.MD, err error) string {
+		var id string
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			id = RequestID(ctx)
+			return nil, err
+		})
+		return id
+	}
+
+	t.Run("the caller's request ID is kept and logged", func(t *testing.T) {
+		buf.Reset()
+		assert.Equal(t, "req-1", call(metadata.Pairs(RequestIDMetadataKey, "req-1"), nil))
+
+		logs := records(t, buf)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "req-1", logs[0]["request_id"])
+		assert.Equal(t, "/test.v1.TestService/Call", logs[0]["method"])
+		assert.Equal(t, "OK", logs[0]["code"])
+		assert.Equal(t, "INFO", logs[0]["level"])
+	})
+
+	t.Run("missing and unsafe request IDs are replaced", func(t *testing.T) {
+		assert.Len(t, call(metadata.MD{}, nil), 32)
+		id := call(metadata.Pairs(RequestIDMetadataKey, "bad\nid"), nil)
+		assert.NotEqual(t, "bad\nid", id)
+		assert.Len(t, id, 32)
+	})
+
+	t.Run("failures are logged by severity", func(t *testing.T) {
+		buf.Reset()
+		call(metadata.MD{}, status.Error(codes.NotFound, "no such order"))
+		call(metadata.MD{}, status.Error(codes.Internal, "database down"))
+
+		logs := records(t, buf)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "WARN", logs[0]["level"])
+		assert.Equal(t, "no such order", logs[0]["error"])
+		assert.Equal(t, "ERROR", logs[1]["level"])
+	})
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+
+	outgoing := func(ctx context.Context) metadata.MD {
+		var md metadata.MD
+		err := interceptor(ctx, "/test.v1.TestService/Call", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			})
+		require.NoError(t, err)
+		return md
+	}
+
+	md := outgoing(WithRequestID(context.Background(), "req-1"))
+	assert.Equal(t, []string{"req-1"}, md.Get(RequestIDMetadataKey))
+
+	md = outgoing(context.Background())
+	assert.Empty(t, md.Get(RequestIDMetadataKey))
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("3f2a-b_c.d:1"))
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("has space"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", 129)))
+}
+
+func TestSetup(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	t.Setenv("LOG_LEVEL", "")
+	assert.NoError(t, Setup("test-service"))
+	t.Setenv("LOG_LEVEL", "debug")
+	assert.NoError(t, Setup("test-service"))
+	assert.True(t, slog.Default().Enabled(context.Background(), slog.LevelDebug))
+
+	t.Setenv("LOG_LEVEL", "loud")
+	assert.Error(t, Setup("test-service"))
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	rpcsHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "RPCs completed by the server, by method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time taken to complete RPCs, by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+
+	validationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_validation_failures_total",
+		Help: "Requests rejected as invalid, by method and reason.",
+	}, []string{"grpc_service", "grpc_method", "reason"})
+)
+
+// UnaryServerInterceptor records every unary RPC. Install it first so it
+// also sees requests rejected by the other interceptors.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records every streaming RPC when its stream
+// ends. Install it first so it also sees requests rejected by the other
+// interceptors.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+// observe records an RPC to fullMethod that started at start and ended
+// with err
+func observe(fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	st := status.Convert(err)
+	rpcsHandled.WithLabelValues(service, method, st.Code().String()).Inc()
+	rpcDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	if reason, ok := validationReason(st); ok {
+		validationFailures.WithLabelValues(service, method, reason).Inc()
+	}
+}
+
+// validationReason returns why st rejected a request as invalid: the
+// reason of its ErrorInfo detail, or its code name, e.g.
+// "INVALID_ARGUMENT", when it has none
+func validationReason(st *status.Status) (string, bool) {
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+	default:
+		return "", false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Reason != "" {
+			return info.Reason, true
+		}
+	}
+	return code.Code(st.Code()).String(), true
+}
+
+// splitMethod splits "/user.v1.UserService/GetUser" into its service and
+// method names
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", fullMethod
+	}
+	return service, method
+}
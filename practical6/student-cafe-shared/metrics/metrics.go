@@ -0,0 +1,60 @@
+// Package metrics exposes the Student Cafe services' Prometheus metrics.
+//
+// Every service serves its metrics on a dedicated HTTP port with
+// NewServer, separate from the port its clients use. The gRPC interceptors
+// record the rate, errors and duration of every RPC, and count requests
+// rejected as invalid by the reason they were rejected for. DBStats
+// publishes the state of a database connection pool. Services register
+// their own business metrics with the default Prometheus registerer.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is where NewServer serves the metrics
+const Path = "/metrics"
+
+// NewServer returns an HTTP server that serves the metrics of the default
+// Prometheus registry at Path on addr, e.g. ":9191"
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.Handler())
+	return &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+}
+
+// Serve runs srv until ctx is cancelled, then shuts it down. Scrapes are
+// short, so in-flight ones are given a second to finish.
+func Serve(ctx context.Context, srv *http.Server) error {
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// DBStats publishes the open, in-use and idle connections of db and how
+// often callers waited for one, labelled with the database name
+func DBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
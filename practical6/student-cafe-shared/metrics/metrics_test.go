@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/grpcerr"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.TestService/Call"}
+	call := func(err error) {
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			return nil, err
+		})
+	}
+	handled := func(code codes.Code) float64 {
+		return testutil.ToFloat64(rpcsHandled.WithLabelValues("test.v1.TestService", "Call", code.String()))
+	}
+	failures := func(reason string) float64 {
+		return testutil.ToFloat64(validationFailures.WithLabelValues("test.v1.TestService", "Call", reason))
+	}
+
+	call(nil)
+	call(nil)
+	call(status.Error(codes.NotFound, "missing"))
+	call(grpcerr.InvalidField("name", "name is required"))
+	call(grpcerr.New(codes.FailedPrecondition, "out of stock", grpcerr.Info("INSUFFICIENT_STOCK", nil)))
+
+	assert.Equal(t, 2.0, handled(codes.OK))
+	assert.Equal(t, 1.0, handled(codes.NotFound))
+	assert.Equal(t, 1.0, handled(codes.InvalidArgument))
+	assert.Equal(t, 1.0, handled(codes.FailedPrecondition))
+	assert.Positive(t, testutil.CollectAndCount(rpcDuration))
+
+	// Only rejected requests count as validation failures
+	assert.Equal(t, 1.0, failures("INVALID_ARGUMENT"))
+	assert.Equal(t, 1.0, failures("INSUFFICIENT_STOCK"))
+	assert.Equal(t, 0.0, failures("NOT_FOUND"))
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/test.v1.TestService/Watch"}
+	err := interceptor(nil, nil, info, func(srv any, ss grpc.ServerStream) error {
+		return status.Error(codes.Canceled, "client went away")
+	})
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(rpcsHandled.WithLabelValues("test.v1.TestService", "Watch", codes.Canceled.String())))
+}
+
+func TestNewServer(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, DBStats(db, "test_db"))
+
+	srv := httptest.NewServer(NewServer(":0").Handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `go_sql_open_connections{db_name="test_db"}`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, NewServer("127.0.0.1:0")) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after its context was cancelled")
+	}
+}
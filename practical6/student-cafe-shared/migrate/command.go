@@ -0,0 +1,152 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+)
+
+// Usage describes the migrate subcommand
+const Usage = `usage: migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [n]       roll back the last n migrations (default 1)
+  status         list migrations and whether they have been applied
+  create <name>  add an empty up/down migration pair to the source tree`
+
+// ErrUsage is returned for unknown commands and invalid arguments
+var ErrUsage = errors.New(Usage)
+
+// Command is the migrate subcommand of a service binary
+type Command struct {
+	// Migrations are the migrations built into the binary
+	Migrations fs.FS
+	// Dir is the migrations source directory that create writes to
+	Dir string
+	// Open connects to the database without checking its schema
+	Open func() (*gorm.DB, error)
+	// Out receives the command's output
+	Out io.Writer
+}
+
+// Run runs the command named by args[0]
+func (c Command) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) != 2 {
+			return ErrUsage
+		}
+		up, down, err := Create(c.Dir, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "created %s\ncreated %s\n", up, down)
+		return nil
+	case "up", "status":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+	case "down":
+		if len(args) > 2 {
+			return ErrUsage
+		}
+	default:
+		return ErrUsage
+	}
+
+	db, err := c.Open()
+	if err != nil {
+		return err
+	}
+	migrator, err := New(db, c.Migrations)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(c.Out, "applied %s\n", m)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "database is at version %d\n", migrator.Latest())
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return ErrUsage
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Fprintf(c.Out, "rolled back %s\n", m)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+// nonName matches runs of characters not allowed in migration names
+var nonName = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create adds an empty migration pair to dir, numbered after the latest
+// migration there, and returns the paths of the up and down scripts
+func Create(dir, name string) (string, string, error) {
+	name = strings.Trim(nonName.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("invalid migration name")
+	}
+
+	existing, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	m := Migration{Version: 1, Name: name}
+	if len(existing) > 0 {
+		m.Version = existing[len(existing)-1].Version + 1
+	}
+
+	up := filepath.Join(dir, m.String()+".up.sql")
+	down := filepath.Join(dir, m.String()+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+m.String()+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- Reverts "+m.String()+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
@@ -0,0 +1,324 @@
+// Package migrate applies versioned SQL migrations to a service database.
+//
+// Migrations are pairs of files named NNNN_description.up.sql and
+// NNNN_description.down.sql, embedded into the service binary. Each applied
+// migration is recorded in the schema_migrations table with a checksum of its
+// up script, so a migration edited after it ran is reported instead of being
+// silently skipped. Services call Check at startup and refuse to serve a
+// database whose schema is behind or ahead of the binary.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSchemaBehind means migrations built into the binary have not been
+	// applied to the database
+	ErrSchemaBehind = errors.New("database schema is behind the binary")
+	// ErrSchemaAhead means the database has migrations the binary does not
+	// know about, typically because a newer release migrated it
+	ErrSchemaAhead = errors.New("database schema is ahead of the binary")
+	// ErrChecksumMismatch means an applied migration was edited afterwards
+	ErrChecksumMismatch = errors.New("applied migration has been modified")
+)
+
+// fileName matches migration files, e.g. 0002_add_menu_stock.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version  uint
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // hex SHA-256 of Up
+}
+
+// Load reads the migrations in the root directory of fsys, ordered by
+// version. Every version needs both an up and a down script.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[m.Version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(script)
+			sum := sha256.Sum256(script)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migration %s has no up script", m)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %s has no down script", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations, nil
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// record is a row of the schema_migrations table
+type record struct {
+	Version   uint
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (record) TableName() string { return "schema_migrations" }
+
+// createTable is portable between Postgres and SQLite
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum VARCHAR(64) NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`
+
+// Migrator applies one service's migrations to its database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New returns a Migrator for the migrations in fsys
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the version the binary expects the database to be at
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// applied returns the recorded migrations, ordered by version
+func (m *Migrator) applied(ctx context.Context) ([]record, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.Exec(createTable).Error; err != nil {
+		return nil, fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	var records []record
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	return records, nil
+}
+
+// find returns the migration with the given version
+func (m *Migrator) find(version uint) (Migration, bool) {
+	i, ok := slices.BinarySearchFunc(m.migrations, version, func(m Migration, v uint) int {
+		return cmp.Compare(m.Version, v)
+	})
+	if !ok {
+		return Migration{}, false
+	}
+	return m.migrations[i], true
+}
+
+// verify checks that every applied migration is known to the binary and
+// unchanged since it ran
+func (m *Migrator) verify(records []record) error {
+	for _, r := range records {
+		known, ok := m.find(r.Version)
+		if !ok {
+			return fmt.Errorf("%w: migration %04d_%s is not known to this binary (latest is %04d)",
+				ErrSchemaAhead, r.Version, r.Name, m.Latest())
+		}
+		if known.Checksum != r.Checksum {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, known)
+		}
+	}
+	return nil
+}
+
+// pending returns the migrations that have not been applied, in order
+func (m *Migrator) pending(records []record) []Migration {
+	applied := make(map[uint]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// Check returns an error unless every migration has been applied unchanged
+// and the database has none the binary does not know about
+func (m *Migrator) Check(ctx context.Context) error {
+	records, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.verify(records); err != nil {
+		return err
+	}
+	if pending := m.pending(records); len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migration(s) starting at %s, run 'migrate up'",
+			ErrSchemaBehind, len(pending), pending[0])
+	}
+	return nil
+}
+
+// Up applies the pending migrations in order, each in its own transaction,
+// and returns the ones it applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	records, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(records); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.pending(records) {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&record{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("applying %s: %w", migration, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the most recently applied migrations, at most steps of
+// them, and returns the ones it rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	records, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(records); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(records) - 1; i >= 0 && len(done) < steps; i-- {
+		migration, _ := m.find(records[i].Version)
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&record{}, "version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("rolling back %s: %w", migration, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// State describes a migration in Status
+type State string
+
+const (
+	Pending  State = "pending"
+	Applied  State = "applied"
+	Modified State = "modified" // applied, but the script has changed since
+	Unknown  State = "unknown"  // applied, but not built into the binary
+)
+
+// Status is the state of one migration
+type Status struct {
+	Version   uint
+	Name      string
+	State     State
+	AppliedAt time.Time // zero while pending
+}
+
+// Status lists every migration known to the binary or recorded in the
+// database, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	records, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.pending(records) {
+		statuses = append(statuses, Status{Version: migration.Version, Name: migration.Name, State: Pending})
+	}
+	for _, r := range records {
+		s := Status{Version: r.Version, Name: r.Name, State: Applied, AppliedAt: r.AppliedAt}
+		if known, ok := m.find(r.Version); !ok {
+			s.State = Unknown
+		} else if known.Checksum != r.Checksum {
+			s.State = Modified
+		}
+		statuses = append(statuses, s)
+	}
+	slices.SortFunc(statuses, func(a, b Status) int { return cmp.Compare(a.Version, b.Version) })
+	return statuses, nil
+}
@@ -0,0 +1,226 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func file(script string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(script)}
+}
+
+// migrations returns a migration set in which each version adds a table
+func migrations(versions int) fstest.MapFS {
+	all := []struct{ name, up, down string }{
+		{"0001_create_users", "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);", "DROP TABLE users;"},
+		{"0002_create_items", "CREATE TABLE items (id INTEGER PRIMARY KEY);\nCREATE INDEX idx_items_id ON items (id);", "DROP TABLE items;"},
+		{"0003_add_users_name", "ALTER TABLE users ADD COLUMN name TEXT;\nUPDATE users SET name = email;", "ALTER TABLE users DROP COLUMN name;"},
+	}
+	fsys := fstest.MapFS{}
+	for _, m := range all[:versions] {
+		fsys[m.name+".up.sql"] = file(m.up)
+		fsys[m.name+".down.sql"] = file(m.down)
+	}
+	return fsys
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	return db
+}
+
+func newMigrator(t *testing.T, db *gorm.DB, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+	m, err := New(db, fsys)
+	require.NoError(t, err)
+	return m
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("ordered by version", func(t *testing.T) {
+		fsys := migrations(3)
+		fsys["0010_later.up.sql"] = file("SELECT 1;")
+		fsys["0010_later.down.sql"] = file("SELECT 1;")
+
+		loaded, err := Load(fsys)
+		require.NoError(t, err)
+		var names []string
+		for _, m := range loaded {
+			names = append(names, m.String())
+		}
+		assert.Equal(t, []string{"0001_create_users", "0002_create_items", "0003_add_users_name", "0010_later"}, names)
+		assert.Len(t, loaded[0].Checksum, 64)
+		assert.NotEqual(t, loaded[0].Checksum, loaded[1].Checksum)
+	})
+
+	invalid := map[string]fstest.MapFS{
+		"missing down script": {"0001_a.up.sql": file("SELECT 1;")},
+		"missing up script":   {"0001_a.down.sql": file("SELECT 1;")},
+		"duplicate version": {
+			"0001_a.up.sql": file("SELECT 1;"), "0001_a.down.sql": file("SELECT 1;"),
+			"0001_b.up.sql": file("SELECT 1;"), "0001_b.down.sql": file("SELECT 1;"),
+		},
+		"bad file name":  {"create_users.sql": file("SELECT 1;")},
+		"version zero":   {"0000_a.up.sql": file("SELECT 1;"), "0000_a.down.sql": file("SELECT 1;")},
+		"uppercase name": {"0001_A.up.sql": file("SELECT 1;"), "0001_A.down.sql": file("SELECT 1;")},
+	}
+	for name, fsys := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newMigrator(t, db, migrations(3))
+
+	assert.ErrorIs(t, m.Check(ctx), ErrSchemaBehind)
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	assert.NoError(t, m.Check(ctx))
+	assert.True(t, db.Migrator().HasColumn("users", "name"))
+	assert.True(t, db.Migrator().HasTable("items"))
+
+	// Nothing left to apply
+	applied, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	rolledBack, err := m.Down(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 2)
+	assert.Equal(t, uint(3), rolledBack[0].Version)
+	assert.Equal(t, uint(2), rolledBack[1].Version)
+	assert.False(t, db.Migrator().HasColumn("users", "name"))
+	assert.False(t, db.Migrator().HasTable("items"))
+	assert.True(t, db.Migrator().HasTable("users"))
+	assert.ErrorIs(t, m.Check(ctx), ErrSchemaBehind)
+
+	// Rolling back more than was applied stops at the first migration
+	rolledBack, err = m.Down(ctx, 5)
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, 1)
+	assert.False(t, db.Migrator().HasTable("users"))
+}
+
+func TestUp_FailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	fsys := migrations(1)
+	fsys["0002_broken.up.sql"] = file("CREATE TABLE orders (id INTEGER PRIMARY KEY);\nINSERT INTO missing VALUES (1);")
+	fsys["0002_broken.down.sql"] = file("DROP TABLE orders;")
+	m := newMigrator(t, db, fsys)
+
+	applied, err := m.Up(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0002_broken")
+	require.Len(t, applied, 1)
+	assert.False(t, db.Migrator().HasTable("orders"), "a failed migration must leave no partial changes")
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Applied, statuses[0].State)
+	assert.Equal(t, Pending, statuses[1].State)
+}
+
+func TestCheck_SchemaAhead(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	_, err := newMigrator(t, db, migrations(3)).Up(ctx)
+	require.NoError(t, err)
+
+	older := newMigrator(t, db, migrations(2))
+	assert.ErrorIs(t, older.Check(ctx), ErrSchemaAhead)
+	_, err = older.Up(ctx)
+	assert.ErrorIs(t, err, ErrSchemaAhead)
+	_, err = older.Down(ctx, 1)
+	assert.ErrorIs(t, err, ErrSchemaAhead)
+
+	statuses, err := older.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, Unknown, statuses[2].State)
+}
+
+func TestCheck_ModifiedMigration(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	_, err := newMigrator(t, db, migrations(2)).Up(ctx)
+	require.NoError(t, err)
+
+	edited := migrations(2)
+	edited["0002_create_items.up.sql"] = file("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT);")
+	m := newMigrator(t, db, edited)
+	assert.ErrorIs(t, m.Check(ctx), ErrChecksumMismatch)
+	_, err = m.Up(ctx)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Modified, statuses[1].State)
+}
+
+func TestCommand(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	var out bytes.Buffer
+	cmd := Command{
+		Migrations: migrations(2),
+		Open:       func() (*gorm.DB, error) { return db, nil },
+		Out:        &out,
+	}
+
+	require.NoError(t, cmd.Run(ctx, []string{"up"}))
+	assert.Contains(t, out.String(), "applied 0001_create_users")
+	assert.Contains(t, out.String(), "database is at version 2")
+
+	out.Reset()
+	require.NoError(t, cmd.Run(ctx, []string{"down"}))
+	assert.Equal(t, "rolled back 0002_create_items\n", out.String())
+
+	out.Reset()
+	require.NoError(t, cmd.Run(ctx, []string{"status"}))
+	assert.Regexp(t, `0001\s+create_users\s+applied\s+\d{4}-`, out.String())
+	assert.Regexp(t, `0002\s+create_items\s+pending\s+-`, out.String())
+
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "0"}, {"down", "x"}, {"up", "2"}, {"create"}} {
+		assert.ErrorIs(t, cmd.Run(ctx, args), ErrUsage, "%v", args)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+
+	up, down, err := Create(dir, "Create Users")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0001_create_users.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0001_create_users.down.sql"), down)
+
+	up, _, err = Create(dir, "add-user-name")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0002_add_user_name.up.sql"), up)
+
+	loaded, err := Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, loaded, 2)
+
+	_, _, err = Create(dir, "--")
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package mtls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// Lifetimes of development certificates
+const (
+	devCALifetime   = 5 * 365 * 24 * time.Hour
+	devCertLifetime = 365 * 24 * time.Hour
+)
+
+// DevCA is a certificate authority for issuing service certificates in
+// development and tests. Production certificates should come from a real
+// CA.
+type DevCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// NewDevCA generates a new CA certificate and key
+func NewDevCA() (*DevCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template, err := certTemplate("Student Cafe development CA", devCALifetime)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &DevCA{cert: cert, key: key}, nil
+}
+
+// LoadDevCA reads a CA written by DevCA.Write
+func LoadDevCA(certPath, keyPath string) (*DevCA, error) {
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load CA %s: %w", certPath, err)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("CA key %s cannot sign", keyPath)
+	}
+	return &DevCA{cert: pair.Leaf, key: key}, nil
+}
+
+// Write writes the CA certificate and key as PEM. Services trust the
+// certificate file through TLS_CA_FILE.
+func (ca *DevCA) Write(certPath, keyPath string) error {
+	return writeKeyPair(certPath, keyPath, ca.cert.Raw, ca.key)
+}
+
+// Issue generates a key and a certificate for names signed by the CA, and
+// writes them as PEM. The first name identifies the service to its peers
+// (see PeerName); every name, e.g. "menu-service" and "localhost", is a host
+// name the service may be reached at. The certificate is valid for both
+// serving and calling other services.
+func (ca *DevCA) Issue(certPath, keyPath string, names ...string) error {
+	if len(names) == 0 {
+		return errors.New("a certificate needs at least one name")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template, err := certTemplate(names[0], devCertLifetime)
+	if err != nil {
+		return err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	template.DNSNames = names
+	for _, name := range names {
+		if name == "localhost" {
+			template.IPAddresses = append(template.IPAddresses, net.IPv4(127, 0, 0, 1), net.IPv6loopback)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return err
+	}
+	return writeKeyPair(certPath, keyPath, der, key)
+}
+
+func certTemplate(commonName string, lifetime time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(lifetime),
+	}, nil
+}
+
+func writeKeyPair(certPath, keyPath string, certDER []byte, key crypto.Signer) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o644)
+}
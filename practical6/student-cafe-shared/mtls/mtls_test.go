@@ -0,0 +1,188 @@
+package mtls
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// devCerts writes a CA to dir and issues a certificate for each service,
+// returning the files each service is configured with
+func devCerts(t *testing.T, dir string, services ...string) map[string]Files {
+	t.Helper()
+	ca, err := NewDevCA()
+	require.NoError(t, err)
+	caPath := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ca.Write(caPath, filepath.Join(dir, "ca-key.pem")))
+
+	files := make(map[string]Files)
+	for _, service := range services {
+		f := Files{
+			Cert: filepath.Join(dir, service+".pem"),
+			Key:  filepath.Join(dir, service+"-key.pem"),
+			CA:   caPath,
+		}
+		require.NoError(t, ca.Issue(f.Cert, f.Key, service, "localhost"))
+		files[service] = f
+	}
+	return files
+}
+
+// serve starts a health server guarded by policy on a local port and
+// returns its address
+func serve(t *testing.T, files Files, policy Policy) string {
+	t.Helper()
+	creds, err := files.ServerCredentials()
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(UnaryServerInterceptor(policy)))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	return "localhost:" + port
+}
+
+// check calls Health.Check on addr as the client configured with files
+func check(t *testing.T, addr string, files Files) codes.Code {
+	t.Helper()
+	creds, err := files.ClientCredentials()
+	require.NoError(t, err)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return status.Code(err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	certs := devCerts(t, t.TempDir(), "menu-service", "order-service", "api-gateway")
+	addr := serve(t, certs["menu-service"], Policy{
+		healthpb.Health_Check_FullMethodName: {"order-service"},
+	})
+
+	t.Run("callers are authorized by certificate name", func(t *testing.T) {
+		assert.Equal(t, codes.OK, check(t, addr, certs["order-service"]))
+		assert.Equal(t, codes.PermissionDenied, check(t, addr, certs["api-gateway"]))
+	})
+
+	t.Run("callers without a certificate are rejected", func(t *testing.T) {
+		files := certs["order-service"]
+		files.Cert, files.Key = "", ""
+		assert.Equal(t, codes.Unavailable, check(t, addr, files))
+	})
+
+	t.Run("certificates from another CA are rejected", func(t *testing.T) {
+		other := devCerts(t, t.TempDir(), "order-service")["order-service"]
+		assert.Equal(t, codes.Unavailable, check(t, addr, other))
+
+		// and servers with one are not trusted
+		files := other
+		files.CA = certs["order-service"].CA
+		assert.Equal(t, codes.Unavailable, check(t, serve(t, other, nil), files))
+	})
+
+	t.Run("plaintext callers are rejected", func(t *testing.T) {
+		assert.Equal(t, codes.Unavailable, check(t, addr, Files{}))
+	})
+}
+
+func TestPlaintext(t *testing.T) {
+	addr := serve(t, Files{}, Policy{healthpb.Health_Check_FullMethodName: {"order-service"}})
+
+	// Without certificates callers cannot be told apart
+	assert.Equal(t, codes.OK, check(t, addr, Files{}))
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	certs := devCerts(t, dir, "menu-service", "order-service")
+	addr := serve(t, certs["menu-service"], nil)
+	require.Equal(t, codes.OK, check(t, addr, certs["order-service"]))
+
+	// Replace every file with ones from a new CA, as if all certificates
+	// were rotated at once
+	rotated := devCerts(t, dir, "menu-service", "order-service")
+	bumpModTimes(t, dir)
+	assert.Equal(t, codes.OK, check(t, addr, rotated["order-service"]))
+}
+
+func TestReloadingKeepsValueOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	files := devCerts(t, dir, "menu-service")["menu-service"]
+	keyPair, err := files.keyPair()
+	require.NoError(t, err)
+	original := keyPair.get().Leaf.SerialNumber
+
+	require.NoError(t, os.WriteFile(files.Cert, []byte("half written"), 0o644))
+	bumpModTimes(t, dir)
+	assert.Equal(t, original, keyPair.get().Leaf.SerialNumber)
+
+	ca, err := LoadDevCA(filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem"))
+	require.NoError(t, err)
+	require.NoError(t, ca.Issue(files.Cert, files.Key, "menu-service"))
+	bumpModTimes(t, dir)
+	assert.NotEqual(t, original, keyPair.get().Leaf.SerialNumber)
+}
+
+// bumpModTimes moves the modification time of every file in dir forward,
+// so changes are noticed on file systems with coarse timestamps
+func bumpModTimes(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		require.NoError(t, err)
+		later := info.ModTime().Add(time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, entry.Name()), later, later))
+	}
+}
+
+func TestFilesFromEnv(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "")
+	t.Setenv("TLS_KEY_FILE", "")
+	t.Setenv("TLS_CA_FILE", "")
+	files, err := FilesFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Files{}, files)
+
+	t.Setenv("TLS_CERT_FILE", "service.pem")
+	_, err = FilesFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("TLS_KEY_FILE", "service-key.pem")
+	t.Setenv("TLS_CA_FILE", "ca.pem")
+	files, err = FilesFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Files{Cert: "service.pem", Key: "service-key.pem", CA: "ca.pem"}, files)
+}
+
+func TestCredentialsNeedMatchingFiles(t *testing.T) {
+	_, err := Files{CA: "ca.pem"}.ServerCredentials()
+	assert.Error(t, err, "a server cannot verify callers without its own certificate")
+
+	_, err = Files{Cert: "service.pem", Key: "service-key.pem"}.ClientCredentials()
+	assert.Error(t, err, "a client must verify the server before presenting a certificate")
+
+	_, err = Files{Cert: "missing.pem", Key: "missing-key.pem"}.ServerCredentials()
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package mtls
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PeerName returns the name of the service that made the call in ctx: the
+// first DNS name in the certificate it was verified with, e.g.
+// "order-service". ok is false when the connection is plaintext or the
+// caller presented no certificate.
+func PeerName(ctx context.Context) (name string, ok bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	names := info.State.VerifiedChains[0][0].DNSNames
+	if len(names) == 0 {
+		return "", false
+	}
+	return names[0], true
+}
+
+// Policy maps full gRPC method names, e.g. "/menu.v1.MenuService/ReserveStock",
+// to the services allowed to call them. Methods missing from the policy are
+// open to every service.
+type Policy map[string][]string
+
+// UnaryServerInterceptor rejects calls to methods in policy from services it
+// does not list. Without mutual TLS callers cannot be told apart and the
+// policy is not enforced.
+func UnaryServerInterceptor(policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := policy.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs
+func StreamServerInterceptor(policy Policy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := policy.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (p Policy) check(ctx context.Context, fullMethod string) error {
+	allowed, guarded := p[fullMethod]
+	if !guarded {
+		return nil
+	}
+	name, ok := PeerName(ctx)
+	if !ok || slices.Contains(allowed, name) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "%s may not call %s, only %s",
+		name, fullMethod, strings.Join(allowed, " or "))
+}
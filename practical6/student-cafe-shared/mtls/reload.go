@@ -0,0 +1,74 @@
+package mtls
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+)
+
+// reloading holds a value loaded from files, reloading it when any of them
+// changes
+type reloading[T any] struct {
+	paths []string
+	load  func() (T, error)
+
+	mu     sync.Mutex
+	stamps []fileStamp
+	value  T
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+// newReloading loads the value from paths with load
+func newReloading[T any](load func() (T, error), paths ...string) (*reloading[T], error) {
+	r := &reloading[T]{paths: paths, load: load}
+	stamps, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	r.stamps, r.value = stamps, value
+	return r, nil
+}
+
+// get returns the value, reloading it first if a file has changed since the
+// last load. A failed reload keeps the previous value, so new connections
+// keep working while files are half written, and is retried on the next
+// call.
+func (r *reloading[T]) get() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps, err := r.stat()
+	if err != nil || slices.Equal(stamps, r.stamps) {
+		return r.value
+	}
+	value, err := r.load()
+	if err != nil {
+		slog.Warn("Failed to reload TLS files, keeping the previous ones", "files", r.paths, "error", err)
+		return r.value
+	}
+	r.stamps, r.value = stamps, value
+	slog.Info("Reloaded TLS files", "files", r.paths)
+	return r.value
+}
+
+func (r *reloading[T]) stat() ([]fileStamp, error) {
+	stamps := make([]fileStamp, len(r.paths))
+	for i, path := range r.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		stamps[i] = fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+	}
+	return stamps, nil
+}
@@ -0,0 +1,304 @@
+// Package pagination implements cursor-based (keyset) pagination and sorting
+// for the list RPCs of the Student Cafe services.
+//
+// Page tokens are opaque to clients: they encode the sort order and the sort
+// key of the last row returned, so the next page starts strictly after it
+// even if rows are inserted or deleted between requests.
+package pagination
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultPageSize is used when a request does not set page_size
+	DefaultPageSize = 50
+	// MaxPageSize caps page_size so a single request stays bounded
+	MaxPageSize = 200
+)
+
+// ErrInvalidPageToken is returned for tokens that cannot be decoded or were
+// issued for a different sort order
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Sort orders a list by one column, with the primary key as tiebreaker
+type Sort struct {
+	Field  string // API name, e.g. "price"
+	Column string // database column, e.g. "price_minor"
+	Desc   bool
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return s.Field + " desc"
+	}
+	return s.Field + " asc"
+}
+
+// ParseSort parses an order_by value such as "created_at desc". fields maps
+// the sortable API field names to database columns; an empty orderBy returns
+// def.
+func ParseSort(orderBy string, fields map[string]string, def Sort) (Sort, error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) == 0 {
+		return def, nil
+	}
+	if len(parts) > 2 {
+		return Sort{}, fmt.Errorf("invalid order_by %q", orderBy)
+	}
+
+	column, ok := fields[parts[0]]
+	if !ok {
+		return Sort{}, fmt.Errorf("cannot order by %q", parts[0])
+	}
+
+	sort := Sort{Field: parts[0], Column: column}
+	if len(parts) == 2 {
+		switch strings.ToLower(parts[1]) {
+		case "asc":
+		case "desc":
+			sort.Desc = true
+		default:
+			return Sort{}, fmt.Errorf("invalid sort direction %q", parts[1])
+		}
+	}
+	return sort, nil
+}
+
+// Request describes the page to fetch
+type Request struct {
+	PageSize  int32
+	PageToken string
+	Sort      Sort
+}
+
+// size returns the effective page size
+func (r Request) size() int {
+	switch {
+	case r.PageSize <= 0:
+		return DefaultPageSize
+	case r.PageSize > MaxPageSize:
+		return MaxPageSize
+	default:
+		return int(r.PageSize)
+	}
+}
+
+// Key returns a row's value in the sort column and its primary key
+type Key[T any] func(row *T) (value any, id uint)
+
+// Find loads one page of rows from query, which may already carry filters and
+// preloads. It returns the rows and the token for the next page, which is
+// empty on the last page.
+func Find[T any](query *gorm.DB, req Request, key Key[T]) ([]T, string, error) {
+	query = query.Order(orderClause(req.Sort))
+
+	if req.PageToken != "" {
+		value, id, err := decodeToken(req.PageToken, req.Sort)
+		if err != nil {
+			return nil, "", err
+		}
+
+		op := ">"
+		if req.Sort.Desc {
+			op = "<"
+		}
+		// Column names come from the ParseSort allow-list, never from input
+		query = query.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", req.Sort.Column, op),
+			value, value, id,
+		)
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	size := req.size()
+	var rows []T
+	if err := query.Limit(size + 1).Find(&rows).Error; err != nil {
+		return nil, "", err
+	}
+	if len(rows) <= size {
+		return rows, "", nil
+	}
+
+	rows = rows[:size]
+	value, id := key(&rows[size-1])
+	token, err := encodeToken(req.Sort, value, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return rows, token, nil
+}
+
+// Slice returns one page of rows held in memory, sorted and split into pages
+// the way Find pages through a query. rows is not modified.
+func Slice[T any](rows []T, req Request, key Key[T]) ([]T, string, error) {
+	page := make([]T, 0, len(rows))
+	if req.PageToken == "" {
+		page = append(page, rows...)
+	} else {
+		value, id, err := decodeToken(req.PageToken, req.Sort)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range rows {
+			rowValue, rowID := key(&rows[i])
+			c, ok := compareKeys(rowValue, rowID, value, id)
+			if !ok {
+				return nil, "", ErrInvalidPageToken
+			}
+			if (c > 0 && !req.Sort.Desc) || (c < 0 && req.Sort.Desc) {
+				page = append(page, rows[i])
+			}
+		}
+	}
+
+	slices.SortStableFunc(page, func(a, b T) int {
+		aValue, aID := key(&a)
+		bValue, bID := key(&b)
+		c, _ := compareKeys(aValue, aID, bValue, bID)
+		if req.Sort.Desc {
+			return -c
+		}
+		return c
+	})
+
+	size := req.size()
+	if len(page) <= size {
+		return page, "", nil
+	}
+
+	page = page[:size]
+	value, id := key(&page[size-1])
+	token, err := encodeToken(req.Sort, value, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return page, token, nil
+}
+
+// compareKeys orders two sort keys by value and then by id. It reports
+// false if the values are not of comparable types.
+func compareKeys(a any, aID uint, b any, bID uint) (int, bool) {
+	var c int
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		c = strings.Compare(a, b)
+	case time.Time:
+		b, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		c = a.Compare(b)
+	default:
+		// Integer keys are decoded from tokens as int64
+		aInt, aOK := toInt64(a)
+		bInt, bOK := toInt64(b)
+		if !aOK || !bOK {
+			return 0, false
+		}
+		c = cmp.Compare(aInt, bInt)
+	}
+	if c == 0 {
+		c = cmp.Compare(aID, bID)
+	}
+	return c, true
+}
+
+// toInt64 converts the integer sort key types to int64
+func toInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// orderClause builds the ORDER BY for a sort, breaking ties by id
+func orderClause(sort Sort) string {
+	dir := "ASC"
+	if sort.Desc {
+		dir = "DESC"
+	}
+	if sort.Column == "id" {
+		return "id " + dir
+	}
+	return fmt.Sprintf("%s %s, id %s", sort.Column, dir, dir)
+}
+
+// cursor is the JSON payload of a page token. The value's type is recorded so
+// it is bound back to the query with the same type it was read with.
+type cursor struct {
+	Sort  string `json:"s"`
+	Type  string `json:"t"`
+	Value string `json:"v"`
+	ID    uint   `json:"i"`
+}
+
+func encodeToken(sort Sort, value any, id uint) (string, error) {
+	c := cursor{Sort: sort.String(), ID: id}
+	switch v := value.(type) {
+	case string:
+		c.Type, c.Value = "string", v
+	case int64:
+		c.Type, c.Value = "int", fmt.Sprint(v)
+	case uint:
+		c.Type, c.Value = "int", fmt.Sprint(v)
+	case time.Time:
+		c.Type, c.Value = "time", v.Format(time.RFC3339Nano)
+	default:
+		return "", fmt.Errorf("unsupported sort key type %T", value)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeToken(token string, sort Sort) (any, uint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, 0, ErrInvalidPageToken
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sort.String() {
+		return nil, 0, ErrInvalidPageToken
+	}
+
+	switch c.Type {
+	case "string":
+		return c.Value, c.ID, nil
+	case "int":
+		var v int64
+		if _, err := fmt.Sscan(c.Value, &v); err != nil {
+			return nil, 0, ErrInvalidPageToken
+		}
+		return v, c.ID, nil
+	case "time":
+		v, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, 0, ErrInvalidPageToken
+		}
+		return v, c.ID, nil
+	default:
+		return nil, 0, ErrInvalidPageToken
+	}
+}
@@ -0,0 +1,201 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type item struct {
+	gorm.Model
+	Name  string
+	Price int64
+}
+
+var itemFields = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"price":      "price",
+	"created_at": "created_at",
+}
+
+func setupItems(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&item{}))
+
+	// Duplicate prices exercise the id tiebreaker
+	start := time.Now()
+	items := []item{
+		{Name: "Coffee", Price: 250},
+		{Name: "Tea", Price: 200},
+		{Name: "Muffin", Price: 250},
+		{Name: "Bagel", Price: 300},
+		{Name: "Water", Price: 100},
+	}
+	for i := range items {
+		items[i].CreatedAt = start.Add(time.Duration(i) * time.Second)
+	}
+	require.NoError(t, db.Create(&items).Error)
+	return db
+}
+
+// collect walks every page and returns the item names in order
+func collect(t *testing.T, db *gorm.DB, sort Sort, pageSize int32, key Key[item]) []string {
+	var names []string
+	token := ""
+	for pages := 0; pages < 10; pages++ {
+		rows, next, err := Find(db.Model(&item{}), Request{PageSize: pageSize, PageToken: token, Sort: sort}, key)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(rows), int(pageSize))
+		for _, row := range rows {
+			names = append(names, row.Name)
+		}
+		if next == "" {
+			return names
+		}
+		token = next
+	}
+	t.Fatal("pagination did not terminate")
+	return nil
+}
+
+func TestFind(t *testing.T) {
+	db := setupItems(t)
+
+	byPrice := func(row *item) (any, uint) { return row.Price, row.ID }
+	byName := func(row *item) (any, uint) { return row.Name, row.ID }
+	byCreated := func(row *item) (any, uint) { return row.CreatedAt, row.ID }
+
+	tests := []struct {
+		name    string
+		orderBy string
+		key     Key[item]
+		want    []string
+	}{
+		{"default id order", "", func(row *item) (any, uint) { return row.ID, row.ID }, []string{"Coffee", "Tea", "Muffin", "Bagel", "Water"}},
+		{"price ascending with ties", "price", byPrice, []string{"Water", "Tea", "Coffee", "Muffin", "Bagel"}},
+		{"price descending with ties", "price desc", byPrice, []string{"Bagel", "Muffin", "Coffee", "Tea", "Water"}},
+		{"name", "name asc", byName, []string{"Bagel", "Coffee", "Muffin", "Tea", "Water"}},
+		{"newest first", "created_at desc", byCreated, []string{"Water", "Bagel", "Muffin", "Tea", "Coffee"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := ParseSort(tt.orderBy, itemFields, Sort{Field: "id", Column: "id"})
+			require.NoError(t, err)
+
+			for _, pageSize := range []int32{1, 2, 10} {
+				assert.Equal(t, tt.want, collect(t, db, sort, pageSize, tt.key), "page size %d", pageSize)
+			}
+		})
+	}
+}
+
+func TestFind_InvalidToken(t *testing.T) {
+	db := setupItems(t)
+	key := func(row *item) (any, uint) { return row.Price, row.ID }
+
+	byPrice := Sort{Field: "price", Column: "price"}
+	_, token, err := Find(db.Model(&item{}), Request{PageSize: 2, Sort: byPrice}, key)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	t.Run("garbage token", func(t *testing.T) {
+		_, _, err := Find(db.Model(&item{}), Request{PageToken: "not-a-token", Sort: byPrice}, key)
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+
+	t.Run("token from a different sort", func(t *testing.T) {
+		_, _, err := Find(db.Model(&item{}), Request{PageToken: token, Sort: Sort{Field: "price", Column: "price", Desc: true}}, key)
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+}
+
+func TestSlice(t *testing.T) {
+	db := setupItems(t)
+	var items []item
+	require.NoError(t, db.Find(&items).Error)
+
+	byPrice := func(row *item) (any, uint) { return row.Price, row.ID }
+	byCreated := func(row *item) (any, uint) { return row.CreatedAt, row.ID }
+	byID := func(row *item) (any, uint) { return row.ID, row.ID }
+
+	tests := []struct {
+		name    string
+		orderBy string
+		key     Key[item]
+	}{
+		{"default id order", "", byID},
+		{"price ascending with ties", "price", byPrice},
+		{"price descending with ties", "price desc", byPrice},
+		{"newest first", "created_at desc", byCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := ParseSort(tt.orderBy, itemFields, Sort{Field: "id", Column: "id"})
+			require.NoError(t, err)
+
+			// Pages must match what Find returns from the database
+			want := collect(t, db, sort, 10, tt.key)
+			for _, pageSize := range []int32{1, 2, 10} {
+				var names []string
+				token := ""
+				for pages := 0; pages < 10; pages++ {
+					rows, next, err := Slice(items, Request{PageSize: pageSize, PageToken: token, Sort: sort}, tt.key)
+					require.NoError(t, err)
+					assert.LessOrEqual(t, len(rows), int(pageSize))
+					for _, row := range rows {
+						names = append(names, row.Name)
+					}
+					if next == "" {
+						break
+					}
+					token = next
+				}
+				assert.Equal(t, want, names, "page size %d", pageSize)
+			}
+		})
+	}
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		byPriceSort := Sort{Field: "price", Column: "price"}
+		_, _, err := Slice(items, Request{PageToken: "not-a-token", Sort: byPriceSort}, byPrice)
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+		// A token whose value does not match the sort key's type
+		token, err := encodeToken(byPriceSort, "cheap", 1)
+		require.NoError(t, err)
+		_, _, err = Slice(items, Request{PageToken: token, Sort: byPriceSort}, byPrice)
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+}
+
+func TestParseSort(t *testing.T) {
+	def := Sort{Field: "id", Column: "id"}
+
+	sort, err := ParseSort("price DESC", itemFields, def)
+	require.NoError(t, err)
+	assert.Equal(t, Sort{Field: "price", Column: "price", Desc: true}, sort)
+
+	sort, err = ParseSort("", itemFields, def)
+	require.NoError(t, err)
+	assert.Equal(t, def, sort)
+
+	for _, bad := range []string{"password", "price sideways", "price desc extra"} {
+		_, err := ParseSort(bad, itemFields, def)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	assert.Equal(t, DefaultPageSize, Request{PageSize: 0}.size())
+	assert.Equal(t, DefaultPageSize, Request{PageSize: -5}.size())
+	assert.Equal(t, 10, Request{PageSize: 10}.size())
+	assert.Equal(t, MaxPageSize, Request{PageSize: MaxPageSize + 1}.size())
+}
@@ -0,0 +1,238 @@
+// Package resilience protects calls between the Student Cafe services from
+// slow and failing backends.
+//
+// Every call gets a deadline and idempotent reads are retried with
+// exponential backoff, both through the gRPC service config built by
+// ServiceConfig. A Breaker stops calling a backend that keeps failing, so
+// callers fail fast instead of queueing behind timeouts, and lets a trial
+// call through once the backend has had time to recover. Breaker states and
+// counters are published as Prometheus metrics named circuit_breaker_*.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/grpcerr"
+)
+
+const (
+	// DefaultFailureThreshold is used when BreakerConfig.FailureThreshold is zero
+	DefaultFailureThreshold = 5
+	// DefaultOpenTimeout is used when BreakerConfig.OpenTimeout is zero
+	DefaultOpenTimeout = 10 * time.Second
+)
+
+// ReasonCircuitOpen is the ErrorInfo reason of calls rejected by an open
+// breaker
+const ReasonCircuitOpen = "CIRCUIT_OPEN"
+
+// State is the state of a Breaker
+type State int
+
+const (
+	// Closed lets every call through
+	Closed State = iota
+	// Open rejects every call until the open timeout has passed
+	Open
+	// HalfOpen lets a single trial call through to decide whether to close
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig tunes a Breaker
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// breaker
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before a trial call
+	OpenTimeout time.Duration
+}
+
+// Stats is a snapshot of a Breaker's state and counters
+type Stats struct {
+	State     string
+	Failures  int   // consecutive failures while closed
+	Opened    int64 // times the breaker has opened
+	Rejected  int64 // calls rejected while open
+	Succeeded int64
+	Failed    int64
+}
+
+// Breaker is a circuit breaker for calls to one backend
+type Breaker struct {
+	name      string
+	threshold int
+	timeout   time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int       // consecutive failures while closed
+	openedAt time.Time // when the breaker last opened
+	probing  bool      // a trial call is in flight while half-open
+	stats    Stats
+}
+
+// NewBreaker returns a closed breaker for the backend called name, e.g.
+// "menu-service", and publishes its stats
+func NewBreaker(name string, config BreakerConfig) *Breaker {
+	b := &Breaker{
+		name:      name,
+		threshold: config.FailureThreshold,
+		timeout:   config.OpenTimeout,
+		now:       time.Now,
+	}
+	if b.threshold <= 0 {
+		b.threshold = DefaultFailureThreshold
+	}
+	if b.timeout <= 0 {
+		b.timeout = DefaultOpenTimeout
+	}
+	breakers.add(b)
+	return b
+}
+
+// Name returns the name of the backend the breaker protects
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the breaker's current state
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// Stats returns a snapshot of the breaker's state and counters
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := b.stats
+	stats.State = b.currentState().String()
+	stats.Failures = b.failures
+	return stats
+}
+
+// currentState moves an open breaker to half-open once its timeout has
+// passed. b.mu must be held.
+func (b *Breaker) currentState() State {
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.timeout {
+		b.state = HalfOpen
+		b.probing = false
+	}
+	return b.state
+}
+
+// allow reports whether a call may proceed
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case Open:
+		b.stats.Rejected++
+		return b.openError()
+	case HalfOpen:
+		if b.probing {
+			b.stats.Rejected++
+			return b.openError()
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed call
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !IsFailure(err) {
+		b.stats.Succeeded++
+		if b.state == HalfOpen {
+			slog.Info("Circuit breaker closed", "backend", b.name)
+		}
+		b.state = Closed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.stats.Failed++
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		slog.Warn("Circuit breaker opened", "backend", b.name, "consecutive_failures", b.failures, "error", err)
+		b.state = Open
+		b.openedAt = b.now()
+		b.failures = 0
+		b.probing = false
+		b.stats.Opened++
+	}
+}
+
+func (b *Breaker) openError() error {
+	return grpcerr.New(codes.Unavailable, b.name+" is unavailable: circuit breaker open",
+		grpcerr.Info(ReasonCircuitOpen, map[string]string{"service": b.name}))
+}
+
+// IsFailure reports whether err means the backend is unhealthy, as opposed
+// to rejecting a particular request. Cancellations by the caller are not
+// failures.
+func IsFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnaryClientInterceptor fails calls fast while the breaker is open and
+// records the outcome of the others
+func (b *Breaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := b.allow(); err != nil {
+			return err
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor fails new streams fast while the breaker is open.
+// Only errors opening a stream are recorded; streams are long-lived, so how
+// they end says little about the backend's health.
+func (b *Breaker) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := b.allow(); err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		b.record(err)
+		return stream, err
+	}
+}
@@ -0,0 +1,111 @@
+package resilience
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultTimeout is the deadline of a call when CallTimeout finds no
+// GRPC_CLIENT_TIMEOUT
+const DefaultTimeout = 5 * time.Second
+
+// Retry policy for idempotent reads. Only UNAVAILABLE is retried: the
+// request never reached a healthy server, so trying again is always safe.
+const (
+	maxAttempts       = 3
+	initialBackoff    = "0.1s"
+	maxBackoff        = "1s"
+	backoffMultiplier = 2
+)
+
+// CallTimeout returns the per-call deadline from the GRPC_CLIENT_TIMEOUT
+// environment variable, e.g. "3s"
+func CallTimeout() (time.Duration, error) {
+	value := os.Getenv("GRPC_CLIENT_TIMEOUT")
+	if value == "" {
+		return DefaultTimeout, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid GRPC_CLIENT_TIMEOUT %q", value)
+	}
+	return d, nil
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// ServiceConfig returns a gRPC service config giving every unary method of
+// desc a deadline of timeout and retrying the named idempotent reads with
+// exponential backoff. Streaming methods are left without a deadline.
+func ServiceConfig(desc grpc.ServiceDesc, timeout time.Duration, reads ...string) string {
+	retried := make(map[string]bool, len(reads))
+	for _, read := range reads {
+		retried[read] = true
+	}
+
+	deadline := fmt.Sprintf("%.3fs", timeout.Seconds())
+	other := methodConfig{Timeout: deadline}
+	idempotent := methodConfig{
+		Timeout: deadline,
+		RetryPolicy: &retryPolicy{
+			MaxAttempts:          maxAttempts,
+			InitialBackoff:       initialBackoff,
+			MaxBackoff:           maxBackoff,
+			BackoffMultiplier:    backoffMultiplier,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		},
+	}
+	for _, m := range desc.Methods {
+		name := methodName{Service: desc.ServiceName, Method: m.MethodName}
+		if retried[m.MethodName] {
+			idempotent.Name = append(idempotent.Name, name)
+		} else {
+			other.Name = append(other.Name, name)
+		}
+	}
+
+	var configs []methodConfig
+	for _, c := range []methodConfig{idempotent, other} {
+		if len(c.Name) > 0 {
+			configs = append(configs, c)
+		}
+	}
+	data, err := json.Marshal(map[string]any{"methodConfig": configs})
+	if err != nil {
+		// Only plain structs are marshalled
+		panic(err)
+	}
+	return string(data)
+}
+
+// DialOptions returns the options that protect a connection to the service
+// described by desc: the service config from ServiceConfig and breaker's
+// interceptors
+func DialOptions(desc grpc.ServiceDesc, timeout time.Duration, breaker *Breaker, reads ...string) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(ServiceConfig(desc, timeout, reads...)),
+		grpc.WithChainUnaryInterceptor(breaker.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(breaker.StreamClientInterceptor()),
+	}
+}
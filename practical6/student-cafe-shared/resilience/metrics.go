@@ -0,0 +1,80 @@
+package resilience
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// breakers publishes the Stats of every Breaker to the default Prometheus
+// registry, so they are served on each service's metrics port
+var breakers = newCollector()
+
+func init() {
+	prometheus.MustRegister(breakers)
+}
+
+var (
+	stateDesc = prometheus.NewDesc("circuit_breaker_state",
+		"1 for the state each circuit breaker is in, 0 for the others.",
+		[]string{"backend", "state"}, nil)
+	failuresDesc = prometheus.NewDesc("circuit_breaker_consecutive_failures",
+		"Consecutive failed calls counted towards opening each circuit breaker.",
+		[]string{"backend"}, nil)
+	openedDesc = prometheus.NewDesc("circuit_breaker_opened_total",
+		"Times each circuit breaker has opened.",
+		[]string{"backend"}, nil)
+	rejectedDesc = prometheus.NewDesc("circuit_breaker_rejected_total",
+		"Calls rejected while each circuit breaker was open.",
+		[]string{"backend"}, nil)
+	callsDesc = prometheus.NewDesc("circuit_breaker_calls_total",
+		"Calls let through each circuit breaker, by whether the backend failed them.",
+		[]string{"backend", "outcome"}, nil)
+)
+
+// collector reads the breakers' stats when scraped, so an open breaker is
+// reported half-open as soon as its timeout has passed
+type collector struct {
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+func newCollector() *collector {
+	return &collector{breakers: make(map[string]*Breaker)}
+}
+
+// add publishes b, replacing any breaker of the same name
+func (c *collector) add(b *Breaker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.breakers[b.name] = b
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stateDesc
+	ch <- failuresDesc
+	ch <- openedDesc
+	ch <- rejectedDesc
+	ch <- callsDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, b := range c.breakers {
+		stats := b.Stats()
+		for _, state := range []State{Closed, Open, HalfOpen} {
+			value := 0.0
+			if state.String() == stats.State {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, value, name, state.String())
+		}
+		ch <- prometheus.MustNewConstMetric(failuresDesc, prometheus.GaugeValue, float64(stats.Failures), name)
+		ch <- prometheus.MustNewConstMetric(openedDesc, prometheus.CounterValue, float64(stats.Opened), name)
+		ch <- prometheus.MustNewConstMetric(rejectedDesc, prometheus.CounterValue, float64(stats.Rejected), name)
+		ch <- prometheus.MustNewConstMetric(callsDesc, prometheus.CounterValue, float64(stats.Succeeded), name, "success")
+		ch <- prometheus.MustNewConstMetric(callsDesc, prometheus.CounterValue, float64(stats.Failed), name, "failure")
+	}
+}
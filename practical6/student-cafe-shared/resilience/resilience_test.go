@@ -0,0 +1,351 @@
+package resilience
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// faultyUserServer is a fake user service that injects faults: it fails the
+// next failures calls with code and delays every call by delay
+type faultyUserServer struct {
+	userv1.UnimplementedUserServiceServer
+	failures atomic.Int32
+	code     codes.Code
+	delay    time.Duration
+	calls    atomic.Int32
+}
+
+func (s *faultyUserServer) fault(ctx context.Context) error {
+	s.calls.Add(1)
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if s.failures.Add(-1) >= 0 {
+		return status.Error(s.code, "injected fault")
+	}
+	return nil
+}
+
+func (s *faultyUserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	if err := s.fault(ctx); err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserResponse{User: &userv1.User{Id: req.Id}}, nil
+}
+
+func (s *faultyUserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.CreateUserResponse, error) {
+	if err := s.fault(ctx); err != nil {
+		return nil, err
+	}
+	return &userv1.CreateUserResponse{User: &userv1.User{Id: 1, Name: req.Name}}, nil
+}
+
+// dial starts fake on a bufconn server and returns a client protected by
+// the package's dial options
+func dial(t *testing.T, fake *faultyUserServer, timeout time.Duration, breaker *Breaker) userv1.UserServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	userv1.RegisterUserServiceServer(s, fake)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	opts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, DialOptions(userv1.UserService_ServiceDesc, timeout, breaker, "GetUser")...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return userv1.NewUserServiceClient(conn)
+}
+
+func TestDeadline(t *testing.T) {
+	fake := &faultyUserServer{delay: time.Second}
+	client := dial(t, fake, 50*time.Millisecond, NewBreaker("deadline-test", BreakerConfig{}))
+
+	start := time.Now()
+	_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 1})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// A shorter deadline from the caller still wins
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = client.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Alice"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("reads are retried while unavailable", func(t *testing.T) {
+		fake := &faultyUserServer{code: codes.Unavailable}
+		fake.failures.Store(2)
+		client := dial(t, fake, time.Second, NewBreaker("retry-read-test", BreakerConfig{}))
+
+		resp, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 7})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(7), resp.User.Id)
+		assert.Equal(t, int32(3), fake.calls.Load())
+	})
+
+	t.Run("reads give up after the last attempt", func(t *testing.T) {
+		fake := &faultyUserServer{code: codes.Unavailable}
+		fake.failures.Store(10)
+		client := dial(t, fake, time.Second, NewBreaker("retry-give-up-test", BreakerConfig{}))
+
+		_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 7})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(maxAttempts), fake.calls.Load())
+	})
+
+	t.Run("writes are not retried", func(t *testing.T) {
+		fake := &faultyUserServer{code: codes.Unavailable}
+		fake.failures.Store(1)
+		client := dial(t, fake, time.Second, NewBreaker("retry-write-test", BreakerConfig{}))
+
+		_, err := client.CreateUser(context.Background(), &userv1.CreateUserRequest{Name: "Alice"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(1), fake.calls.Load())
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		fake := &faultyUserServer{code: codes.NotFound}
+		fake.failures.Store(1)
+		client := dial(t, fake, time.Second, NewBreaker("retry-not-found-test", BreakerConfig{}))
+
+		_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 7})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, int32(1), fake.calls.Load())
+	})
+}
+
+func TestBreaker_AgainstFaultyServer(t *testing.T) {
+	fake := &faultyUserServer{code: codes.Internal}
+	fake.failures.Store(3)
+	breaker := NewBreaker("faulty-user-service", BreakerConfig{FailureThreshold: 3, OpenTimeout: 50 * time.Millisecond})
+	client := dial(t, fake, time.Second, breaker)
+	ctx := context.Background()
+
+	for range 3 {
+		_, err := client.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Alice"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	}
+	assert.Equal(t, Open, breaker.State())
+
+	// Calls fail fast without reaching the server
+	_, err := client.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Alice"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, ReasonCircuitOpen, errorReason(err))
+	assert.Equal(t, int32(3), fake.calls.Load())
+
+	// After the open timeout a trial call goes through and closes the breaker
+	time.Sleep(60 * time.Millisecond)
+	_, err = client.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, Closed, breaker.State())
+
+	stats := breaker.Stats()
+	assert.Equal(t, int64(1), stats.Opened)
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(3), stats.Failed)
+	assert.Equal(t, int64(1), stats.Succeeded)
+}
+
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// clock is a manually advanced time source
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(name string) (*Breaker, *clock) {
+	c := &clock{t: time.Unix(0, 0)}
+	b := NewBreaker(name, BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+	b.now = c.now
+	return b, c
+}
+
+var errUnavailable = status.Error(codes.Unavailable, "down")
+
+func TestBreaker_States(t *testing.T) {
+	b, clock := newTestBreaker("states-test")
+
+	// Successes reset the count of consecutive failures
+	require.NoError(t, b.allow())
+	b.record(errUnavailable)
+	require.NoError(t, b.allow())
+	b.record(nil)
+	require.NoError(t, b.allow())
+	b.record(errUnavailable)
+	assert.Equal(t, Closed, b.State())
+
+	require.NoError(t, b.allow())
+	b.record(errUnavailable)
+	assert.Equal(t, Open, b.State())
+	assert.Error(t, b.allow())
+
+	// Half-open allows one trial call at a time
+	clock.advance(time.Minute)
+	assert.Equal(t, HalfOpen, b.State())
+	require.NoError(t, b.allow())
+	assert.Error(t, b.allow())
+
+	// A failed trial reopens the breaker straight away
+	b.record(errUnavailable)
+	assert.Equal(t, Open, b.State())
+
+	clock.advance(time.Minute)
+	require.NoError(t, b.allow())
+	b.record(nil)
+	assert.Equal(t, Closed, b.State())
+	require.NoError(t, b.allow())
+}
+
+func TestBreaker_RequestErrorsAreNotFailures(t *testing.T) {
+	b, _ := newTestBreaker("request-errors-test")
+
+	for _, err := range []error{
+		status.Error(codes.NotFound, "no such user"),
+		status.Error(codes.InvalidArgument, "bad email"),
+		status.Error(codes.PermissionDenied, "not yours"),
+		status.Error(codes.Canceled, "caller went away"),
+		context.Canceled,
+	} {
+		assert.False(t, IsFailure(err), "%v", err)
+		require.NoError(t, b.allow())
+		b.record(err)
+	}
+	assert.Equal(t, Closed, b.State())
+
+	assert.True(t, IsFailure(status.Error(codes.DeadlineExceeded, "slow")))
+	assert.True(t, IsFailure(errors.New("not a status")))
+}
+
+// breakerMetric returns the value of the named circuit_breaker_* series of
+// backend, with the given extra labels, from the default registry
+func breakerMetric(t *testing.T, name, backend string, labels ...string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	want := map[string]string{"backend": backend}
+	for i := 0; i+1 < len(labels); i += 2 {
+		want[labels[i]] = labels[i+1]
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			got := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				got[label.GetName()] = label.GetValue()
+			}
+			if maps.Equal(got, want) {
+				if metric.GetCounter() != nil {
+					return metric.GetCounter().GetValue()
+				}
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+	t.Fatalf("no %s series for %v", name, want)
+	return 0
+}
+
+func TestBreaker_Metrics(t *testing.T) {
+	b, clock := newTestBreaker("metrics-test")
+	require.NoError(t, b.allow())
+	b.record(nil)
+	require.NoError(t, b.allow())
+	b.record(errUnavailable)
+
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_state", "metrics-test", "state", "closed"))
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_consecutive_failures", "metrics-test"))
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_calls_total", "metrics-test", "outcome", "success"))
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_calls_total", "metrics-test", "outcome", "failure"))
+
+	// A second failure trips it
+	require.NoError(t, b.allow())
+	b.record(errUnavailable)
+	assert.Error(t, b.allow())
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_state", "metrics-test", "state", "open"))
+	assert.Equal(t, 0.0, breakerMetric(t, "circuit_breaker_state", "metrics-test", "state", "closed"))
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_opened_total", "metrics-test"))
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_rejected_total", "metrics-test"))
+
+	// Scrapes see it go half-open once the timeout has passed
+	clock.advance(time.Minute)
+	assert.Equal(t, 1.0, breakerMetric(t, "circuit_breaker_state", "metrics-test", "state", "half-open"))
+}
+
+func TestServiceConfig(t *testing.T) {
+	var config struct {
+		MethodConfig []struct {
+			Name        []methodName
+			Timeout     string
+			RetryPolicy *retryPolicy
+		}
+	}
+	raw := ServiceConfig(userv1.UserService_ServiceDesc, 1500*time.Millisecond, "GetUser", "GetUsers")
+	require.NoError(t, json.Unmarshal([]byte(raw), &config))
+	require.Len(t, config.MethodConfig, 2)
+
+	reads := config.MethodConfig[0]
+	assert.Equal(t, "1.500s", reads.Timeout)
+	require.NotNil(t, reads.RetryPolicy)
+	assert.ElementsMatch(t, []methodName{
+		{Service: "user.v1.UserService", Method: "GetUser"},
+		{Service: "user.v1.UserService", Method: "GetUsers"},
+	}, reads.Name)
+
+	writes := config.MethodConfig[1]
+	assert.Nil(t, writes.RetryPolicy)
+	assert.Contains(t, writes.Name, methodName{Service: "user.v1.UserService", Method: "CreateUser"})
+}
+
+func TestCallTimeout(t *testing.T) {
+	t.Setenv("GRPC_CLIENT_TIMEOUT", "")
+	d, err := CallTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTimeout, d)
+
+	t.Setenv("GRPC_CLIENT_TIMEOUT", "250ms")
+	d, err = CallTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, d)
+
+	t.Setenv("GRPC_CLIENT_TIMEOUT", "0s")
+	_, err = CallTimeout()
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+// Package shutdown helps the services stop cleanly when they are asked to
+// terminate, finishing in-flight requests within a bounded drain period.
+package shutdown
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultTimeout is the drain period used when SHUTDOWN_TIMEOUT is unset
+const DefaultTimeout = 30 * time.Second
+
+// Timeout returns the drain period from the SHUTDOWN_TIMEOUT environment
+// variable, e.g. "15s"
+func Timeout() (time.Duration, error) {
+	value := os.Getenv("SHUTDOWN_TIMEOUT")
+	if value == "" {
+		return DefaultTimeout, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid SHUTDOWN_TIMEOUT %q", value)
+	}
+	return d, nil
+}
+
+// GracefulStop stops s from accepting new RPCs and waits up to timeout for
+// in-flight ones, including open streams, to finish before closing them. It
+// reports whether every RPC finished in time.
+func GracefulStop(s *grpc.Server, timeout time.Duration) bool {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		return true
+	case <-timer.C:
+		// Stop cancels the remaining RPCs, which lets GracefulStop return
+		s.Stop()
+		<-stopped
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// slowHealth answers health checks after a delay
+type slowHealth struct {
+	healthpb.UnimplementedHealthServer
+	delay time.Duration
+}
+
+func (h slowHealth) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	select {
+	case <-time.After(h.delay):
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startSlowRPC starts a server whose health checks take delay and begins
+// one check, returning the server and the channel the check's result is
+// sent on
+func startSlowRPC(t *testing.T, delay time.Duration) (*grpc.Server, <-chan error) {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, slowHealth{delay: delay})
+	go s.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		done <- err
+	}()
+	// Give the RPC time to reach the handler
+	time.Sleep(50 * time.Millisecond)
+	return s, done
+}
+
+func TestGracefulStop_DrainsInFlightRPCs(t *testing.T) {
+	s, done := startSlowRPC(t, 100*time.Millisecond)
+
+	assert.True(t, GracefulStop(s, time.Second))
+	assert.NoError(t, <-done, "the in-flight RPC should complete")
+}
+
+func TestGracefulStop_ForcesStopAfterTimeout(t *testing.T) {
+	s, done := startSlowRPC(t, time.Minute)
+
+	start := time.Now()
+	assert.False(t, GracefulStop(s, 100*time.Millisecond))
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	err := <-done
+	assert.Contains(t, []codes.Code{codes.Unavailable, codes.Canceled}, status.Code(err))
+}
+
+func TestTimeout(t *testing.T) {
+	t.Setenv("SHUTDOWN_TIMEOUT", "")
+	d, err := Timeout()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTimeout, d)
+
+	t.Setenv("SHUTDOWN_TIMEOUT", "5s")
+	d, err = Timeout()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, d)
+
+	for _, invalid := range []string{"soon", "-1s"} {
+		t.Setenv("SHUTDOWN_TIMEOUT", invalid)
+		_, err = Timeout()
+		assert.Error(t, err, invalid)
+	}
+}
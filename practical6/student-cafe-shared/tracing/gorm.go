@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormCallback is the name the span callbacks are registered under, and
+// the statement key of the span they share
+const gormCallback = "tracing"
+
+// InstrumentGORM starts a span around every query db runs, as a child of
+// the span in the query's context. Set the context with db.WithContext so
+// queries join the trace of the RPC that issued them.
+func InstrumentGORM(db *gorm.DB) error {
+	system := db.Dialector.Name()
+	cb := db.Callback()
+	registrations := []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, r := range registrations {
+		if err := r.before(gormCallback+":before_"+r.operation, startQuerySpan(system, r.operation)); err != nil {
+			return err
+		}
+		if err := r.after(gormCallback+":after_"+r.operation, endQuerySpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuerySpan(system, operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNameKey.String(system),
+				semconv.DBOperationName(operation),
+			))
+		db.Statement.Context = ctx
+		db.InstanceSet(gormCallback, span)
+	}
+}
+
+func endQuerySpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormCallback)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	// A missing row is an answer, not a failure
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
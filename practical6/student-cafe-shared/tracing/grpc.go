@@ -0,0 +1,22 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"google.golang.org/grpc"
+)
+
+// Health probes run every few seconds and would drown out real traffic
+var notHealthCheck = otelgrpc.WithFilter(filters.Not(filters.HealthCheck()))
+
+// ServerOption traces every RPC a server handles except health checks,
+// continuing the caller's trace when the request carries one
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler(notHealthCheck))
+}
+
+// DialOption traces every RPC made on a connection except health checks
+// and sends the trace context to the server
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler(notHealthCheck))
+}
@@ -0,0 +1,103 @@
+// Package tracing follows a request across the Student Cafe services with
+// OpenTelemetry.
+//
+// Setup installs a tracer provider for the process and the W3C trace
+// context propagator, so spans started by the api-gateway are continued by
+// every gRPC service it calls. ServerOption and DialOption trace gRPC
+// servers and clients through stats handlers, and InstrumentGORM adds a
+// span around every database query.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Exporters selected by the OTEL_TRACES_EXPORTER environment variable
+const (
+	// ExporterNone records no spans but still propagates trace context
+	ExporterNone = "none"
+	// ExporterOTLP sends spans over gRPC to OTEL_EXPORTER_OTLP_ENDPOINT
+	ExporterOTLP = "otlp"
+	// ExporterStdout prints spans as JSON, for local debugging
+	ExporterStdout = "stdout"
+)
+
+// instrumentation names the tracer of the spans this package starts
+const instrumentation = "student-cafe-shared/tracing"
+
+// Shutdown flushes buffered spans and stops the exporter
+type Shutdown func(ctx context.Context) error
+
+// Setup installs the tracer provider of service using the exporter named
+// by OTEL_TRACES_EXPORTER, defaulting to ExporterNone. The OTLP exporter is
+// configured by the standard OTEL_EXPORTER_OTLP_* variables.
+func Setup(ctx context.Context, service string) (Shutdown, error) {
+	name := os.Getenv("OTEL_TRACES_EXPORTER")
+	if name == "" {
+		name = ExporterNone
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name {
+	case ExporterNone:
+		Install(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("invalid OTEL_TRACES_EXPORTER %q", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s trace exporter: %w", name, err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service)))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	Install(tp)
+	return tp.Shutdown, nil
+}
+
+// Install makes tp the global tracer provider and propagates W3C trace
+// context and baggage
+func Install(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// InstallInMemory installs a tracer provider that records finished spans in
+// memory, for tests
+func InstallInMemory() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	Install(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	return exporter
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
@@ -0,0 +1,185 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type user struct {
+	ID   uint
+	Name string
+}
+
+// dbUserServer is a fake user service that looks users up with GORM
+type dbUserServer struct {
+	userv1.UnimplementedUserServiceServer
+	db *gorm.DB
+}
+
+func (s *dbUserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	var u user
+	if err := s.db.WithContext(ctx).First(&u, req.Id).Error; err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserResponse{User: &userv1.User{Id: uint32(u.ID), Name: u.Name}}, nil
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&user{}))
+	require.NoError(t, db.Create(&user{ID: 1, Name: "Alice"}).Error)
+	require.NoError(t, InstrumentGORM(db))
+	return db
+}
+
+// serve starts fake on a traced bufconn server and returns a traced client
+// connection to it
+func serve(t *testing.T, fake *dbUserServer) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(ServerOption())
+	userv1.RegisterUserServiceServer(s, fake)
+	healthpb.RegisterHealthServer(s, grpchealth.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		DialOption())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func spanNamed(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", "no span named %q in %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func attributeOf(span tracetest.SpanStub, key attribute.Key) attribute.KeyValue {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv
+		}
+	}
+	return attribute.KeyValue{}
+}
+
+func TestTracePropagation(t *testing.T) {
+	exporter := InstallInMemory()
+	conn := serve(t, &dbUserServer{db: openDB(t)})
+	exporter.Reset()
+
+	ctx, root := tracer().Start(context.Background(), "request")
+	resp, err := userv1.NewUserServiceClient(conn).GetUser(ctx, &userv1.GetUserRequest{Id: 1})
+	root.End()
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", resp.User.Name)
+
+	// request -> client call -> server call -> query, all in one trace
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	var client, server tracetest.SpanStub
+	for _, span := range spans {
+		assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext.TraceID())
+		switch {
+		case span.Name == "user.v1.UserService/GetUser" && span.SpanKind == trace.SpanKindClient:
+			client = span
+		case span.Name == "user.v1.UserService/GetUser" && span.SpanKind == trace.SpanKindServer:
+			server = span
+		}
+	}
+	assert.Equal(t, root.SpanContext().SpanID(), client.Parent.SpanID())
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+	assert.True(t, server.Parent.IsRemote())
+	query := spanNamed(t, spans, "gorm.query")
+	assert.Equal(t, server.SpanContext.SpanID(), query.Parent.SpanID())
+
+	// Health probes are not traced
+	exporter.Reset()
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, exporter.GetSpans())
+}
+
+func TestInstrumentGORM(t *testing.T) {
+	exporter := InstallInMemory()
+	db := openDB(t)
+	ctx := context.Background()
+
+	t.Run("queries are spans with their SQL", func(t *testing.T) {
+		exporter.Reset()
+		var u user
+		require.NoError(t, db.WithContext(ctx).Where("name = ?", "Alice").First(&u).Error)
+
+		span := spanNamed(t, exporter.GetSpans(), "gorm.query")
+		assert.Contains(t, attributeOf(span, "db.query.text").Value.AsString(), "FROM `users`")
+		assert.Equal(t, "users", attributeOf(span, "db.collection.name").Value.AsString())
+		assert.Equal(t, "sqlite", attributeOf(span, "db.system.name").Value.AsString())
+		assert.Equal(t, codes.Unset, span.Status.Code)
+	})
+
+	t.Run("writes are spans", func(t *testing.T) {
+		exporter.Reset()
+		require.NoError(t, db.WithContext(ctx).Create(&user{Name: "Bob"}).Error)
+		span := spanNamed(t, exporter.GetSpans(), "gorm.create")
+		assert.Equal(t, int64(1), attributeOf(span, "db.rows_affected").Value.AsInt64())
+	})
+
+	t.Run("missing rows are not errors", func(t *testing.T) {
+		exporter.Reset()
+		var u user
+		assert.ErrorIs(t, db.WithContext(ctx).First(&u, 999).Error, gorm.ErrRecordNotFound)
+		assert.Equal(t, codes.Unset, spanNamed(t, exporter.GetSpans(), "gorm.query").Status.Code)
+	})
+
+	t.Run("failed queries are recorded", func(t *testing.T) {
+		exporter.Reset()
+		assert.Error(t, db.WithContext(ctx).Exec("SELECT * FROM missing").Error)
+		span := spanNamed(t, exporter.GetSpans(), "gorm.raw")
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.NotEmpty(t, span.Events)
+	})
+}
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	for _, name := range []string{"", ExporterNone, ExporterStdout} {
+		t.Setenv("OTEL_TRACES_EXPORTER", name)
+		shutdown, err := Setup(ctx, "test-service")
+		require.NoError(t, err, name)
+		assert.NoError(t, shutdown(ctx), name)
+	}
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+	_, err := Setup(ctx, "test-service")
+	assert.Error(t, err)
+}
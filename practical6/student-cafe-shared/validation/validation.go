@@ -0,0 +1,87 @@
+// Package validation enforces the buf.validate rules declared in the
+// student-cafe-protos messages.
+//
+// Services install UnaryServerInterceptor (and StreamServerInterceptor for
+// streaming RPCs) so handlers only ever see requests that satisfy their
+// message's rules. Rejected requests fail with InvalidArgument and a
+// BadRequest detail listing every violated field.
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"student-cafe-shared/grpcerr"
+)
+
+// Validate checks msg against its validation rules
+func Validate(msg proto.Message) error {
+	err := protovalidate.Validate(msg)
+	if err == nil {
+		return nil
+	}
+
+	var verr *protovalidate.ValidationError
+	if !errors.As(err, &verr) {
+		// The rules themselves could not be compiled or evaluated
+		return status.Errorf(codes.Internal, "validating request: %v", err)
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(verr.Violations))
+	messages := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		field := protovalidate.FieldPathString(v.Proto.GetField())
+		violations = append(violations, grpcerr.Violation(field, v.Proto.GetMessage()))
+		if field != "" {
+			messages = append(messages, field+": "+v.Proto.GetMessage())
+		} else {
+			messages = append(messages, v.Proto.GetMessage())
+		}
+	}
+	return grpcerr.New(codes.InvalidArgument,
+		"invalid request: "+strings.Join(messages, "; "),
+		grpcerr.BadRequest(violations...))
+}
+
+// UnaryServerInterceptor rejects unary requests that break their message's
+// validation rules
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if err := Validate(msg); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects every message received on a stream that
+// breaks its validation rules
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatedStream{ServerStream: ss})
+	}
+}
+
+// validatedStream validates the messages received on a stream
+type validatedStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		return Validate(msg)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// violatedFields returns the fields named in err's BadRequest detail
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var fields []string
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid requests pass", func(t *testing.T) {
+		assert.NoError(t, Validate(&userv1.CreateUserRequest{
+			Name:  "Alice",
+			Email: "alice@example.com",
+		}))
+		assert.NoError(t, Validate(&orderv1.CreateOrderRequest{
+			UserId: 1,
+			Items:  []*orderv1.OrderItemRequest{{MenuItemId: 1, Quantity: 2}},
+		}))
+	})
+
+	t.Run("every violated field is reported", func(t *testing.T) {
+		err := Validate(&userv1.CreateUserRequest{
+			Email:    "not-an-email",
+			Password: "short",
+		})
+		assert.ElementsMatch(t, []string{"name", "email", "password"}, violatedFields(t, err))
+	})
+
+	t.Run("nested fields are reported by path", func(t *testing.T) {
+		err := Validate(&orderv1.CreateOrderRequest{
+			UserId: 1,
+			Items: []*orderv1.OrderItemRequest{
+				{MenuItemId: 1, Quantity: 1},
+				{MenuItemId: 2, Quantity: 0},
+			},
+		})
+		assert.Equal(t, []string{"items[1].quantity"}, violatedFields(t, err))
+	})
+
+	t.Run("empty orders are rejected", func(t *testing.T) {
+		err := Validate(&orderv1.CreateOrderRequest{UserId: 1})
+		assert.Equal(t, []string{"items"}, violatedFields(t, err))
+	})
+
+	t.Run("updates only check the fields they carry", func(t *testing.T) {
+		assert.NoError(t, Validate(&userv1.UpdateUserRequest{
+			User:       &userv1.User{Id: 1, Email: "alice@example.com"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}},
+		}))
+
+		err := Validate(&userv1.UpdateUserRequest{User: &userv1.User{Email: "alice"}})
+		assert.ElementsMatch(t, []string{"user", "user"}, violatedFields(t, err))
+	})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/CreateUser"}
+
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), &userv1.CreateUserRequest{Email: "alice@example.com"}, info, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, called, "invalid requests must not reach the handler")
+
+	_, err = interceptor(context.Background(), &userv1.CreateUserRequest{Name: "Alice", Email: "alice@example.com"}, info, handler)
+	require.NoError(t, err)
+	assert.True(t, called)
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +19,14 @@ import (
 // Test configuration
 var (
 	apiGatewayURL = getEnv("API_GATEWAY_URL", "http://localhost:8080")
+
+	// grantOwnerCommand makes the user whose email is appended to it a cafe
+	// owner, as only cafe owners may do so through the API
+	grantOwnerCommand = getEnv("GRANT_OWNER_COMMAND", "docker compose exec -T user-service /user-service grant-owner")
+
+	// accessToken authenticates makeRequest calls; TestMain logs in a test
+	// user to obtain it
+	accessToken string
 )
 
 func getEnv(key, defaultValue string) string {
@@ -36,23 +46,28 @@ type User struct {
 	UpdatedAt   string `json:"updated_at"`
 }
 
+type Money struct {
+	CurrencyCode string `json:"currency_code"`
+	AmountMinor  int64  `json:"amount_minor"`
+}
+
 type MenuItem struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       Money  `json:"price"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
 }
 
 type OrderItem struct {
-	ID         uint    `json:"id"`
-	OrderID    uint    `json:"order_id"`
-	MenuItemID uint    `json:"menu_item_id"`
-	Quantity   int     `json:"quantity"`
-	Price      float64 `json:"price"`
-	CreatedAt  string  `json:"created_at"`
-	UpdatedAt  string  `json:"updated_at"`
+	ID         uint   `json:"id"`
+	OrderID    uint   `json:"order_id"`
+	MenuItemID uint   `json:"menu_item_id"`
+	Quantity   int    `json:"quantity"`
+	Price      Money  `json:"price"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
 }
 
 type Order struct {
@@ -60,10 +75,27 @@ type Order struct {
 	UserID     uint        `json:"user_id"`
 	Status     string      `json:"status"`
 	OrderItems []OrderItem `json:"order_items"`
+	Subtotal   Money       `json:"subtotal"`
+	Tax        Money       `json:"tax"`
+	Total      Money       `json:"total"`
 	CreatedAt  string      `json:"created_at"`
 	UpdatedAt  string      `json:"updated_at"`
 }
 
+type ErrorResponse struct {
+	Error struct {
+		Code            string            `json:"code"`
+		Message         string            `json:"message"`
+		RequestID       string            `json:"request_id"`
+		Reason          string            `json:"reason"`
+		Metadata        map[string]string `json:"metadata"`
+		FieldViolations []struct {
+			Field       string `json:"field"`
+			Description string `json:"description"`
+		} `json:"field_violations"`
+	} `json:"error"`
+}
+
 // Helper functions
 func makeRequest(method, path string, body interface{}) (*http.Response, error) {
 	var bodyReader io.Reader
@@ -83,17 +115,66 @@ func makeRequest(method, path string, body interface{}) (*http.Response, error)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	return client.Do(req)
 }
 
+// login creates a cafe owner with the given email and returns an access
+// token for it
+func login(email string) (string, error) {
+	const password = "e2e-test-password"
+
+	resp, err := makeRequest("POST", "/api/users", map[string]interface{}{
+		"name":     "E2E Auth User",
+		"email":    email,
+		"password": password,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("create user: status %d", resp.StatusCode)
+	}
+
+	args := append(strings.Fields(grantOwnerCommand), email)
+	if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("grant cafe owner: %v: %s", err, out)
+	}
+
+	resp, err = makeRequest("POST", "/api/auth/login", map[string]interface{}{
+		"email":    email,
+		"password": password,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("login: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Tokens struct {
+			AccessToken string `json:"access_token"`
+		} `json:"tokens"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.Tokens.AccessToken, nil
+}
+
 func TestMain(m *testing.M) {
 	// Wait for services to be ready
 	fmt.Println("Waiting for services to be ready...")
 	maxRetries := 30
 	for i := 0; i < maxRetries; i++ {
-		resp, err := http.Get(apiGatewayURL + "/api/users")
+		resp, err := http.Get(apiGatewayURL + "/api/menu")
 		if err == nil && resp.StatusCode < 500 {
 			resp.Body.Close()
 			fmt.Println("Services are ready!")
@@ -106,6 +187,14 @@ func TestMain(m *testing.M) {
 		time.Sleep(2 * time.Second)
 	}
 
+	// Sign up and log in a cafe owner for the tests
+	token, err := login(fmt.Sprintf("e2e-auth-%d@test.com", time.Now().UnixNano()))
+	if err != nil {
+		fmt.Printf("Failed to log in test user: %v\n", err)
+		os.Exit(1)
+	}
+	accessToken = token
+
 	// Run tests
 	code := m.Run()
 	os.Exit(code)
@@ -120,6 +209,29 @@ func TestE2E_HealthCheck(t *testing.T) {
 	assert.Less(t, resp.StatusCode, 500, "API Gateway should be accessible")
 }
 
+func TestE2E_AuthenticationRequired(t *testing.T) {
+	// Without a token protected routes are rejected
+	resp, err := http.Get(apiGatewayURL + "/api/users")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Invalid tokens are rejected even on public routes
+	req, err := http.NewRequest("GET", apiGatewayURL+"/api/menu", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// The menu can be browsed anonymously
+	resp, err = http.Get(apiGatewayURL + "/api/menu")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
 func TestE2E_CreateUser(t *testing.T) {
 	reqBody := map[string]interface{}{
 		"name":          "E2E Test User",
@@ -143,6 +255,18 @@ func TestE2E_CreateUser(t *testing.T) {
 	assert.Equal(t, reqBody["is_cafe_owner"], user.IsCafeOwner)
 	assert.NotEmpty(t, user.CreatedAt)
 	assert.NotEmpty(t, user.UpdatedAt)
+
+	// Signing up again with the same email is a conflict
+	dupResp, err := makeRequest("POST", "/api/users", reqBody)
+	require.NoError(t, err)
+	defer dupResp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, dupResp.StatusCode)
+
+	var errResp ErrorResponse
+	require.NoError(t, json.NewDecoder(dupResp.Body).Decode(&errResp))
+	assert.Equal(t, "ALREADY_EXISTS", errResp.Error.Code)
+	assert.Equal(t, "EMAIL_TAKEN", errResp.Error.Reason)
 }
 
 func TestE2E_GetUsers(t *testing.T) {
@@ -223,7 +347,7 @@ func TestE2E_CreateMenuItem(t *testing.T) {
 	assert.NotZero(t, item.ID)
 	assert.Equal(t, reqBody["name"], item.Name)
 	assert.Equal(t, reqBody["description"], item.Description)
-	assert.InDelta(t, reqBody["price"], item.Price, 0.01)
+	assert.Equal(t, int64(450), item.Price.AmountMinor)
 }
 
 func TestE2E_GetMenu(t *testing.T) {
@@ -322,8 +446,9 @@ func TestE2E_CompleteOrderFlow(t *testing.T) {
 	assert.Len(t, order.OrderItems, 2)
 
 	// Verify prices were snapshotted
-	assert.InDelta(t, 2.50, order.OrderItems[0].Price, 0.01)
-	assert.InDelta(t, 5.00, order.OrderItems[1].Price, 0.01)
+	assert.Equal(t, int64(250), order.OrderItems[0].Price.AmountMinor)
+	assert.Equal(t, int64(500), order.OrderItems[1].Price.AmountMinor)
+	assert.Equal(t, int64(1000), order.Subtotal.AmountMinor)
 
 	// Step 4: Retrieve the order
 	getOrderResp, err := makeRequest("GET", fmt.Sprintf("/api/orders/%d", order.ID), nil)
@@ -386,6 +511,14 @@ func TestE2E_OrderValidation(t *testing.T) {
 		defer resp.Body.Close()
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var errResp ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Equal(t, "INVALID_ARGUMENT", errResp.Error.Code)
+		assert.Equal(t, "MENU_ITEM_NOT_FOUND", errResp.Error.Reason)
+		assert.NotEmpty(t, errResp.Error.RequestID)
+		require.Len(t, errResp.Error.FieldViolations, 1)
+		assert.Equal(t, "items[0].menu_item_id", errResp.Error.FieldViolations[0].Field)
 	})
 }
 
@@ -5,32 +5,36 @@ import (
 	"log"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
 
+	commonv1 "github.com/douglasswm/student-cafe-protos/gen/go/common/v1"
 	menuv1 "github.com/douglasswm/student-cafe-protos/gen/go/menu/v1"
 	orderv1 "github.com/douglasswm/student-cafe-protos/gen/go/order/v1"
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	// Import actual service implementations
-	menudatabase "menu-service/database"
 	menugrpc "menu-service/grpc"
-	menumodels "menu-service/models"
+	menurepository "menu-service/repository"
 
-	orderdatabase "order-service/database"
 	ordergrpc "order-service/grpc"
-	ordermodels "order-service/models"
+	orderrepository "order-service/repository"
 
-	userdatabase "user-service/database"
 	usergrpc "user-service/grpc"
-	usermodels "user-service/models"
+	userrepository "user-service/repository"
 
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+	"student-cafe-shared/validation"
 )
 
 const bufSize = 1024 * 1024
@@ -39,23 +43,37 @@ var (
 	userListener  *bufconn.Listener
 	menuListener  *bufconn.Listener
 	orderListener *bufconn.Listener
+
+	// userTokens signs the tokens of the running user service
+	userTokens *auth.TokenIssuer
+
+	// cafeOwner is the caller the tests act as, as if forwarded by the
+	// api-gateway
+	cafeOwner = auth.Identity{UserID: 1, Role: auth.RoleCafeOwner}
 )
 
-// setupUserService creates and starts the user service
+// setupUserService creates and starts the user service with in-memory
+// storage of its own
 func setupUserService(t *testing.T) {
-	// Setup in-memory database
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	// Sign tokens with a key loaded from a temporary file, like main does
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "jwt-private.pem")
+	require.NoError(t, auth.WriteKeyPair(keyPath, filepath.Join(dir, "jwt-public.pem")))
+	key, err := auth.LoadPrivateKey(keyPath)
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&usermodels.User{})
-	require.NoError(t, err)
-
-	userdatabase.DB = db
+	userServer := usergrpc.NewUserServer(userrepository.NewMemoryUserRepository())
+	userServer.Tokens = auth.NewTokenIssuer(key)
+	userTokens = userServer.Tokens
 
 	// Create gRPC server with bufconn
 	userListener = bufconn.Listen(bufSize)
-	s := grpc.NewServer()
-	userv1.RegisterUserServiceServer(s, usergrpc.NewUserServer())
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		auth.UnaryServerInterceptor(),
+		validation.UnaryServerInterceptor(),
+		authz.UnaryServerInterceptor(usergrpc.Policy()),
+	))
+	userv1.RegisterUserServiceServer(s, userServer)
 
 	go func() {
 		if err := s.Serve(userListener); err != nil {
@@ -64,21 +82,17 @@ func setupUserService(t *testing.T) {
 	}()
 }
 
-// setupMenuService creates and starts the menu service
+// setupMenuService creates and starts the menu service with in-memory
+// storage of its own
 func setupMenuService(t *testing.T) {
-	// Setup in-memory database
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	require.NoError(t, err)
-
-	err = db.AutoMigrate(&menumodels.MenuItem{})
-	require.NoError(t, err)
-
-	menudatabase.DB = db
-
 	// Create gRPC server with bufconn
 	menuListener = bufconn.Listen(bufSize)
-	s := grpc.NewServer()
-	menuv1.RegisterMenuServiceServer(s, menugrpc.NewMenuServer())
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		auth.UnaryServerInterceptor(),
+		validation.UnaryServerInterceptor(),
+		authz.UnaryServerInterceptor(menugrpc.Policy()),
+	))
+	menuv1.RegisterMenuServiceServer(s, menugrpc.NewMenuServer(menurepository.NewMemoryMenuRepository()))
 
 	go func() {
 		if err := s.Serve(menuListener); err != nil {
@@ -87,26 +101,32 @@ func setupMenuService(t *testing.T) {
 	}()
 }
 
-// setupOrderService creates and starts the order service with clients to user and menu services
+// setupOrderService creates and starts the order service with in-memory
+// storage of its own and clients to the user and menu services
 func setupOrderService(t *testing.T, userConn, menuConn *grpc.ClientConn) {
-	// Setup in-memory database
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	require.NoError(t, err)
-
-	err = db.AutoMigrate(&ordermodels.Order{}, &ordermodels.OrderItem{})
-	require.NoError(t, err)
-
-	orderdatabase.DB = db
-
 	// Create order server with injected clients
+	orders := orderrepository.NewMemoryOrderRepository()
 	orderServer := &ordergrpc.OrderServer{
 		UserClient: userv1.NewUserServiceClient(userConn),
 		MenuClient: menuv1.NewMenuServiceClient(menuConn),
+		Orders:     orders,
 	}
 
 	// Create gRPC server with bufconn
 	orderListener = bufconn.Listen(bufSize)
-	s := grpc.NewServer()
+	policy := ordergrpc.Policy(orders)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			auth.UnaryServerInterceptor(),
+			validation.UnaryServerInterceptor(),
+			authz.UnaryServerInterceptor(policy),
+		),
+		grpc.ChainStreamInterceptor(
+			auth.StreamServerInterceptor(),
+			validation.StreamServerInterceptor(),
+			authz.StreamServerInterceptor(policy),
+		),
+	)
 	orderv1.RegisterOrderServiceServer(s, orderServer)
 
 	go func() {
@@ -135,7 +155,8 @@ func TestIntegration_CreateUser(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(userListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer conn.Close()
 
@@ -154,13 +175,55 @@ func TestIntegration_CreateUser(t *testing.T) {
 	assert.Equal(t, "integration@test.com", resp.User.Email)
 }
 
+func TestIntegration_LoginAndRefresh(t *testing.T) {
+	setupUserService(t)
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(userListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := userv1.NewUserServiceClient(conn)
+
+	created, err := client.CreateUser(ctx, &userv1.CreateUserRequest{
+		Name:     "Login Test User",
+		Email:    "login@test.com",
+		Password: "integration-secret",
+	})
+	require.NoError(t, err)
+
+	login, err := client.Login(ctx, &userv1.LoginRequest{Email: "login@test.com", Password: "integration-secret"})
+	require.NoError(t, err)
+	assert.Equal(t, created.User.Id, login.User.Id)
+
+	identity, err := userTokens.Verifier().Verify(login.Tokens.AccessToken, auth.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, auth.Identity{UserID: created.User.Id, Role: auth.RoleStudent}, identity)
+
+	refreshed, err := client.RefreshToken(ctx, &userv1.RefreshTokenRequest{RefreshToken: login.Tokens.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEmpty(t, refreshed.Tokens.AccessToken)
+
+	_, err = client.Login(ctx, &userv1.LoginRequest{Email: "login@test.com", Password: "wrong-password"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// The server interceptor rejects malformed identity metadata
+	badCtx := metadata.AppendToOutgoingContext(ctx, auth.UserIDMetadataKey, "not-a-number")
+	_, err = client.GetUser(badCtx, &userv1.GetUserRequest{Id: created.User.Id})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
 func TestIntegration_CreateAndGetMenuItem(t *testing.T) {
 	setupMenuService(t)
 
-	ctx := context.Background()
+	ctx := auth.NewContext(context.Background(), cafeOwner)
 	conn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(menuListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer conn.Close()
 
@@ -170,7 +233,7 @@ func TestIntegration_CreateAndGetMenuItem(t *testing.T) {
 	createResp, err := client.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
 		Name:        "Integration Coffee",
 		Description: "Test coffee",
-		Price:       3.50,
+		Price:       &commonv1.Money{AmountMinor: 350},
 	})
 
 	require.NoError(t, err)
@@ -184,7 +247,7 @@ func TestIntegration_CreateAndGetMenuItem(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, createResp.MenuItem.Id, getResp.MenuItem.Id)
 	assert.Equal(t, "Integration Coffee", getResp.MenuItem.Name)
-	assert.InDelta(t, 3.50, getResp.MenuItem.Price, 0.001)
+	assert.Equal(t, int64(350), getResp.MenuItem.Price.AmountMinor)
 }
 
 func TestIntegration_CompleteOrderFlow(t *testing.T) {
@@ -192,12 +255,13 @@ func TestIntegration_CompleteOrderFlow(t *testing.T) {
 	setupUserService(t)
 	setupMenuService(t)
 
-	ctx := context.Background()
+	ctx := auth.NewContext(context.Background(), cafeOwner)
 
 	// Connect to user service
 	userConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(userListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer userConn.Close()
 
@@ -206,7 +270,8 @@ func TestIntegration_CompleteOrderFlow(t *testing.T) {
 	// Connect to menu service
 	menuConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(menuListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer menuConn.Close()
 
@@ -218,7 +283,8 @@ func TestIntegration_CompleteOrderFlow(t *testing.T) {
 	// Connect to order service
 	orderConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(orderListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer orderConn.Close()
 
@@ -237,14 +303,14 @@ func TestIntegration_CompleteOrderFlow(t *testing.T) {
 	item1, err := menuClient.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
 		Name:        "Coffee",
 		Description: "Hot coffee",
-		Price:       2.50,
+		Price:       &commonv1.Money{AmountMinor: 250},
 	})
 	require.NoError(t, err)
 
 	item2, err := menuClient.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
 		Name:        "Sandwich",
 		Description: "Ham sandwich",
-		Price:       5.00,
+		Price:       &commonv1.Money{AmountMinor: 500},
 	})
 	require.NoError(t, err)
 
@@ -264,8 +330,11 @@ func TestIntegration_CompleteOrderFlow(t *testing.T) {
 	assert.Len(t, orderResp.Order.OrderItems, 2)
 
 	// Verify prices were snapshotted
-	assert.InDelta(t, 2.50, orderResp.Order.OrderItems[0].Price, 0.001)
-	assert.InDelta(t, 5.00, orderResp.Order.OrderItems[1].Price, 0.001)
+	assert.Equal(t, int64(250), orderResp.Order.OrderItems[0].Price.AmountMinor)
+	assert.Equal(t, int64(500), orderResp.Order.OrderItems[1].Price.AmountMinor)
+
+	// Verify totals were computed by the order service
+	assert.Equal(t, int64(1000), orderResp.Order.Total.AmountMinor)
 
 	// Step 4: Retrieve the order
 	getOrderResp, err := orderClient.GetOrder(ctx, &orderv1.GetOrderRequest{
@@ -282,18 +351,20 @@ func TestIntegration_OrderValidation(t *testing.T) {
 	setupUserService(t)
 	setupMenuService(t)
 
-	ctx := context.Background()
+	ctx := auth.NewContext(context.Background(), cafeOwner)
 
 	// Connect to services
 	userConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(userListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer userConn.Close()
 
 	menuConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(menuListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer menuConn.Close()
 
@@ -301,7 +372,8 @@ func TestIntegration_OrderValidation(t *testing.T) {
 
 	orderConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(orderListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer orderConn.Close()
 
@@ -341,6 +413,214 @@ func TestIntegration_OrderValidation(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "menu item 9999 not found")
 	})
+
+	// Requests breaking the proto validation rules never reach the server
+	t.Run("request rules", func(t *testing.T) {
+		violations := func(err error) []string {
+			st := status.Convert(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			var fields []string
+			for _, detail := range st.Details() {
+				if br, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range br.FieldViolations {
+						fields = append(fields, v.Field)
+					}
+				}
+			}
+			return fields
+		}
+
+		_, err := orderClient.CreateOrder(ctx, &orderv1.CreateOrderRequest{UserId: userResp.User.Id})
+		assert.Equal(t, []string{"items"}, violations(err))
+
+		_, err = orderClient.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+			UserId: userResp.User.Id,
+			Items:  []*orderv1.OrderItemRequest{{MenuItemId: 1, Quantity: -1}},
+		})
+		assert.Equal(t, []string{"items[0].quantity"}, violations(err))
+
+		_, err = userClient.CreateUser(ctx, &userv1.CreateUserRequest{Email: "not-an-email"})
+		assert.ElementsMatch(t, []string{"name", "email"}, violations(err))
+
+		menuClient := menuv1.NewMenuServiceClient(menuConn)
+		_, err = menuClient.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+			Name:  "Refund Coffee",
+			Price: &commonv1.Money{CurrencyCode: "USD", AmountMinor: -100},
+		})
+		assert.Equal(t, []string{"price.amount_minor"}, violations(err))
+	})
+}
+
+func TestIntegration_StockTracking(t *testing.T) {
+	// Setup all three services
+	setupUserService(t)
+	setupMenuService(t)
+
+	ctx := auth.NewContext(context.Background(), cafeOwner)
+
+	userConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(userListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer userConn.Close()
+
+	menuConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(menuListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer menuConn.Close()
+
+	setupOrderService(t, userConn, menuConn)
+
+	orderConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(orderListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer orderConn.Close()
+
+	userClient := userv1.NewUserServiceClient(userConn)
+	menuClient := menuv1.NewMenuServiceClient(menuConn)
+	orderClient := orderv1.NewOrderServiceClient(orderConn)
+
+	userResp, err := userClient.CreateUser(ctx, &userv1.CreateUserRequest{
+		Name:  "Stock User",
+		Email: "stock@test.com",
+	})
+	require.NoError(t, err)
+
+	stock := int32(3)
+	itemResp, err := menuClient.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+		Name:  "Limited Muffin",
+		Price: &commonv1.Money{AmountMinor: 300},
+		Stock: &stock,
+	})
+	require.NoError(t, err)
+	itemID := itemResp.MenuItem.Id
+
+	orderTwo := func() (*orderv1.CreateOrderResponse, error) {
+		return orderClient.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+			UserId: userResp.User.Id,
+			Items:  []*orderv1.OrderItemRequest{{MenuItemId: itemID, Quantity: 2}},
+		})
+	}
+	stockLeft := func() int32 {
+		resp, err := menuClient.GetMenuItem(ctx, &menuv1.GetMenuItemRequest{Id: itemID})
+		require.NoError(t, err)
+		return resp.MenuItem.GetStock()
+	}
+
+	// Placing an order decrements stock
+	first, err := orderTwo()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), stockLeft())
+
+	// Not enough left for a second order
+	_, err = orderTwo()
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, int32(1), stockLeft())
+
+	// Cancelling the first order returns its stock
+	_, err = orderClient.CancelOrder(ctx, &orderv1.CancelOrderRequest{Id: first.Order.Id})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), stockLeft())
+
+	// Unavailable items cannot be ordered regardless of stock
+	_, err = menuClient.SetMenuItemAvailability(ctx, &menuv1.SetMenuItemAvailabilityRequest{Id: itemID, Available: false})
+	require.NoError(t, err)
+	_, err = orderTwo()
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), "not available")
+}
+
+func TestIntegration_Authorization(t *testing.T) {
+	// Setup all three services
+	setupUserService(t)
+	setupMenuService(t)
+
+	ctx := auth.NewContext(context.Background(), cafeOwner)
+
+	userConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(userListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer userConn.Close()
+
+	menuConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(menuListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer menuConn.Close()
+
+	setupOrderService(t, userConn, menuConn)
+
+	orderConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufDialer(orderListener)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
+	require.NoError(t, err)
+	defer orderConn.Close()
+
+	userClient := userv1.NewUserServiceClient(userConn)
+	menuClient := menuv1.NewMenuServiceClient(menuConn)
+	orderClient := orderv1.NewOrderServiceClient(orderConn)
+
+	alice, err := userClient.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Alice", Email: "alice-authz@test.com"})
+	require.NoError(t, err)
+	bob, err := userClient.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Bob", Email: "bob-authz@test.com"})
+	require.NoError(t, err)
+	asAlice := auth.NewContext(context.Background(), auth.Identity{UserID: alice.User.Id, Role: auth.RoleStudent})
+
+	item, err := menuClient.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
+		Name:  "Authz Tea",
+		Price: &commonv1.Money{AmountMinor: 200},
+	})
+	require.NoError(t, err)
+	items := []*orderv1.OrderItemRequest{{MenuItemId: item.MenuItem.Id, Quantity: 1}}
+
+	// Students cannot change the menu
+	_, err = menuClient.CreateMenuItem(asAlice, &menuv1.CreateMenuItemRequest{
+		Name:  "Free Coffee",
+		Price: &commonv1.Money{AmountMinor: 0},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Students order for themselves; their identity is forwarded when
+	// order-service reserves stock
+	aliceOrder, err := orderClient.CreateOrder(asAlice, &orderv1.CreateOrderRequest{UserId: alice.User.Id, Items: items})
+	require.NoError(t, err)
+	_, err = orderClient.CreateOrder(asAlice, &orderv1.CreateOrderRequest{UserId: bob.User.Id, Items: items})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	bobOrder, err := orderClient.CreateOrder(ctx, &orderv1.CreateOrderRequest{UserId: bob.User.Id, Items: items})
+	require.NoError(t, err)
+
+	// Students read only their own orders
+	_, err = orderClient.GetOrder(asAlice, &orderv1.GetOrderRequest{Id: aliceOrder.Order.Id})
+	assert.NoError(t, err)
+	_, err = orderClient.GetOrder(asAlice, &orderv1.GetOrderRequest{Id: bobOrder.Order.Id})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	list, err := orderClient.GetOrders(asAlice, &orderv1.GetOrdersRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Orders, 1)
+	assert.Equal(t, aliceOrder.Order.Id, list.Orders[0].Id)
+
+	// Only cafe owners advance orders
+	_, err = orderClient.UpdateOrderStatus(asAlice, &orderv1.UpdateOrderStatusRequest{Id: aliceOrder.Order.Id, Status: "confirmed"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = orderClient.UpdateOrderStatus(ctx, &orderv1.UpdateOrderStatusRequest{Id: aliceOrder.Order.Id, Status: "confirmed"})
+	assert.NoError(t, err)
+
+	// Calls without an identity are rejected
+	_, err = orderClient.GetOrders(context.Background(), &orderv1.GetOrdersRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
 }
 
 func TestIntegration_ConcurrentOrders(t *testing.T) {
@@ -351,17 +631,19 @@ func TestIntegration_ConcurrentOrders(t *testing.T) {
 	setupMenuService(t)
 	defer menuListener.Close()
 
-	ctx := context.Background()
+	ctx := auth.NewContext(context.Background(), cafeOwner)
 
 	userConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(userListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer userConn.Close()
 
 	menuConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(menuListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer menuConn.Close()
 
@@ -369,7 +651,8 @@ func TestIntegration_ConcurrentOrders(t *testing.T) {
 
 	orderConn, err := grpc.DialContext(ctx, "bufnet",
 		grpc.WithContextDialer(bufDialer(orderListener)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()))
 	require.NoError(t, err)
 	defer orderConn.Close()
 
@@ -388,7 +671,7 @@ func TestIntegration_ConcurrentOrders(t *testing.T) {
 	itemResp, err := menuClient.CreateMenuItem(ctx, &menuv1.CreateMenuItemRequest{
 		Name:        "Test Item",
 		Description: "For concurrent testing",
-		Price:       1.00,
+		Price:       &commonv1.Money{AmountMinor: 100},
 	})
 	require.NoError(t, err)
 
@@ -1,28 +1,63 @@
 package database
 
 import (
-	"log"
-	"user-service/models"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"student-cafe-shared/migrate"
+	"student-cafe-shared/tracing"
 )
 
-var DB *gorm.DB
+// MigrationsDir is the source directory of the user database migrations,
+// relative to the service module
+const MigrationsDir = "database/migrations"
 
-func Connect(dsn string) error {
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations returns the SQL migrations built into the binary
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrationFiles, "migrations")
 	if err != nil {
-		return err
+		panic(err)
 	}
+	return sub
+}
 
-	// Only migrate user-related tables
-	err = DB.AutoMigrate(&models.User{})
+// Open opens the user database without checking its schema. Every query is
+// traced as a child of the span in its context.
+func Open(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Connect opens the user database and refuses to use it unless its schema
+// is at exactly the version of the migrations built into the binary
+func Connect(dsn string) (*gorm.DB, error) {
+	db, err := Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrate.New(db, Migrations())
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		return nil, fmt.Errorf("checking user database schema: %w", err)
 	}
 
-	log.Println("User database connected")
-	return nil
+	slog.Info("User database connected", "schema_version", migrator.Latest())
+	return db, nil
 }
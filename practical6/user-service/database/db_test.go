@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+	"user-service/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"student-cafe-shared/migrate"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := migrate.Load(Migrations())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, "0001_baseline", migrations[0].String())
+}
+
+func TestBaseline_UpAndDown(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+	ctx := context.Background()
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	drop := func() {
+		require.NoError(t, db.Migrator().DropTable("users", "schema_migrations"))
+	}
+	drop()
+	t.Cleanup(drop)
+
+	migrator, err := migrate.New(db, Migrations())
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	// The schema accepts everything the model writes
+	require.NoError(t, db.Create(&models.User{Name: "Alice", Email: "alice@example.com", PasswordHash: "hash"}).Error)
+	assert.Error(t, db.Create(&models.User{Name: "Alice", Email: "alice@example.com"}).Error)
+
+	_, err = migrator.Down(ctx, int(migrator.Latest()))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable(&models.User{}))
+}
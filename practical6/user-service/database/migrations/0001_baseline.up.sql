@@ -0,0 +1,18 @@
+-- Baseline schema. Databases created by the AutoMigrate calls this service
+-- used to make at startup already have some of it, so every statement is
+-- written to adopt an existing table rather than fail on it.
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name TEXT,
+    email TEXT CONSTRAINT uni_users_email UNIQUE,
+    is_cafe_owner BOOLEAN
+);
+
+-- Columns added after the first release
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
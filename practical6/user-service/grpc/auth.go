@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/grpcerr"
+	"user-service/models"
+)
+
+// MinPasswordLength is the shortest password CreateUser accepts
+const MinPasswordLength = 8
+
+// hashPassword returns the bcrypt hash stored for a new password
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", grpcerr.InvalidField("password", "password must be at least %d characters", MinPasswordLength)
+	}
+	// bcrypt ignores everything after 72 bytes, so longer passwords would
+	// silently match any suffix
+	if len(password) > 72 {
+		return "", grpcerr.InvalidField("password", "password must be at most 72 bytes")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to hash password: %v", err)
+	}
+	return string(hash), nil
+}
+
+// Login exchanges an email and password for access and refresh tokens
+func (s *UserServer) Login(ctx context.Context, req *userv1.LoginRequest) (*userv1.LoginResponse, error) {
+	if s.Tokens == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "token signing is not configured")
+	}
+
+	// Unknown emails and wrong passwords get the same error so callers
+	// cannot probe which emails are registered
+	user, err := s.Users.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid email or password")
+		}
+		return nil, dberr.Error(err, "failed to get user")
+	}
+	if user.PasswordHash == "" ||
+		bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid email or password")
+	}
+
+	tokens, err := s.issueTokens(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.LoginResponse{
+		Tokens: tokens,
+		User:   modelToProto(user),
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for new access and refresh tokens.
+// The user is reloaded so role changes and deletions take effect.
+func (s *UserServer) RefreshToken(ctx context.Context, req *userv1.RefreshTokenRequest) (*userv1.RefreshTokenResponse, error) {
+	if s.Tokens == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "token signing is not configured")
+	}
+
+	identity, err := s.Tokens.Verifier().Verify(req.RefreshToken, auth.RefreshToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+	}
+
+	user, err := s.Users.Get(ctx, uint(identity.UserID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "user no longer exists")
+		}
+		return nil, dberr.Error(err, "failed to get user")
+	}
+
+	tokens, err := s.issueTokens(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.RefreshTokenResponse{
+		Tokens: tokens,
+	}, nil
+}
+
+// issueTokens signs a new token pair for user
+func (s *UserServer) issueTokens(user *models.User) (*userv1.AuthTokens, error) {
+	pair, err := s.Tokens.Issue(auth.Identity{
+		UserID: uint32(user.ID),
+		Role:   auth.RoleFor(user.IsCafeOwner),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign tokens: %v", err)
+	}
+
+	return &userv1.AuthTokens{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(pair.ExpiresIn.Seconds()),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+
+	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+)
+
+// Policy returns the authorization policy of the UserService: users may read
+// and update their own account, and cafe owners may do anything; only they
+// may list or delete users, or grant and revoke is_cafe_owner. Sign-up and
+// login stay public, so CreateUser checks is_cafe_owner itself, and
+// BatchGetUsers leaves out the users a caller may not read.
+func Policy() authz.Policy {
+	ownerOnly := authz.RequireRole(auth.RoleCafeOwner)
+	anyUser := authz.RequireRole(auth.RoleCafeOwner, auth.RoleStudent)
+	return authz.Policy{
+		userv1.UserService_GetUser_FullMethodName:       authz.RoleOrOwner(auth.RoleCafeOwner, requestUser),
+		userv1.UserService_BatchGetUsers_FullMethodName: anyUser,
+		userv1.UserService_GetUsers_FullMethodName:      ownerOnly,
+		userv1.UserService_UpdateUser_FullMethodName:    authorizeUpdate,
+		userv1.UserService_DeleteUser_FullMethodName:    ownerOnly,
+	}
+}
+
+// requestUser returns the user a GetUser request refers to
+func requestUser(ctx context.Context, req any) (uint32, error) {
+	return req.(*userv1.GetUserRequest).Id, nil
+}
+
+// canRead reports whether the caller in ctx may read user id: cafe owners
+// may read anyone, everyone else only themselves
+func canRead(ctx context.Context, id uint32) bool {
+	caller, ok := auth.FromContext(ctx)
+	return ok && (caller.Role == auth.RoleCafeOwner || caller.UserID == id)
+}
+
+// authorizeUpdate lets users update their own name and email. Naming
+// is_cafe_owner in the update mask, or setting it with an empty mask, which
+// replaces every field, is left to cafe owners.
+func authorizeUpdate(ctx context.Context, caller auth.Identity, req any) error {
+	if caller.Role == auth.RoleCafeOwner {
+		return nil
+	}
+
+	update := req.(*userv1.UpdateUserRequest)
+	if update.GetUser().GetId() != caller.UserID {
+		return status.Errorf(codes.PermissionDenied, "not allowed to access another user's resources")
+	}
+	paths := update.GetUpdateMask().GetPaths()
+	if slices.Contains(paths, "is_cafe_owner") || (len(paths) == 0 && update.GetUser().GetIsCafeOwner()) {
+		return errOwnerOnlyField
+	}
+	return nil
+}
+
+// authorizeSignUp allows anyone to create a user, but only cafe owners to
+// create another cafe owner
+func authorizeSignUp(ctx context.Context, req *userv1.CreateUserRequest) error {
+	if !req.IsCafeOwner {
+		return nil
+	}
+	if caller, ok := auth.FromContext(ctx); !ok || caller.Role != auth.RoleCafeOwner {
+		return errOwnerOnlyField
+	}
+	return nil
+}
+
+var errOwnerOnlyField = status.Error(codes.PermissionDenied, "only cafe owners may set is_cafe_owner")
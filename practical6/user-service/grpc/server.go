@@ -2,36 +2,65 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
-	"user-service/database"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/fieldmask"
+	"student-cafe-shared/grpcerr"
+	"student-cafe-shared/pagination"
 	"user-service/models"
+	"user-service/repository"
 )
 
 // UserServer implements the gRPC UserService
 type UserServer struct {
 	userv1.UnimplementedUserServiceServer
+
+	// Users stores the service's users
+	Users repository.UserRepository
+
+	// Tokens signs the access and refresh tokens returned by Login and
+	// RefreshToken; both RPCs fail while it is nil
+	Tokens *auth.TokenIssuer
 }
 
-// NewUserServer creates a new gRPC user server
-func NewUserServer() *UserServer {
-	return &UserServer{}
+// NewUserServer creates a new gRPC user server backed by users
+func NewUserServer(users repository.UserRepository) *UserServer {
+	return &UserServer{Users: users}
 }
 
 // CreateUser creates a new user
 func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.CreateUserResponse, error) {
+	if err := authorizeSignUp(ctx, req); err != nil {
+		return nil, err
+	}
+
 	user := models.User{
 		Name:        req.Name,
 		Email:       req.Email,
 		IsCafeOwner: req.IsCafeOwner,
 	}
 
-	if err := database.DB.Create(&user).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+	if req.Password != "" {
+		hash, err := hashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+	}
+
+	if err := s.Users.Create(ctx, &user); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return nil, emailTakenError(req.Email)
+		}
+		return nil, dberr.Error(err, "failed to create user")
 	}
 
 	return &userv1.CreateUserResponse{
@@ -39,26 +68,83 @@ func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserReque
 	}, nil
 }
 
+// emailTakenError reports a unique violation on users.email, the only
+// unique column of users
+func emailTakenError(email string) error {
+	return grpcerr.New(codes.AlreadyExists, fmt.Sprintf("a user with email %s already exists", email),
+		grpcerr.Info("EMAIL_TAKEN", map[string]string{"email": email}))
+}
+
 // GetUser retrieves a user by ID
 func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
-	var user models.User
-	if err := database.DB.First(&user, req.Id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	user, err := s.Users.Get(ctx, uint(req.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "user not found")
 		}
-		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+		return nil, dberr.Error(err, "failed to get user")
 	}
 
 	return &userv1.GetUserResponse{
-		User: modelToProto(&user),
+		User: modelToProto(user),
 	}, nil
 }
 
-// GetUsers retrieves all users
+// BatchGetUsers retrieves several users by ID in one query and reports the
+// IDs that do not exist. Users the caller may not read are reported missing
+// too, so one of them does not fail the whole batch.
+func (s *UserServer) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersRequest) (*userv1.BatchGetUsersResponse, error) {
+	ids := make([]uint, 0, len(req.Ids))
+	for _, id := range req.Ids {
+		if canRead(ctx, id) {
+			ids = append(ids, uint(id))
+		}
+	}
+	users, err := s.Users.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, dberr.Error(err, "failed to get users")
+	}
+
+	found := make(map[uint32]*models.User, len(users))
+	for i := range users {
+		found[uint32(users[i].ID)] = &users[i]
+	}
+
+	resp := &userv1.BatchGetUsersResponse{}
+	seen := make(map[uint32]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user, ok := found[id]; ok {
+			resp.Users = append(resp.Users, modelToProto(user))
+		} else {
+			resp.MissingIds = append(resp.MissingIds, id)
+		}
+	}
+	return resp, nil
+}
+
+// GetUsers retrieves a page of users, optionally filtered by is_cafe_owner
 func (s *UserServer) GetUsers(ctx context.Context, req *userv1.GetUsersRequest) (*userv1.GetUsersResponse, error) {
-	var users []models.User
-	if err := database.DB.Find(&users).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get users: %v", err)
+	sort, err := pagination.ParseSort(req.OrderBy, repository.SortFields, pagination.Sort{Field: "id", Column: "id"})
+	if err != nil {
+		return nil, grpcerr.InvalidField("order_by", "%v", err)
+	}
+
+	users, nextPageToken, err := s.Users.List(ctx, repository.UserFilter{
+		IsCafeOwner: req.IsCafeOwner,
+	}, pagination.Request{
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+		Sort:      sort,
+	})
+	if errors.Is(err, pagination.ErrInvalidPageToken) {
+		return nil, grpcerr.InvalidField("page_token", "%v", err)
+	}
+	if err != nil {
+		return nil, dberr.Error(err, "failed to get users")
 	}
 
 	protoUsers := make([]*userv1.User, len(users))
@@ -67,10 +153,69 @@ func (s *UserServer) GetUsers(ctx context.Context, req *userv1.GetUsersRequest)
 	}
 
 	return &userv1.GetUsersResponse{
-		Users: protoUsers,
+		Users:         protoUsers,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
+// updatableUserFields are the update_mask paths UpdateUser accepts
+var updatableUserFields = []string{"name", "email", "is_cafe_owner"}
+
+// UpdateUser updates the fields of a user named in the update mask
+func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.UpdateUserResponse, error) {
+	if req.User == nil {
+		return nil, grpcerr.InvalidField("user", "user is required")
+	}
+
+	paths, err := fieldmask.Paths(req.UpdateMask, updatableUserFields)
+	if err != nil {
+		return nil, grpcerr.InvalidField("update_mask", "%v", err)
+	}
+
+	user, err := s.Users.Get(ctx, uint(req.User.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, dberr.Error(err, "failed to get user")
+	}
+
+	// The mask paths are also the names of the columns they update
+	for _, path := range paths {
+		switch path {
+		case "name":
+			user.Name = req.User.Name
+		case "email":
+			user.Email = req.User.Email
+		case "is_cafe_owner":
+			user.IsCafeOwner = req.User.IsCafeOwner
+		}
+	}
+
+	if err := s.Users.Update(ctx, user, paths...); err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return nil, emailTakenError(req.User.Email)
+		}
+		return nil, dberr.Error(err, "failed to update user")
+	}
+
+	return &userv1.UpdateUserResponse{
+		User: modelToProto(user),
+	}, nil
+}
+
+// DeleteUser soft-deletes a user
+func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*userv1.DeleteUserResponse, error) {
+	if err := s.Users.Delete(ctx, uint(req.Id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, dberr.Error(err, "failed to delete user")
+	}
+
+	return &userv1.DeleteUserResponse{}, nil
+}
+
 // modelToProto converts a GORM User model to proto User message
 func modelToProto(user *models.User) *userv1.User {
 	return &userv1.User{
@@ -2,18 +2,30 @@ package grpc
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
-	"user-service/database"
 	"user-service/models"
+	"user-service/repository"
 
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+)
+
+// Callers of the tests that check authorization
+var (
+	cafeOwner = auth.Identity{UserID: 100, Role: auth.RoleCafeOwner}
+	student   = auth.Identity{UserID: 2, Role: auth.RoleStudent}
 )
 
 // setupTestDB creates an in-memory SQLite database for testing
@@ -39,12 +51,11 @@ func TestCreateUser(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
-
-	server := NewUserServer()
+	server := NewUserServer(repository.NewGormUserRepository(db))
 
 	tests := []struct {
 		name        string
+		caller      *auth.Identity
 		request     *userv1.CreateUserRequest
 		wantErr     bool
 		expectedMsg string
@@ -59,7 +70,8 @@ func TestCreateUser(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "create cafe owner",
+			name:   "cafe owners create cafe owners",
+			caller: &cafeOwner,
 			request: &userv1.CreateUserRequest{
 				Name:        "Jane Owner",
 				Email:       "jane@cafeshop.com",
@@ -67,6 +79,27 @@ func TestCreateUser(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "signing up as a cafe owner is rejected",
+			request: &userv1.CreateUserRequest{
+				Name:        "Mallory",
+				Email:       "mallory@example.com",
+				IsCafeOwner: true,
+			},
+			wantErr:     true,
+			expectedMsg: "only cafe owners may set is_cafe_owner",
+		},
+		{
+			name:   "students cannot create cafe owners",
+			caller: &student,
+			request: &userv1.CreateUserRequest{
+				Name:        "Mallory",
+				Email:       "mallory@example.com",
+				IsCafeOwner: true,
+			},
+			wantErr:     true,
+			expectedMsg: "only cafe owners may set is_cafe_owner",
+		},
 		{
 			name: "empty name should still work (validation is optional)",
 			request: &userv1.CreateUserRequest{
@@ -81,6 +114,9 @@ func TestCreateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
+			if tt.caller != nil {
+				ctx = auth.NewContext(ctx, *tt.caller)
+			}
 			resp, err := server.CreateUser(ctx, tt.request)
 
 			if tt.wantErr {
@@ -102,13 +138,26 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+func TestCreateUser_DuplicateEmail(t *testing.T) {
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
+	ctx := context.Background()
+
+	_, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	_, err = server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Johnny", Email: "john@example.com"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	assert.Equal(t, "a user with email john@example.com already exists", st.Message())
+}
+
 func TestGetUser(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
-
-	server := NewUserServer()
+	server := NewUserServer(repository.NewGormUserRepository(db))
 
 	// Create a test user
 	testUser := models.User{
@@ -167,13 +216,58 @@ func TestGetUser(t *testing.T) {
 	}
 }
 
-func TestGetUsers(t *testing.T) {
+func TestBatchGetUsers(t *testing.T) {
 	// Setup
 	db := setupTestDB(t)
 	defer teardownTestDB(t, db)
-	database.DB = db
+	server := NewUserServer(repository.NewGormUserRepository(db))
+
+	testUsers := []models.User{
+		{Name: "User 1", Email: "user1@example.com"},
+		{Name: "User 2", Email: "user2@example.com"},
+		{Name: "User 3", Email: "user3@example.com"},
+	}
+	require.NoError(t, db.Create(&testUsers).Error)
+	first, third := uint32(testUsers[0].ID), uint32(testUsers[2].ID)
+
+	ctx := auth.NewContext(context.Background(), cafeOwner)
+
+	// Users come back in request order with duplicates and missing IDs
+	// reported once
+	resp, err := server.BatchGetUsers(ctx, &userv1.BatchGetUsersRequest{
+		Ids: []uint32{third, 9999, first, third, 8888},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 2)
+	assert.Equal(t, "User 3", resp.Users[0].Name)
+	assert.Equal(t, "User 1", resp.Users[1].Name)
+	assert.Equal(t, []uint32{9999, 8888}, resp.MissingIds)
 
-	server := NewUserServer()
+	// Other users are missing to a student, without failing their own
+	asThird := auth.NewContext(context.Background(), auth.Identity{UserID: third, Role: auth.RoleStudent})
+	resp, err = server.BatchGetUsers(asThird, &userv1.BatchGetUsersRequest{
+		Ids: []uint32{first, third},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, third, resp.Users[0].Id)
+	assert.Equal(t, []uint32{first}, resp.MissingIds)
+
+	// Deleted users are missing
+	require.NoError(t, db.Delete(&testUsers[0]).Error)
+	resp, err = server.BatchGetUsers(ctx, &userv1.BatchGetUsersRequest{
+		Ids: []uint32{first},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Users)
+	assert.Equal(t, []uint32{first}, resp.MissingIds)
+}
+
+func TestGetUsers(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
 
 	// Test empty database
 	t.Run("empty database", func(t *testing.T) {
@@ -215,6 +309,307 @@ func TestGetUsers(t *testing.T) {
 	})
 }
 
+func TestGetUsers_PaginationAndFilters(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
+	ctx := context.Background()
+
+	testUsers := []models.User{
+		{Name: "Carol", Email: "carol@example.com", IsCafeOwner: false},
+		{Name: "Alice", Email: "alice@example.com", IsCafeOwner: true},
+		{Name: "Bob", Email: "bob@example.com", IsCafeOwner: false},
+		{Name: "Dave", Email: "dave@example.com", IsCafeOwner: true},
+		{Name: "Erin", Email: "erin@example.com", IsCafeOwner: false},
+	}
+	for _, user := range testUsers {
+		require.NoError(t, db.Create(&user).Error)
+	}
+
+	t.Run("pages through all users", func(t *testing.T) {
+		var names []string
+		req := &userv1.GetUsersRequest{PageSize: 2}
+		for {
+			resp, err := server.GetUsers(ctx, req)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(resp.Users), 2)
+			for _, user := range resp.Users {
+				names = append(names, user.Name)
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+		assert.Equal(t, []string{"Carol", "Alice", "Bob", "Dave", "Erin"}, names)
+	})
+
+	t.Run("filter by is_cafe_owner", func(t *testing.T) {
+		resp, err := server.GetUsers(ctx, &userv1.GetUsersRequest{IsCafeOwner: proto.Bool(true)})
+		require.NoError(t, err)
+		require.Len(t, resp.Users, 2)
+		for _, user := range resp.Users {
+			assert.True(t, user.IsCafeOwner)
+		}
+
+		resp, err = server.GetUsers(ctx, &userv1.GetUsersRequest{IsCafeOwner: proto.Bool(false)})
+		require.NoError(t, err)
+		assert.Len(t, resp.Users, 3)
+	})
+
+	t.Run("sort by name descending", func(t *testing.T) {
+		resp, err := server.GetUsers(ctx, &userv1.GetUsersRequest{OrderBy: "name desc", PageSize: 3})
+		require.NoError(t, err)
+		require.Len(t, resp.Users, 3)
+		assert.Equal(t, "Erin", resp.Users[0].Name)
+		assert.Equal(t, "Dave", resp.Users[1].Name)
+		assert.Equal(t, "Carol", resp.Users[2].Name)
+		assert.NotEmpty(t, resp.NextPageToken)
+
+		resp, err = server.GetUsers(ctx, &userv1.GetUsersRequest{OrderBy: "name desc", PageSize: 3, PageToken: resp.NextPageToken})
+		require.NoError(t, err)
+		require.Len(t, resp.Users, 2)
+		assert.Equal(t, "Bob", resp.Users[0].Name)
+		assert.Equal(t, "Alice", resp.Users[1].Name)
+		assert.Empty(t, resp.NextPageToken)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, req := range []*userv1.GetUsersRequest{
+			{OrderBy: "password"},
+			{PageToken: "garbage"},
+		} {
+			_, err := server.GetUsers(ctx, req)
+			require.Error(t, err)
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		}
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
+	ctx := context.Background()
+
+	created, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Jon Doe", Email: "jon@example.com"})
+	require.NoError(t, err)
+	id := created.User.Id
+
+	t.Run("mask limits the update", func(t *testing.T) {
+		resp, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			User:       &userv1.User{Id: id, Name: "John Doe", Email: "ignored@example.com"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "John Doe", resp.User.Name)
+		assert.Equal(t, "jon@example.com", resp.User.Email)
+
+		var dbUser models.User
+		require.NoError(t, db.First(&dbUser, id).Error)
+		assert.Equal(t, "John Doe", dbUser.Name)
+		assert.Equal(t, "jon@example.com", dbUser.Email)
+	})
+
+	t.Run("masked fields can be cleared", func(t *testing.T) {
+		_, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			User:       &userv1.User{Id: id, IsCafeOwner: true},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"is_cafe_owner"}},
+		})
+		require.NoError(t, err)
+
+		resp, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			User:       &userv1.User{Id: id, IsCafeOwner: false},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"is_cafe_owner"}},
+		})
+		require.NoError(t, err)
+		assert.False(t, resp.User.IsCafeOwner)
+	})
+
+	t.Run("empty mask replaces every field", func(t *testing.T) {
+		resp, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			User: &userv1.User{Id: id, Name: "Jane Doe", Email: "jane@example.com", IsCafeOwner: true},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Doe", resp.User.Name)
+		assert.Equal(t, "jane@example.com", resp.User.Email)
+		assert.True(t, resp.User.IsCafeOwner)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		other, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Other", Email: "other@example.com"})
+		require.NoError(t, err)
+
+		tests := []struct {
+			name string
+			req  *userv1.UpdateUserRequest
+			code codes.Code
+		}{
+			{"missing user", &userv1.UpdateUserRequest{}, codes.InvalidArgument},
+			{"unknown path", &userv1.UpdateUserRequest{
+				User:       &userv1.User{Id: id},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"created_at"}},
+			}, codes.InvalidArgument},
+			{"unknown user", &userv1.UpdateUserRequest{
+				User:       &userv1.User{Id: 999, Name: "Nobody"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+			}, codes.NotFound},
+			{"email taken", &userv1.UpdateUserRequest{
+				User:       &userv1.User{Id: id, Email: other.User.Email},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}},
+			}, codes.AlreadyExists},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := server.UpdateUser(ctx, tt.req)
+				assert.Equal(t, tt.code, status.Code(err))
+			})
+		}
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
+	ctx := context.Background()
+
+	created, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Test User", Email: "test@example.com"})
+	require.NoError(t, err)
+	id := created.User.Id
+
+	_, err = server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: id})
+	require.NoError(t, err)
+
+	// The user is hidden from reads but the row is kept
+	_, err = server.GetUser(ctx, &userv1.GetUserRequest{Id: id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := server.GetUsers(ctx, &userv1.GetUsersRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Users)
+
+	var deleted models.User
+	require.NoError(t, db.Unscoped().First(&deleted, id).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+
+	// Deleting twice reports the user as missing
+	_, err = server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// newTestTokenIssuer loads a freshly generated signing key from a temporary
+// file, the same way main loads JWT_PRIVATE_KEY_FILE
+func newTestTokenIssuer(t *testing.T) *auth.TokenIssuer {
+	dir := t.TempDir()
+	privateKeyPath := filepath.Join(dir, "jwt-private.pem")
+	require.NoError(t, auth.WriteKeyPair(privateKeyPath, filepath.Join(dir, "jwt-public.pem")))
+
+	key, err := auth.LoadPrivateKey(privateKeyPath)
+	require.NoError(t, err)
+	return auth.NewTokenIssuer(key)
+}
+
+func TestLogin(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
+	server.Tokens = newTestTokenIssuer(t)
+	ctx := context.Background()
+
+	created, err := server.CreateUser(auth.NewContext(ctx, cafeOwner), &userv1.CreateUserRequest{
+		Name:        "Jane Owner",
+		Email:       "jane@cafeshop.com",
+		IsCafeOwner: true,
+		Password:    "correct horse",
+	})
+	require.NoError(t, err)
+
+	// Only the hash is stored
+	var stored models.User
+	require.NoError(t, db.First(&stored, created.User.Id).Error)
+	assert.NotEmpty(t, stored.PasswordHash)
+	assert.NotEqual(t, "correct horse", stored.PasswordHash)
+
+	t.Run("valid credentials return tokens for the user", func(t *testing.T) {
+		resp, err := server.Login(ctx, &userv1.LoginRequest{Email: "jane@cafeshop.com", Password: "correct horse"})
+		require.NoError(t, err)
+		assert.Equal(t, created.User.Id, resp.User.Id)
+		assert.Equal(t, "Bearer", resp.Tokens.TokenType)
+		assert.Equal(t, int64(auth.DefaultAccessTokenTTL.Seconds()), resp.Tokens.ExpiresIn)
+
+		identity, err := server.Tokens.Verifier().Verify(resp.Tokens.AccessToken, auth.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, auth.Identity{UserID: created.User.Id, Role: auth.RoleCafeOwner}, identity)
+	})
+
+	t.Run("wrong password and unknown email are rejected alike", func(t *testing.T) {
+		_, err := server.Login(ctx, &userv1.LoginRequest{Email: "jane@cafeshop.com", Password: "wrong password"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = server.Login(ctx, &userv1.LoginRequest{Email: "nobody@example.com", Password: "correct horse"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("users without a password cannot log in", func(t *testing.T) {
+		_, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "No Password", Email: "nopass@example.com"})
+		require.NoError(t, err)
+
+		_, err = server.Login(ctx, &userv1.LoginRequest{Email: "nopass@example.com"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("short passwords are rejected", func(t *testing.T) {
+		_, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Name: "Short", Email: "short@example.com", Password: "abc"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	// Setup
+	db := setupTestDB(t)
+	defer teardownTestDB(t, db)
+	server := NewUserServer(repository.NewGormUserRepository(db))
+	server.Tokens = newTestTokenIssuer(t)
+	ctx := context.Background()
+
+	created, err := server.CreateUser(ctx, &userv1.CreateUserRequest{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: "correct horse",
+	})
+	require.NoError(t, err)
+
+	login, err := server.Login(ctx, &userv1.LoginRequest{Email: "john@example.com", Password: "correct horse"})
+	require.NoError(t, err)
+
+	// Role changes are picked up on refresh
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", created.User.Id).Update("is_cafe_owner", true).Error)
+
+	resp, err := server.RefreshToken(ctx, &userv1.RefreshTokenRequest{RefreshToken: login.Tokens.RefreshToken})
+	require.NoError(t, err)
+	identity, err := server.Tokens.Verifier().Verify(resp.Tokens.AccessToken, auth.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleCafeOwner, identity.Role)
+
+	// Access tokens cannot be used as refresh tokens
+	_, err = server.RefreshToken(ctx, &userv1.RefreshTokenRequest{RefreshToken: login.Tokens.AccessToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Deleted users cannot refresh
+	_, err = server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.User.Id})
+	require.NoError(t, err)
+	_, err = server.RefreshToken(ctx, &userv1.RefreshTokenRequest{RefreshToken: login.Tokens.RefreshToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
 func TestModelToProto(t *testing.T) {
 	now := time.Now()
 	user := &models.User{
@@ -237,3 +632,88 @@ func TestModelToProto(t *testing.T) {
 	assert.Equal(t, now.Format(time.RFC3339), protoUser.CreatedAt)
 	assert.Equal(t, now.Format(time.RFC3339), protoUser.UpdatedAt)
 }
+
+func TestPolicy(t *testing.T) {
+	interceptor := authz.UnaryServerInterceptor(Policy())
+	call := func(ctx context.Context, method string, req any) codes.Code {
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return status.Code(err)
+	}
+
+	owner := auth.NewContext(context.Background(), cafeOwner)
+	asStudent := auth.NewContext(context.Background(), student)
+	anonymous := context.Background()
+
+	t.Run("users read their own account only", func(t *testing.T) {
+		assert.Equal(t, codes.OK, call(asStudent, userv1.UserService_GetUser_FullMethodName, &userv1.GetUserRequest{Id: 2}))
+		assert.Equal(t, codes.PermissionDenied, call(asStudent, userv1.UserService_GetUser_FullMethodName, &userv1.GetUserRequest{Id: 3}))
+		assert.Equal(t, codes.OK, call(owner, userv1.UserService_GetUser_FullMethodName, &userv1.GetUserRequest{Id: 3}))
+		assert.Equal(t, codes.Unauthenticated, call(anonymous, userv1.UserService_GetUser_FullMethodName, &userv1.GetUserRequest{Id: 2}))
+
+		// BatchGetUsers leaves other users out rather than failing
+		assert.Equal(t, codes.OK, call(asStudent, userv1.UserService_BatchGetUsers_FullMethodName, &userv1.BatchGetUsersRequest{Ids: []uint32{2, 3}}))
+		assert.Equal(t, codes.OK, call(owner, userv1.UserService_BatchGetUsers_FullMethodName, &userv1.BatchGetUsersRequest{Ids: []uint32{2, 3}}))
+		assert.Equal(t, codes.Unauthenticated, call(anonymous, userv1.UserService_BatchGetUsers_FullMethodName, &userv1.BatchGetUsersRequest{Ids: []uint32{2}}))
+	})
+
+	t.Run("only cafe owners list and delete users", func(t *testing.T) {
+		for _, tc := range []struct {
+			method string
+			req    any
+		}{
+			{userv1.UserService_GetUsers_FullMethodName, &userv1.GetUsersRequest{}},
+			{userv1.UserService_DeleteUser_FullMethodName, &userv1.DeleteUserRequest{Id: 2}},
+		} {
+			assert.Equal(t, codes.OK, call(owner, tc.method, tc.req), tc.method)
+			assert.Equal(t, codes.PermissionDenied, call(asStudent, tc.method, tc.req), tc.method)
+			assert.Equal(t, codes.Unauthenticated, call(anonymous, tc.method, tc.req), tc.method)
+		}
+	})
+
+	t.Run("users update their own name and email", func(t *testing.T) {
+		own := &userv1.UpdateUserRequest{
+			User:       &userv1.User{Id: 2, Name: "Renamed"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "email"}},
+		}
+		assert.Equal(t, codes.OK, call(asStudent, userv1.UserService_UpdateUser_FullMethodName, own))
+
+		replace := &userv1.UpdateUserRequest{User: &userv1.User{Id: 2, Name: "Renamed"}}
+		assert.Equal(t, codes.OK, call(asStudent, userv1.UserService_UpdateUser_FullMethodName, replace))
+
+		other := &userv1.UpdateUserRequest{
+			User:       &userv1.User{Id: 3, Name: "Renamed"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+		}
+		assert.Equal(t, codes.PermissionDenied, call(asStudent, userv1.UserService_UpdateUser_FullMethodName, other))
+		assert.Equal(t, codes.OK, call(owner, userv1.UserService_UpdateUser_FullMethodName, other))
+	})
+
+	t.Run("only cafe owners change is_cafe_owner", func(t *testing.T) {
+		for _, req := range []*userv1.UpdateUserRequest{
+			{
+				User:       &userv1.User{Id: 2, IsCafeOwner: true},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"is_cafe_owner"}},
+			},
+			{
+				User:       &userv1.User{Id: 2, IsCafeOwner: false},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "is_cafe_owner"}},
+			},
+			// An empty mask replaces every field
+			{User: &userv1.User{Id: 2, Name: "Renamed", IsCafeOwner: true}},
+		} {
+			assert.Equal(t, codes.PermissionDenied, call(asStudent, userv1.UserService_UpdateUser_FullMethodName, req))
+			assert.Equal(t, codes.OK, call(owner, userv1.UserService_UpdateUser_FullMethodName, req))
+		}
+	})
+
+	t.Run("sign-up and login are public", func(t *testing.T) {
+		for _, method := range []string{
+			userv1.UserService_CreateUser_FullMethodName,
+			userv1.UserService_Login_FullMethodName,
+			userv1.UserService_RefreshToken_FullMethodName,
+		} {
+			assert.Equal(t, codes.OK, call(anonymous, method, nil), method)
+		}
+	})
+}
@@ -1,26 +1,129 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"user-service/database"
 	grpcserver "user-service/grpc"
+	"user-service/repository"
 
 	userv1 "github.com/douglasswm/student-cafe-protos/gen/go/user/v1"
 	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	"student-cafe-shared/auth"
+	"student-cafe-shared/authz"
+	"student-cafe-shared/health"
+	"student-cafe-shared/logging"
+	"student-cafe-shared/metrics"
+	"student-cafe-shared/migrate"
+	"student-cafe-shared/mtls"
+	"student-cafe-shared/shutdown"
+	"student-cafe-shared/tracing"
+	"student-cafe-shared/validation"
 )
 
 func main() {
+	// Log JSON lines to stdout
+	if err := logging.Setup("user-service"); err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
 	// Connect to dedicated user database
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
 		dsn = "host=localhost user=postgres password=postgres dbname=user_db port=5432 sslmode=disable"
 	}
 
-	if err := database.Connect(dsn); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	// "user-service migrate <command>" manages the schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		cmd := migrate.Command{
+			Migrations: database.Migrations(),
+			Dir:        database.MigrationsDir,
+			Open:       func() (*gorm.DB, error) { return database.Open(dsn) },
+			Out:        os.Stdout,
+		}
+		if err := cmd.Run(context.Background(), os.Args[2:]); err != nil {
+			logging.Fatal("Migration failed", "error", err)
+		}
+		return
+	}
+
+	// "user-service grant-owner <email>" makes an existing user a cafe owner.
+	// Only cafe owners may do so through the API, so this creates the first.
+	if len(os.Args) > 1 && os.Args[1] == "grant-owner" {
+		if len(os.Args) != 3 {
+			logging.Fatal("usage: grant-owner <email>")
+		}
+		db, err := database.Connect(dsn)
+		if err != nil {
+			logging.Fatal("Failed to connect to database", "error", err)
+		}
+		if err := grantOwner(context.Background(), repository.NewGormUserRepository(db), os.Args[2]); err != nil {
+			logging.Fatal("Failed to grant cafe owner", "email", os.Args[2], "error", err)
+		}
+		slog.Info("Granted cafe owner", "email", os.Args[2])
+		return
+	}
+
+	// SIGTERM (or Ctrl-C) starts a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// How long in-flight RPCs may run after shutdown starts (e.g. "30s")
+	drainTimeout, err := shutdown.Timeout()
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
+	// Export spans to the backend named by OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(ctx, "user-service")
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", "error", err)
+	}
+
+	db, err := database.Connect(dsn)
+	if err != nil {
+		logging.Fatal("Failed to connect to database", "error", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		logging.Fatal("Failed to get database pool", "error", err)
+	}
+	if err := metrics.DBStats(sqlDB, "user_db"); err != nil {
+		logging.Fatal("Failed to register database metrics", "error", err)
+	}
+
+	// Load the key used to sign access and refresh tokens
+	keyFile := os.Getenv("JWT_PRIVATE_KEY_FILE")
+	if keyFile == "" {
+		logging.Fatal("JWT_PRIVATE_KEY_FILE must be set (run 'make jwt-keys' to generate a key pair)")
+	}
+	signingKey, err := auth.LoadPrivateKey(keyFile)
+	if err != nil {
+		logging.Fatal("Failed to load JWT signing key", "error", err)
+	}
+	tokens := auth.NewTokenIssuer(signingKey)
+
+	// Token lifetimes (e.g. "15m", "168h")
+	if ttl := os.Getenv("JWT_ACCESS_TOKEN_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			logging.Fatal("Invalid JWT_ACCESS_TOKEN_TTL", "value", ttl, "error", err)
+		}
+		tokens.AccessTokenTTL = d
+	}
+	if ttl := os.Getenv("JWT_REFRESH_TOKEN_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			logging.Fatal("Invalid JWT_REFRESH_TOKEN_TTL", "value", ttl, "error", err)
+		}
+		tokens.RefreshTokenTTL = d
 	}
 
 	// Get gRPC port from environment
@@ -29,18 +132,91 @@ func main() {
 		grpcPort = "9091"
 	}
 
+	// Get metrics port from environment
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9101"
+	}
+
 	// Start listening on TCP port
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
 	if err != nil {
-		log.Fatalf("Failed to listen on gRPC port %s: %v", grpcPort, err)
+		logging.Fatal("Failed to listen on gRPC port", "port", grpcPort, "error", err)
+	}
+
+	// Serve TLS, or mutual TLS when a CA is set, with the files named by
+	// TLS_CERT_FILE, TLS_KEY_FILE and TLS_CA_FILE
+	creds, err := mtls.ServerOption()
+	if err != nil {
+		logging.Fatal("Invalid TLS configuration", "error", err)
+	}
+
+	// Create and register gRPC server; every RPC is traced and measured,
+	// and the interceptors read the caller identity forwarded by the
+	// api-gateway, validate requests and enforce the authorization policy
+	s := grpc.NewServer(
+		creds,
+		tracing.ServerOption(),
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(),
+			validation.UnaryServerInterceptor(),
+			authz.UnaryServerInterceptor(grpcserver.Policy()),
+		),
+	)
+	userServer := grpcserver.NewUserServer(repository.NewGormUserRepository(db))
+	userServer.Tokens = tokens
+	userv1.RegisterUserServiceServer(s, userServer)
+
+	// Publish grpc.health.v1 status; the service is only SERVING while its
+	// dependencies are reachable
+	checker := health.NewChecker(userv1.UserService_ServiceDesc.ServiceName)
+	checker.Add("database", health.Database(sqlDB))
+	checker.Register(s)
+	go checker.Run(ctx)
+
+	// Serve Prometheus metrics on their own port until shutdown
+	go func() {
+		if err := metrics.Serve(ctx, metrics.NewServer(":"+metricsPort)); err != nil {
+			logging.Fatal("Metrics server failed", "error", err)
+		}
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.Serve(lis) }()
+	slog.Info("User service (gRPC only) starting", "grpc_port", grpcPort, "metrics_port", metricsPort)
+
+	select {
+	case err := <-serveErr:
+		logging.Fatal("gRPC server failed", "error", err)
+	case <-ctx.Done():
+		stop() // a second signal kills the process immediately
 	}
 
-	// Create and register gRPC server
-	s := grpc.NewServer()
-	userv1.RegisterUserServiceServer(s, grpcserver.NewUserServer())
+	// Stop advertising the service, then let in-flight RPCs finish
+	slog.Info("Shutting down, draining in-flight RPCs", "timeout", drainTimeout.String())
+	checker.Shutdown()
+	if !shutdown.GracefulStop(s, drainTimeout) {
+		slog.Warn("Drain timeout exceeded, cancelled the remaining RPCs")
+	}
+	if err := sqlDB.Close(); err != nil {
+		slog.Error("Failed to close database", "error", err)
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+	slog.Info("User service stopped")
+}
 
-	log.Printf("User service (gRPC only) starting on :%s", grpcPort)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("gRPC server failed: %v", err)
+// grantOwner sets is_cafe_owner on the user with email
+func grantOwner(ctx context.Context, users repository.UserRepository, email string) error {
+	user, err := users.GetByEmail(ctx, email)
+	if err != nil {
+		return err
 	}
+	user.IsCafeOwner = true
+	return users.Update(ctx, user, "is_cafe_owner")
 }
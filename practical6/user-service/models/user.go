@@ -4,7 +4,8 @@ import "gorm.io/gorm"
 
 type User struct {
 	gorm.Model
-	Name       string `json:"name"`
-	Email      string `json:"email" gorm:"unique"`
-	IsCafeOwner bool   `json:"is_cafe_owner"`
+	Name         string `json:"name"`
+	Email        string `json:"email" gorm:"unique"`
+	IsCafeOwner  bool   `json:"is_cafe_owner"`
+	PasswordHash string `json:"-"` // bcrypt hash; empty for users who cannot log in
 }
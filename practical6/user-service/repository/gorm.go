@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"student-cafe-shared/pagination"
+	"user-service/models"
+)
+
+// GormUserRepository stores users in a SQL database through GORM
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository returns a repository backed by db, which must
+// already be migrated
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+// Create saves a new user
+func (r *GormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// Get returns the user with the given ID
+func (r *GormUserRepository) Get(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByIDs returns the users with the given IDs ordered by ID
+func (r *GormUserRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	users := []models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetByEmail returns the user with the given email
+func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// List returns a page of users matching filter
+func (r *GormUserRepository) List(ctx context.Context, filter UserFilter, page pagination.Request) ([]models.User, string, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{})
+	if filter.IsCafeOwner != nil {
+		query = query.Where("is_cafe_owner = ?", *filter.IsCafeOwner)
+	}
+	return pagination.Find(query, page, sortKey(page.Sort))
+}
+
+// Update saves the named columns of an existing user
+func (r *GormUserRepository) Update(ctx context.Context, user *models.User, columns ...string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).Model(user).Select(columns).Updates(user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes the user with the given ID
+func (r *GormUserRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"student-cafe-shared/pagination"
+	"user-service/models"
+)
+
+// MemoryUserRepository stores users in memory. It is safe for concurrent
+// use and the zero value is not usable; create one with
+// NewMemoryUserRepository.
+type MemoryUserRepository struct {
+	mu     sync.RWMutex
+	users  map[uint]models.User
+	nextID uint
+}
+
+// NewMemoryUserRepository returns an empty in-memory repository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: make(map[uint]models.User)}
+}
+
+// Create saves a new user
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(user.Email, 0) {
+		return gorm.ErrDuplicatedKey
+	}
+
+	r.nextID++
+	user.ID = r.nextID
+	setTimestamps(&user.Model)
+	r.users[user.ID] = *user
+	return nil
+}
+
+// emailTaken reports whether a user other than id has email. Like the
+// unique index it mirrors, deleted users keep their email.
+func (r *MemoryUserRepository) emailTaken(email string, id uint) bool {
+	for _, user := range r.users {
+		if user.Email == email && user.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the user with the given ID
+func (r *MemoryUserRepository) Get(ctx context.Context, id uint) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+// GetByIDs returns the users with the given IDs ordered by ID
+func (r *MemoryUserRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []models.User{}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user, ok := r.users[id]; ok && !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return cmp.Compare(a.ID, b.ID) })
+	return users, nil
+}
+
+// GetByEmail returns the user with the given email
+func (r *MemoryUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns a page of users matching filter
+func (r *MemoryUserRepository) List(ctx context.Context, filter UserFilter, page pagination.Request) ([]models.User, string, error) {
+	r.mu.RLock()
+	users := make([]models.User, 0, len(r.users))
+	for _, user := range r.users {
+		if user.DeletedAt.Valid {
+			continue
+		}
+		if filter.IsCafeOwner != nil && user.IsCafeOwner != *filter.IsCafeOwner {
+			continue
+		}
+		users = append(users, user)
+	}
+	r.mu.RUnlock()
+
+	return pagination.Slice(users, page, sortKey(page.Sort))
+}
+
+// Update saves the named columns of an existing user
+func (r *MemoryUserRepository) Update(ctx context.Context, user *models.User, columns ...string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+
+	for _, column := range columns {
+		switch column {
+		case "name":
+			stored.Name = user.Name
+		case "email":
+			if r.emailTaken(user.Email, user.ID) {
+				return gorm.ErrDuplicatedKey
+			}
+			stored.Email = user.Email
+		case "is_cafe_owner":
+			stored.IsCafeOwner = user.IsCafeOwner
+		case "password_hash":
+			stored.PasswordHash = user.PasswordHash
+		default:
+			return fmt.Errorf("cannot update column %q", column)
+		}
+	}
+
+	stored.UpdatedAt = time.Now()
+	user.UpdatedAt = stored.UpdatedAt
+	r.users[user.ID] = stored
+	return nil
+}
+
+// Delete soft-deletes the user with the given ID
+func (r *MemoryUserRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.users[id] = user
+	return nil
+}
+
+// setTimestamps fills in unset creation and update times, as GORM does
+func setTimestamps(model *gorm.Model) {
+	now := time.Now()
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = now
+	}
+}
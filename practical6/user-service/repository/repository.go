@@ -0,0 +1,64 @@
+// Package repository stores the user service's users.
+//
+// UserServer depends on the UserRepository interface rather than on a
+// database handle, so each server owns its storage: GormUserRepository
+// backs the service in production and MemoryUserRepository keeps users in
+// memory for tests. Both report missing rows with gorm.ErrRecordNotFound and
+// duplicate emails with a unique violation, so callers can classify errors
+// with dberr.
+package repository
+
+import (
+	"context"
+
+	"student-cafe-shared/pagination"
+	"user-service/models"
+)
+
+// UserRepository stores users
+type UserRepository interface {
+	// Create saves a new user, setting its ID and timestamps
+	Create(ctx context.Context, user *models.User) error
+	// Get returns the user with the given ID
+	Get(ctx context.Context, id uint) (*models.User, error)
+	// GetByIDs returns the users with the given IDs ordered by ID, skipping
+	// IDs that do not exist
+	GetByIDs(ctx context.Context, ids []uint) ([]models.User, error)
+	// GetByEmail returns the user with the given email
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// List returns a page of users matching filter and the next page token
+	List(ctx context.Context, filter UserFilter, page pagination.Request) ([]models.User, string, error)
+	// Update saves the named columns of an existing user
+	Update(ctx context.Context, user *models.User, columns ...string) error
+	// Delete soft-deletes the user with the given ID
+	Delete(ctx context.Context, id uint) error
+}
+
+// UserFilter narrows the users returned by List
+type UserFilter struct {
+	IsCafeOwner *bool
+}
+
+// SortFields maps the order_by fields users can be listed by to columns
+var SortFields = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"email":      "email",
+	"created_at": "created_at",
+}
+
+// sortKey returns the pagination key for a sort order
+func sortKey(sort pagination.Sort) pagination.Key[models.User] {
+	return func(user *models.User) (any, uint) {
+		switch sort.Field {
+		case "name":
+			return user.Name, user.ID
+		case "email":
+			return user.Email, user.ID
+		case "created_at":
+			return user.CreatedAt, user.ID
+		default:
+			return user.ID, user.ID
+		}
+	}
+}
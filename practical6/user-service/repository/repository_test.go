@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"student-cafe-shared/dberr"
+	"student-cafe-shared/pagination"
+	"user-service/models"
+)
+
+// implementations returns a fresh instance of every UserRepository
+func implementations(t *testing.T) map[string]UserRepository {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+
+	return map[string]UserRepository{
+		"gorm":   NewGormUserRepository(db),
+		"memory": NewMemoryUserRepository(),
+	}
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+
+	for name, repo := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			alice := &models.User{Name: "Alice", Email: "alice@example.com"}
+			require.NoError(t, repo.Create(ctx, alice))
+			assert.NotZero(t, alice.ID)
+			assert.False(t, alice.CreatedAt.IsZero())
+
+			owner := &models.User{Name: "Olive", Email: "olive@example.com", IsCafeOwner: true}
+			require.NoError(t, repo.Create(ctx, owner))
+
+			t.Run("get", func(t *testing.T) {
+				got, err := repo.Get(ctx, alice.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "alice@example.com", got.Email)
+
+				got, err = repo.GetByEmail(ctx, "olive@example.com")
+				require.NoError(t, err)
+				assert.Equal(t, owner.ID, got.ID)
+
+				_, err = repo.Get(ctx, 9999)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				_, err = repo.GetByEmail(ctx, "nobody@example.com")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("get by IDs", func(t *testing.T) {
+				users, err := repo.GetByIDs(ctx, []uint{owner.ID, 9999, alice.ID, owner.ID})
+				require.NoError(t, err)
+				require.Len(t, users, 2)
+				assert.Equal(t, alice.ID, users[0].ID)
+				assert.Equal(t, owner.ID, users[1].ID)
+
+				users, err = repo.GetByIDs(ctx, nil)
+				require.NoError(t, err)
+				assert.Empty(t, users)
+			})
+
+			t.Run("duplicate email", func(t *testing.T) {
+				err := repo.Create(ctx, &models.User{Name: "Alice Again", Email: "alice@example.com"})
+				assert.True(t, dberr.IsUniqueViolation(err), "got %v", err)
+			})
+
+			t.Run("list", func(t *testing.T) {
+				byName := pagination.Sort{Field: "name", Column: "name"}
+				users, next, err := repo.List(ctx, UserFilter{}, pagination.Request{PageSize: 1, Sort: byName})
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				assert.Equal(t, "Alice", users[0].Name)
+				require.NotEmpty(t, next)
+
+				users, next, err = repo.List(ctx, UserFilter{}, pagination.Request{PageSize: 1, PageToken: next, Sort: byName})
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				assert.Equal(t, "Olive", users[0].Name)
+				assert.Empty(t, next)
+
+				isOwner := true
+				users, _, err = repo.List(ctx, UserFilter{IsCafeOwner: &isOwner}, pagination.Request{Sort: byName})
+				require.NoError(t, err)
+				require.Len(t, users, 1)
+				assert.Equal(t, owner.ID, users[0].ID)
+			})
+
+			t.Run("update only the named columns", func(t *testing.T) {
+				user, err := repo.Get(ctx, alice.ID)
+				require.NoError(t, err)
+				user.Name = "Alice Smith"
+				user.IsCafeOwner = true
+				require.NoError(t, repo.Update(ctx, user, "name"))
+
+				got, err := repo.Get(ctx, alice.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "Alice Smith", got.Name)
+				assert.False(t, got.IsCafeOwner)
+
+				user.Email = "olive@example.com"
+				err = repo.Update(ctx, user, "email")
+				assert.True(t, dberr.IsUniqueViolation(err), "got %v", err)
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				require.NoError(t, repo.Delete(ctx, owner.ID))
+				_, err := repo.Get(ctx, owner.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, repo.Delete(ctx, owner.ID), gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, repo.Update(ctx, owner, "name"), gorm.ErrRecordNotFound)
+				users, err := repo.GetByIDs(ctx, []uint{owner.ID})
+				require.NoError(t, err)
+				assert.Empty(t, users)
+			})
+		})
+	}
+}